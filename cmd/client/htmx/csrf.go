@@ -0,0 +1,55 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+// CSRF is a http.Handler middleware that passes the CSRF token a page sends on to the
+// server, which refuses requests that change something without it. htmx sends the
+// token in the X-CSRF-Token header of every request, and forms posted without htmx in
+// their csrf_token field. Pages rendered for other safe requests embed the token of
+// the session, fetched from the server; unsafe requests never get one they did not
+// bring, or the proxy would vouch for forms posted by other sites.
+func CSRF(fetch func(ctx context.Context) (string, error)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(domain.CSRFHeader)
+			switch {
+			case token != "":
+			case !safeMethod(r.Method):
+				token = formToken(w, r)
+			case !isHTMX(r):
+				var err error
+				var unauthenticatedErr domain.ErrUnauthenticated
+				if token, err = fetch(r.Context()); err != nil && !errors.As(err, &unauthenticatedErr) {
+					log.Ctx(r.Context()).Warn().Err(err).Msg("failed to fetch the CSRF token")
+				}
+			}
+
+			if token != "" {
+				r = r.WithContext(domain.ContextWithCSRFToken(r.Context(), token))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// formToken returns the token of a form posted without htmx; the form is parsed with
+// the limit of the largest form, the import of a file
+func formToken(w http.ResponseWriter, r *http.Request) string {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	return r.PostFormValue(domain.CSRFField)
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/pages"
+)
+
+func TestCSRF(t *testing.T) {
+	const token = "session-csrf-token"
+	tests := map[string]struct {
+		request    func() *http.Request
+		fetchErr   error
+		wantFetch  bool
+		wantToken  string
+		wantInPage bool
+	}{
+		"Page": {
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			wantFetch:  true,
+			wantToken:  token,
+			wantInPage: true,
+		},
+		"SignedOut": {
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/login", nil)
+			},
+			fetchErr:  domain.ErrUnauthenticated{},
+			wantFetch: true,
+		},
+		"HTMX": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader("description=cake"))
+				req.Header.Set("HX-Request", "true")
+				req.Header.Set(domain.CSRFHeader, token)
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			wantToken:  token,
+			wantInPage: true,
+		},
+		"Form": {
+			request: func() *http.Request {
+				form := url.Values{"description": {"cake"}, domain.CSRFField: {token}}
+				req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			wantToken:  token,
+			wantInPage: true,
+		},
+		"Forged": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader("description=cake"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var fetched bool
+			fetch := func(context.Context) (string, error) {
+				fetched = true
+				if tt.fetchErr != nil {
+					return "", tt.fetchErr
+				}
+				return token, nil
+			}
+			var gotToken string
+			handler := CSRF(fetch)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotToken = domain.CSRFTokenFromContext(r.Context())
+				if r.Method == http.MethodPost && r.FormValue("description") != "cake" {
+					t.Errorf("FormValue(description) = %q, want %q", r.FormValue("description"), "cake")
+				}
+				_ = pages.HomePage(nil, nil).Render(r.Context(), w)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.request())
+
+			if fetched != tt.wantFetch {
+				t.Errorf("fetched = %v, want %v", fetched, tt.wantFetch)
+			}
+			if gotToken != tt.wantToken {
+				t.Errorf("token = %q, want %q", gotToken, tt.wantToken)
+			}
+			body := rec.Body.String()
+			inHeaders := strings.Contains(body, `hx-headers="{&#34;`+domain.CSRFHeader+`&#34;:&#34;`+token+`&#34;}"`)
+			inForms := strings.Contains(body, `name="`+domain.CSRFField+`" value="`+token+`"`)
+			if inHeaders != tt.wantInPage || inForms != tt.wantInPage {
+				t.Errorf("token in hx-headers = %v and forms = %v, want %v", inHeaders, inForms, tt.wantInPage)
+			}
+		})
+	}
+}
+
+func Test_serviceError_CSRF(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serviceError(rec, httptest.NewRequest(http.MethodPost, "/todos", nil), domain.ErrInvalidCSRFToken{}, "failed to add todo")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("StatusCode = %v, want %v", rec.Code, http.StatusForbidden)
+	}
+}
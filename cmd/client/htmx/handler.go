@@ -1,15 +1,22 @@
 package htmx
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"path"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
 	"github.com/stackus/todos-htmx-wasm/internal/features/home"
 	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
 	"github.com/stackus/todos-htmx-wasm/internal/templates/pages"
 	"github.com/stackus/todos-htmx-wasm/internal/templates/partials"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
 type (
@@ -30,23 +37,60 @@ type (
 		Delete(w http.ResponseWriter, r *http.Request)
 		// Sort : POST /todos/sort
 		Sort(w http.ResponseWriter, r *http.Request)
+		// Export : GET /todos/export
+		Export(w http.ResponseWriter, r *http.Request)
+		// Import : POST /todos/import
+		Import(w http.ResponseWriter, r *http.Request)
+		// LoginPage : GET /login
+		LoginPage(w http.ResponseWriter, r *http.Request)
+		// Login : POST /login
+		Login(w http.ResponseWriter, r *http.Request)
+		// Register : POST /register
+		Register(w http.ResponseWriter, r *http.Request)
+		// Logout : POST /logout
+		Logout(w http.ResponseWriter, r *http.Request)
+		// Sharing : GET /sharing
+		Sharing(w http.ResponseWriter, r *http.Request)
+		// Invite : POST /sharing
+		Invite(w http.ResponseWriter, r *http.Request)
+		// RemoveMember : POST /sharing/{userId}/remove
+		RemoveMember(w http.ResponseWriter, r *http.Request)
+		// UpdateBanner : GET /update
+		UpdateBanner(w http.ResponseWriter, r *http.Request)
 	}
 
 	handler struct {
 		homeSvc  home.Service
 		todosSvc todos.Service
+		accounts domain.Accounts
+		sharing  domain.Sharing
 	}
 )
 
-func NewHandler(homeSvc home.Service, todosSvc todos.Service) Handler {
+// maxImportSize is the largest list of todos that may be uploaded
+const maxImportSize = 10 << 20
+
+func NewHandler(homeSvc home.Service, todosSvc todos.Service, accounts domain.Accounts, sharing domain.Sharing) Handler {
 	return &handler{
 		homeSvc:  homeSvc,
 		todosSvc: todosSvc,
+		accounts: accounts,
+		sharing:  sharing,
 	}
 }
 
 func Mount(r chi.Router, h Handler) {
 	r.Get("/", h.Home)
+	r.Get("/login", h.LoginPage)
+	r.Post("/login", h.Login)
+	r.Post("/register", h.Register)
+	r.Post("/logout", h.Logout)
+	r.Get("/update", h.UpdateBanner)
+	r.Route("/sharing", func(r chi.Router) {
+		r.Get("/", h.Sharing)
+		r.Post("/", h.Invite)
+		r.Post("/{userId}/remove", h.RemoveMember)
+	})
 	r.Route("/todos", func(r chi.Router) {
 		r.Get("/", h.Search)
 		r.Post("/", h.Create)
@@ -58,17 +102,33 @@ func Mount(r chi.Router, h Handler) {
 			r.Post("/delete", h.Delete)
 		})
 		r.Post("/sort", h.Sort)
+		r.Get("/export", h.Export)
+		r.Post("/import", h.Import)
 	})
 }
 
 func (h handler) Home(w http.ResponseWriter, r *http.Request) {
 	list, err := h.homeSvc.List(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to list todos")
 		return
 	}
+	lists, err := h.sharing.Lists(r.Context())
+	if err != nil {
+		serviceError(w, r, err, "failed to list lists")
+		return
+	}
+
+	if err := pages.HomePage(list, lists).Render(r.Context(), w); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
 
-	if err := pages.HomePage(list).Render(r.Context(), w); err != nil {
+// UpdateBanner renders the banner VersionCheck chose, which pages poll for
+func (h handler) UpdateBanner(w http.ResponseWriter, r *http.Request) {
+	if err := shared.VersionBanner().Render(r.Context(), w); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -89,7 +149,7 @@ func (h handler) Sort(w http.ResponseWriter, r *http.Request) {
 		todoIDs = append(todoIDs, todoID)
 	}
 	if _, err := h.todosSvc.Sort(r.Context(), todoIDs); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to sort todos")
 		return
 	}
 
@@ -97,7 +157,7 @@ func (h handler) Sort(w http.ResponseWriter, r *http.Request) {
 	case true:
 		w.WriteHeader(http.StatusNoContent)
 	default:
-		http.Redirect(w, r, "/", http.StatusFound)
+		redirectHome(w, r)
 	}
 }
 
@@ -105,7 +165,7 @@ func (h handler) Search(w http.ResponseWriter, r *http.Request) {
 	var search = r.URL.Query().Get("search")
 	list, err := h.todosSvc.Search(r.Context(), search)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to retrieve todos")
 		return
 	}
 
@@ -116,6 +176,7 @@ func (h handler) Search(w http.ResponseWriter, r *http.Request) {
 		err = pages.TodosPage(list, search).Render(r.Context(), w)
 	}
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -129,7 +190,12 @@ func (h handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	todo, err := h.todosSvc.Add(r.Context(), description)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			h.addTodoInvalid(w, r, description, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to add todo")
 		return
 	}
 
@@ -137,10 +203,11 @@ func (h handler) Create(w http.ResponseWriter, r *http.Request) {
 	case true:
 		err = partials.RenderTodo(todo).Render(r.Context(), w)
 	default:
-		http.Redirect(w, r, "/", http.StatusFound)
+		redirectHome(w, r)
 	}
 
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -162,7 +229,12 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	todo, err := h.todosSvc.Update(r.Context(), todoID, completed, description)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			h.updateTodoInvalid(w, r, todoID, completed, description, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to update todo")
 		return
 	}
 
@@ -170,9 +242,10 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 	case true:
 		err = partials.RenderTodo(todo).Render(r.Context(), w)
 	default:
-		http.Redirect(w, r, "/", http.StatusFound)
+		redirectHome(w, r)
 	}
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -187,17 +260,18 @@ func (h handler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 	todo, err := h.todosSvc.Get(r.Context(), todoID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to get todo")
 		return
 	}
 
 	switch isHTMX(r) {
 	case true:
-		err = partials.EditTodoForm(todo).Render(r.Context(), w)
+		err = partials.EditTodoForm(todo, "").Render(r.Context(), w)
 	default:
-		err = pages.TodoPage(todo).Render(r.Context(), w)
+		err = pages.TodoPage(todo, "").Render(r.Context(), w)
 	}
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -212,7 +286,7 @@ func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.todosSvc.Remove(r.Context(), todoID); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to remove todo")
 		return
 	}
 
@@ -220,14 +294,285 @@ func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
 	case true:
 		_, err = w.Write([]byte(""))
 	default:
-		http.Redirect(w, r, "/", http.StatusFound)
+		redirectHome(w, r)
+	}
+
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) Export(w http.ResponseWriter, r *http.Request) {
+	var format = r.URL.Query().Get("format")
+	if format == "" {
+		format = domain.FormatJSON
+	}
+	codec, err := domain.CodecFor(format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.todosSvc.Export(r.Context(), format)
+	if err != nil {
+		serviceError(w, r, err, "failed to export todos")
+		return
+	}
+
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="todos.`+codec.Extension()+`"`)
+	_, _ = w.Write(data)
+}
+
+func (h handler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.importInvalid(w, r, "choose a .json, .csv, .txt or .md file to import")
+		return
+	}
+	defer file.Close()
+
+	var format = strings.TrimPrefix(path.Ext(header.Filename), ".")
+	mode, err := domain.ParseImportMode(r.Form.Get("mode"))
+	if err != nil {
+		h.importInvalid(w, r, err.Error())
+		return
+	}
+	var dryRun = r.Form.Get("dry_run") == "true"
+
+	report, err := h.todosSvc.Import(r.Context(), format, file, mode, dryRun)
+	if err != nil {
+		var validationErr domain.ErrValidation
+		var formatErr domain.ErrUnsupportedFormat
+		var decodeErr domain.ErrUnmarshaling
+		if errors.As(err, &validationErr) || errors.As(err, &formatErr) || errors.As(err, &decodeErr) {
+			h.importInvalid(w, r, err.Error())
+			return
+		}
+		serviceError(w, r, err, "failed to import todos")
+		return
+	}
+
+	switch {
+	case !isHTMX(r):
+		err = pages.ImportPage(report).Render(r.Context(), w)
+	case report.DryRun:
+		err = partials.ImportReport(report).Render(r.Context(), w)
+	default:
+		var list []*domain.Todo
+		if list, err = h.todosSvc.Search(r.Context(), ""); err != nil {
+			serviceError(w, r, err, "failed to retrieve todos")
+			return
+		}
+		err = partials.ImportedTodos(report, list).Render(r.Context(), w)
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) LoginPage(w http.ResponseWriter, r *http.Request) {
+	if err := pages.LoginPage("", "", "").Render(r.Context(), w); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) Login(w http.ResponseWriter, r *http.Request) {
+	h.authenticate(w, r, h.accounts.Login)
+}
+
+func (h handler) Register(w http.ResponseWriter, r *http.Request) {
+	h.authenticate(w, r, h.accounts.Register)
+}
+
+func (h handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.accounts.Logout(r.Context()); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to log out")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// authenticate signs the user in with the submitted credentials and sends them to
+// their list, or renders the login page with the reasons they were rejected
+func (h handler) authenticate(w http.ResponseWriter, r *http.Request, signIn func(ctx context.Context, username, password string) (*domain.User, error)) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var username = r.Form.Get("username")
+
+	_, err := signIn(r.Context(), username, r.Form.Get("password"))
+	if err != nil {
+		var validationErr domain.ErrValidation
+		var credentialsErr domain.ErrInvalidCredentials
+		var status int
+		var usernameMessage, passwordMessage string
+		switch {
+		case errors.As(err, &validationErr):
+			status = http.StatusUnprocessableEntity
+			usernameMessage = validationErr.Field(domain.FieldUsername)
+			passwordMessage = validationErr.Field(domain.FieldPassword)
+		case errors.As(err, &credentialsErr):
+			status = http.StatusUnauthorized
+			passwordMessage = credentialsErr.Error()
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to sign in")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		if err = pages.LoginPage(username, usernameMessage, passwordMessage).Render(r.Context(), w); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// importInvalid renders the reason an import was rejected
+func (h handler) importInvalid(w http.ResponseWriter, r *http.Request, message string) {
+	var err error
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	switch isHTMX(r) {
+	case true:
+		err = partials.FieldError("import-error", message).Render(r.Context(), w)
+	default:
+		err = pages.ImportErrorPage(message).Render(r.Context(), w)
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// addTodoInvalid renders the add todo form errors
+func (h handler) addTodoInvalid(w http.ResponseWriter, r *http.Request, description string, validationErr domain.ErrValidation) {
+	var err error
+	var message = validationErr.Field(domain.FieldDescription)
+
+	switch isHTMX(r) {
+	case true:
+		retarget(w, "add-todo-error")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = partials.FieldError("add-todo-error", message).Render(r.Context(), w)
+	default:
+		var list []*domain.Todo
+		if list, err = h.todosSvc.Search(r.Context(), ""); err != nil {
+			serviceError(w, r, err, "failed to retrieve todos")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = pages.AddTodoPage(list, description, message).Render(r.Context(), w)
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
+}
+
+// updateTodoInvalid renders the edit todo form errors
+func (h handler) updateTodoInvalid(w http.ResponseWriter, r *http.Request, todoID uuid.UUID, completed bool, description string, validationErr domain.ErrValidation) {
+	var err error
+	var message = validationErr.Field(domain.FieldDescription)
+	var errorID = "description-error-" + todoID.String()
 
+	switch isHTMX(r) {
+	case true:
+		retarget(w, errorID)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = partials.FieldError(errorID, message).Render(r.Context(), w)
+	default:
+		var todo *domain.Todo
+		if todo, err = h.todosSvc.Get(r.Context(), todoID); err != nil {
+			serviceError(w, r, err, "failed to get todo")
+			return
+		}
+		if todo == nil {
+			http.NotFound(w, r)
+			return
+		}
+		// render what was submitted rather than what was saved
+		var submitted = *todo
+		submitted.Completed = completed
+		submitted.Description = description
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = pages.TodoPage(&submitted, message).Render(r.Context(), w)
+	}
 	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// serviceError sends the user to the login page when they are not signed in, responds
+// with 403 Forbidden when their role in the list does not allow the request or the
+// request lacks the CSRF token of the session, and otherwise logs the error and
+// responds with 500 Internal Server Error
+func serviceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	var unauthenticatedErr domain.ErrUnauthenticated
+	var permissionErr domain.ErrPermissionDenied
+	var csrfErr domain.ErrInvalidCSRFToken
+	var apiErr domain.ErrIncompatibleAPI
+	if errors.As(err, &permissionErr) {
+		http.Error(w, permissionErr.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.As(err, &csrfErr) {
+		// pages opened before the user signed in again carry the token of an old session
+		http.Error(w, csrfErr.Error()+"; reload the page and try again", http.StatusForbidden)
+		return
+	}
+	if errors.As(err, &apiErr) {
+		// the server was updated while this proxy kept serving the page
+		http.Error(w, apiErr.Error()+"; reload the page to update it", http.StatusConflict)
+		return
+	}
+	if errors.As(err, &unauthenticatedErr) {
+		switch isHTMX(r) {
+		case true:
+			// htmx follows HX-Redirect whatever the status
+			w.Header().Set("HX-Redirect", "/login")
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+		}
+		return
+	}
+	log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// redirectHome sends a browser without htmx back to the list it was on
+func redirectHome(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, shared.InList(r.Context(), "/"), http.StatusFound)
+}
+
+// retarget swaps the response into the element with the given id instead of the request target
+func retarget(w http.ResponseWriter, id string) {
+	w.Header().Set("HX-Retarget", "#"+id)
+	w.Header().Set("HX-Reswap", "outerHTML")
+}
+
 func isHTMX(r *http.Request) bool {
 	// Check for "HX-Request" header
 	if r.Header.Get("HX-Request") != "" {
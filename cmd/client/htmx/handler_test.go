@@ -3,6 +3,7 @@ package htmx
 import (
 	"bytes"
 	"context"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -29,6 +30,9 @@ func Test_handler_Create(t *testing.T) {
 		Completed:   false,
 		CreatedAt:   time.Now(),
 	}
+	var invalid = domain.ErrValidation{
+		Fields: map[string]string{domain.FieldDescription: "must not be empty"},
+	}
 	type fields struct {
 		todosSvc *todos.MockService
 	}
@@ -80,6 +84,46 @@ func Test_handler_Create(t *testing.T) {
 			},
 			wantView: partials.RenderTodo(todo),
 		},
+		"CreateInvalidHTML": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("description=+"))
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Add(context.Background(), " ").Return(nil, invalid)
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/html; charset=utf-8"},
+			},
+			wantView: pages.AddTodoPage([]*domain.Todo{todo}, " ", "must not be empty"),
+		},
+		"CreateInvalidHTMX": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("description=+"))
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+					req.Header.Set("HX-Request", "true")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Add(context.Background(), " ").Return(nil, invalid)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/html; charset=utf-8"},
+				"Hx-Retarget":  []string{"#add-todo-error"},
+				"Hx-Reswap":    []string{"outerHTML"},
+			},
+			wantView: partials.FieldError("add-todo-error", "must not be empty"),
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -255,7 +299,7 @@ func Test_handler_Get(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type": []string{"text/html; charset=utf-8"},
 			},
-			wantView: pages.TodoPage(todo),
+			wantView: pages.TodoPage(todo, ""),
 		},
 		"GetHTMX": {
 			args: args{
@@ -276,7 +320,7 @@ func Test_handler_Get(t *testing.T) {
 			wantHeader: http.Header{
 				"Content-Type": []string{"text/html; charset=utf-8"},
 			},
-			wantView: partials.EditTodoForm(todo),
+			wantView: partials.EditTodoForm(todo, ""),
 		},
 	}
 	for name, tt := range tests {
@@ -347,7 +391,7 @@ func Test_handler_Search(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Search(mock.AnythingOfType("*context.emptyCtx"), "test").Return([]*domain.Todo{todo}, nil)
+				f.todosSvc.EXPECT().Search(context.Background(), "test").Return([]*domain.Todo{todo}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
@@ -365,7 +409,7 @@ func Test_handler_Search(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Search(mock.AnythingOfType("*context.emptyCtx"), "test").Return([]*domain.Todo{todo}, nil)
+				f.todosSvc.EXPECT().Search(context.Background(), "test").Return([]*domain.Todo{todo}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
@@ -454,7 +498,7 @@ func Test_handler_Sort(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Sort(mock.AnythingOfType("*context.emptyCtx"), todoIDs).Return([]*domain.Todo{
+				f.todosSvc.EXPECT().Sort(context.Background(), todoIDs).Return([]*domain.Todo{
 					firstTodo, secondTodo,
 				}, nil)
 			},
@@ -479,7 +523,7 @@ func Test_handler_Sort(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Sort(mock.AnythingOfType("*context.emptyCtx"), todoIDs).Return([]*domain.Todo{
+				f.todosSvc.EXPECT().Sort(context.Background(), todoIDs).Return([]*domain.Todo{
 					firstTodo, secondTodo,
 				}, nil)
 			},
@@ -487,6 +531,43 @@ func Test_handler_Sort(t *testing.T) {
 			wantHeader:     http.Header{},
 			wantView:       nil,
 		},
+		"SortUnauthenticatedHTML": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"id": {firstTodo.ID.String()}}.Encode()))
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Sort(context.Background(), []uuid.UUID{firstTodo.ID}).Return(nil, domain.ErrUnauthenticated{})
+			},
+			wantStatusCode: http.StatusSeeOther,
+			wantHeader: http.Header{
+				"Location": []string{"/login"},
+			},
+			wantView: nil,
+		},
+		"SortUnauthenticatedHTMX": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"id": {firstTodo.ID.String()}}.Encode()))
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+					req.Header.Set("HX-Request", "true")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Sort(context.Background(), []uuid.UUID{firstTodo.ID}).Return(nil, domain.ErrUnauthenticated{})
+			},
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeader: http.Header{
+				"Hx-Redirect": []string{"/login"},
+			},
+			wantView: nil,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -627,3 +708,211 @@ func Test_handler_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_Import(t *testing.T) {
+	var todo = &domain.Todo{
+		ID:          uuid.New(),
+		Description: "first",
+		Completed:   false,
+		CreatedAt:   time.Now(),
+	}
+	var dryRun = &domain.ImportReport{Mode: domain.ImportMerge, DryRun: true, Added: []*domain.Todo{todo}}
+	var applied = &domain.ImportReport{Mode: domain.ImportReplace, Added: []*domain.Todo{todo}}
+	var upload = func(filename, content string, fields url.Values) *http.Request {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		fw, _ := mw.CreateFormFile("file", filename)
+		_, _ = fw.Write([]byte(content))
+		for name := range fields {
+			_ = mw.WriteField(name, fields.Get(name))
+		}
+		_ = mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("HX-Request", "true")
+		return req
+	}
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := map[string]struct {
+		args           args
+		mock           func(f fields)
+		wantStatusCode int
+		wantView       templ.Component
+	}{
+		"ImportDryRunHTMX": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: upload("todos.csv", "description\nfirst\n", url.Values{"mode": {"merge"}, "dry_run": {"true"}}),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Import(context.Background(), "csv", mock.Anything, domain.ImportMerge, true).Return(dryRun, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantView:       partials.ImportReport(dryRun),
+		},
+		"ImportHTMX": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: upload("todos.json", "[]", url.Values{"mode": {"replace"}}),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Import(context.Background(), "json", mock.Anything, domain.ImportReplace, false).Return(applied, nil)
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantView:       partials.ImportedTodos(applied, []*domain.Todo{todo}),
+		},
+		"ImportUnsupportedHTMX": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: upload("todos.xml", "<todos/>", url.Values{}),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Import(context.Background(), "xml", mock.Anything, domain.ImportMerge, false).Return(nil, domain.ErrUnsupportedFormat{Format: "xml"})
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantView:       partials.FieldError("import-error", `unsupported format: "xml"`),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := handler{
+				todosSvc: f.todosSvc,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			h.Import(tt.args.w, tt.args.r)
+
+			res := tt.args.w.(*httptest.ResponseRecorder).Result()
+			if res.StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.Import() StatusCode = %v, want %v", res.StatusCode, tt.wantStatusCode)
+			}
+			gotBuffer := new(bytes.Buffer)
+			_, _ = gotBuffer.ReadFrom(res.Body)
+			wantBuffer := new(bytes.Buffer)
+			_ = tt.wantView.Render(context.Background(), wantBuffer)
+			if gotBuffer.String() != wantBuffer.String() {
+				t.Errorf("handler.Import() Body = %v, want %v", gotBuffer.String(), wantBuffer.String())
+			}
+		})
+	}
+}
+
+func Test_handler_Login(t *testing.T) {
+	var user = &domain.User{ID: uuid.New(), Username: "alice"}
+	var invalid = domain.ErrValidation{
+		Fields: map[string]string{
+			domain.FieldUsername: "must be at least 3 characters",
+			domain.FieldPassword: "must be at least 8 characters",
+		},
+	}
+	type fields struct {
+		accounts *domain.MockAccounts
+	}
+	tests := map[string]struct {
+		register       bool
+		form           url.Values
+		mock           func(f fields)
+		wantStatusCode int
+		wantHeader     http.Header
+		wantView       templ.Component
+	}{
+		"Login": {
+			form: url.Values{"username": {"alice"}, "password": {"correct horse"}},
+			mock: func(f fields) {
+				f.accounts.EXPECT().Login(context.Background(), "alice", "correct horse").Return(user, nil)
+			},
+			wantStatusCode: http.StatusSeeOther,
+			wantHeader: http.Header{
+				"Location": []string{"/"},
+			},
+		},
+		"LoginInvalidCredentials": {
+			form: url.Values{"username": {"alice"}, "password": {"wrong password"}},
+			mock: func(f fields) {
+				f.accounts.EXPECT().Login(context.Background(), "alice", "wrong password").Return(nil, domain.ErrInvalidCredentials{})
+			},
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/html; charset=utf-8"},
+			},
+			wantView: pages.LoginPage("alice", "", "incorrect username or password"),
+		},
+		"Register": {
+			register: true,
+			form:     url.Values{"username": {"alice"}, "password": {"correct horse"}},
+			mock: func(f fields) {
+				f.accounts.EXPECT().Register(context.Background(), "alice", "correct horse").Return(user, nil)
+			},
+			wantStatusCode: http.StatusSeeOther,
+			wantHeader: http.Header{
+				"Location": []string{"/"},
+			},
+		},
+		"RegisterInvalid": {
+			register: true,
+			form:     url.Values{"username": {"al"}, "password": {"short"}},
+			mock: func(f fields) {
+				f.accounts.EXPECT().Register(context.Background(), "al", "short").Return(nil, invalid)
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/html; charset=utf-8"},
+			},
+			wantView: pages.LoginPage("al", "must be at least 3 characters", "must be at least 8 characters"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				accounts: domain.NewMockAccounts(t),
+			}
+			h := handler{
+				accounts: f.accounts,
+			}
+			tt.mock(f)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			switch tt.register {
+			case true:
+				h.Register(w, r)
+			default:
+				h.Login(w, r)
+			}
+
+			res := w.Result()
+			if res.StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.Login() StatusCode = %v, want %v", res.StatusCode, tt.wantStatusCode)
+			}
+			if !reflect.DeepEqual(res.Header, tt.wantHeader) {
+				t.Errorf("handler.Login() Header = %v, want %v", res.Header, tt.wantHeader)
+			}
+			gotBuffer := new(bytes.Buffer)
+			_, _ = gotBuffer.ReadFrom(res.Body)
+			if tt.wantView == nil {
+				if strings.TrimSpace(gotBuffer.String()) != "" {
+					t.Errorf("handler.Login() Body = %v, want %v", gotBuffer.String(), "")
+				}
+				return
+			}
+			wantBuffer := new(bytes.Buffer)
+			_ = tt.wantView.Render(context.Background(), wantBuffer)
+			if gotBuffer.String() != wantBuffer.String() {
+				t.Errorf("handler.Login() Body = %v, want %v", gotBuffer.String(), wantBuffer.String())
+			}
+		})
+	}
+}
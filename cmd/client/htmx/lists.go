@@ -0,0 +1,120 @@
+package htmx
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/pages"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
+)
+
+// SelectList is a http.Handler middleware that acts on the list named by the X-Todo-List
+// header, which every htmx request of a page on a shared list sends, or by the list
+// query parameter of links and forms; pages then only offer what the user's role in
+// the list allows
+func SelectList(sharing domain.Sharing) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			selected := r.Header.Get(domain.ListHeader)
+			if selected == "" {
+				selected = r.URL.Query().Get("list")
+			}
+			if selected == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			listID, err := uuid.Parse(selected)
+			if err != nil {
+				http.Error(w, "invalid list: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx := domain.ContextWithList(r.Context(), listID)
+			memberships, err := sharing.Lists(ctx)
+			if err != nil {
+				serviceError(w, r, err, "failed to retrieve lists")
+				return
+			}
+			for i, membership := range memberships {
+				if membership.ListID != listID {
+					continue
+				}
+				// the first list is the user's own, which pages show without a selection
+				if i > 0 {
+					ctx = shared.WithList(ctx, membership)
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			serviceError(w, r, domain.ErrPermissionDenied{Permission: domain.PermissionView}, "failed to select list")
+		})
+	}
+}
+
+func (h handler) Sharing(w http.ResponseWriter, r *http.Request) {
+	h.renderSharing(w, r, http.StatusOK, "", "")
+}
+
+func (h handler) Invite(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var username = r.Form.Get("username")
+
+	if _, err := h.sharing.Invite(r.Context(), username, domain.Role(r.Form.Get("role"))); err != nil {
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			message := validationErr.Field(domain.FieldUsername)
+			if message == "" {
+				message = validationErr.Field(domain.FieldRole)
+			}
+			h.renderSharing(w, r, http.StatusUnprocessableEntity, username, message)
+			return
+		}
+		serviceError(w, r, err, "failed to share list")
+		return
+	}
+
+	http.Redirect(w, r, shared.InList(r.Context(), "/sharing"), http.StatusSeeOther)
+}
+
+func (h handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sharing.RemoveMember(r.Context(), userID); err != nil {
+		serviceError(w, r, err, "failed to remove list member")
+		return
+	}
+
+	// members who leave a list go back to their own
+	if membership := shared.List(r.Context()); membership != nil && membership.UserID == userID {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, shared.InList(r.Context(), "/sharing"), http.StatusSeeOther)
+}
+
+// renderSharing renders the members of the list with the invite form
+func (h handler) renderSharing(w http.ResponseWriter, r *http.Request, status int, username, message string) {
+	memberships, err := h.sharing.Members(r.Context())
+	if err != nil {
+		serviceError(w, r, err, "failed to retrieve list members")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := pages.SharingPage(memberships, username, message).Render(r.Context(), w); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to render response")
+	}
+}
@@ -0,0 +1,97 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+)
+
+func TestSelectList(t *testing.T) {
+	var todo = &domain.Todo{ID: uuid.New(), Description: "Bake a cake"}
+	var own = &domain.Membership{ListID: uuid.New(), Role: domain.RoleOwner}
+	var listID = uuid.New()
+	tests := map[string]struct {
+		role           domain.Role
+		request        func() *http.Request
+		wantStatusCode int
+		wantReadOnly   bool
+	}{
+		"Viewer": {
+			role: domain.RoleViewer,
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+				req.Header.Set(domain.ListHeader, listID.String())
+				return req
+			},
+			wantStatusCode: http.StatusOK,
+			wantReadOnly:   true,
+		},
+		"Editor": {
+			role: domain.RoleEditor,
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/todos?list="+listID.String(), nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		"NotMember": {
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/todos?list="+listID.String(), nil)
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		"InvalidList": {
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/todos?list=mine", nil)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sharing := domain.NewMockSharing(t)
+			todosSvc := todos.NewMockService(t)
+			if tt.wantStatusCode != http.StatusBadRequest {
+				memberships := []*domain.Membership{own}
+				if tt.role != "" {
+					memberships = append(memberships, &domain.Membership{ListID: listID, ListName: "alice", Role: tt.role})
+				}
+				sharing.EXPECT().Lists(mock.Anything).Return(memberships, nil)
+			}
+			if tt.wantStatusCode == http.StatusOK {
+				todosSvc.EXPECT().Search(mock.MatchedBy(func(ctx context.Context) bool {
+					selected, ok := domain.ListFromContext(ctx)
+					return ok && selected == listID
+				}), "").Return([]*domain.Todo{todo}, nil)
+			}
+			h := handler{todosSvc: todosSvc, sharing: sharing}
+
+			rec := httptest.NewRecorder()
+			SelectList(sharing)(http.HandlerFunc(h.Search)).ServeHTTP(rec, tt.request())
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("StatusCode = %v, want %v", rec.Code, tt.wantStatusCode)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, `hx-headers="{&#34;`+domain.ListHeader+`&#34;:&#34;`+listID.String()+`&#34;}"`) {
+				t.Errorf("Body = %v, want the list in hx-headers", body)
+			}
+			if got := !strings.Contains(body, "hx-delete") && !strings.Contains(body, `hx-post="/todos/sort"`); got != tt.wantReadOnly {
+				t.Errorf("read only = %v, want %v", got, tt.wantReadOnly)
+			}
+			if got := strings.Contains(body, "Add Todo"); got == tt.wantReadOnly {
+				t.Errorf("add todo form shown = %v, want %v", got, !tt.wantReadOnly)
+			}
+		})
+	}
+}
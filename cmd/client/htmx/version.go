@@ -0,0 +1,102 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
+)
+
+var (
+	// versionCheckInterval is how long an answer from the server is trusted before it
+	// is asked again; pages poll for the banner, so deploys are noticed within about this long
+	versionCheckInterval = time.Minute
+	// versionRetryDelay is how long the proxy waits to ask again after a failed check; the
+	// wait doubles with every failure that follows, up to versionCheckInterval
+	versionRetryDelay = 5 * time.Second
+)
+
+// VersionCheck is a http.Handler middleware that asks the server for its version, and
+// again every versionCheckInterval; every page shows a banner asking the user to reload
+// when the server's API is incompatible with this client or when the server offers a
+// proxy other than build, the content hash of this one. Only the request that asks
+// waits for the answer; the others are served with the last one meanwhile.
+func VersionCheck(fetch func(ctx context.Context) (*domain.Version, error), build string) func(next http.Handler) http.Handler {
+	var mu sync.Mutex
+	var due time.Time
+	var fetching bool
+	var failures int
+	var current shared.Banner
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			check := !fetching && !time.Now().Before(due)
+			fetching = fetching || check
+			b := current
+			mu.Unlock()
+
+			if check {
+				version, err := fetch(r.Context())
+				mu.Lock()
+				fetching = false
+				if err != nil {
+					failures++
+					due = time.Now().Add(retryDelay(failures))
+					log.Ctx(r.Context()).Warn().Err(err).Int("Failures", failures).Msg("failed to check the server version")
+				} else {
+					failures = 0
+					due = time.Now().Add(versionCheckInterval)
+					current = versionBanner(version, build)
+				}
+				b = current
+				mu.Unlock()
+			}
+
+			if b.Message != "" {
+				r = r.WithContext(shared.WithBanner(r.Context(), b))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// retryDelay returns how long to wait after the given number of failed checks in a row
+func retryDelay(failures int) time.Duration {
+	delay := versionRetryDelay
+	for i := 1; i < failures && delay < versionCheckInterval; i++ {
+		delay *= 2
+	}
+	if delay > versionCheckInterval {
+		return versionCheckInterval
+	}
+	return delay
+}
+
+func versionBanner(version *domain.Version, build string) shared.Banner {
+	// a server that does not know its proxy, as in development, offers no update
+	offered := ""
+	if version.Client != "" && build != "" && version.Client != build {
+		offered = version.Client
+	}
+	switch {
+	case !domain.CompatibleAPI(version.API):
+		return shared.Banner{
+			Message: "This page speaks version " + domain.APIVersion + " of the API but the server speaks version " +
+				version.API + ". Reload the page to update it.",
+			Build:        offered,
+			Incompatible: true,
+		}
+	case offered != "":
+		return shared.Banner{
+			Message: "A new version of Todos is available.",
+			Build:   offered,
+		}
+	default:
+		return shared.Banner{}
+	}
+}
@@ -0,0 +1,207 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/pages"
+)
+
+func TestVersionCheck(t *testing.T) {
+	tests := map[string]struct {
+		versions   []*domain.Version
+		errs       []error
+		build      string
+		interval   time.Duration
+		retry      time.Duration
+		requests   int
+		wantBanner []bool
+		wantUpdate []bool
+		wantCalls  int
+	}{
+		"Compatible": {
+			versions:   []*domain.Version{{API: "1.7"}},
+			requests:   2,
+			wantBanner: []bool{false, false},
+			wantCalls:  1,
+		},
+		"Incompatible": {
+			versions:   []*domain.Version{{API: "2.0"}},
+			requests:   2,
+			wantBanner: []bool{true, true},
+			wantCalls:  1,
+		},
+		"RetriedAfterError": {
+			versions:   []*domain.Version{nil, {API: "2.0"}},
+			errs:       []error{errors.New("offline"), nil},
+			retry:      -1,
+			requests:   3,
+			wantBanner: []bool{false, true, true},
+			wantCalls:  2,
+		},
+		"BacksOffAfterError": {
+			versions:   []*domain.Version{nil},
+			errs:       []error{errors.New("offline")},
+			requests:   3,
+			wantBanner: []bool{false, false, false},
+			wantCalls:  1,
+		},
+		"SameBuild": {
+			versions:   []*domain.Version{{API: "1.7", Client: "0123456789ab"}},
+			build:      "0123456789ab",
+			requests:   2,
+			wantBanner: []bool{false, false},
+			wantUpdate: []bool{false, false},
+			wantCalls:  1,
+		},
+		"NewBuild": {
+			versions:   []*domain.Version{{API: "1.7", Client: "ba9876543210"}},
+			build:      "0123456789ab",
+			requests:   2,
+			wantBanner: []bool{false, false},
+			wantUpdate: []bool{true, true},
+			wantCalls:  1,
+		},
+		"UnknownBuild": {
+			versions:   []*domain.Version{{API: "1.7", Client: "ba9876543210"}},
+			requests:   1,
+			wantBanner: []bool{false},
+			wantUpdate: []bool{false},
+			wantCalls:  1,
+		},
+		"RecheckedAfterInterval": {
+			versions:   []*domain.Version{{API: "1.7", Client: "0123456789ab"}, {API: "1.7", Client: "ba9876543210"}},
+			build:      "0123456789ab",
+			interval:   -1,
+			requests:   2,
+			wantBanner: []bool{false, false},
+			wantUpdate: []bool{false, true},
+			wantCalls:  2,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.interval != 0 {
+				defer func(interval time.Duration) { versionCheckInterval = interval }(versionCheckInterval)
+				versionCheckInterval = tt.interval
+			}
+			if tt.retry != 0 {
+				defer func(retry time.Duration) { versionRetryDelay = retry }(versionRetryDelay)
+				versionRetryDelay = tt.retry
+			}
+			var calls int
+			fetch := func(context.Context) (*domain.Version, error) {
+				i := calls
+				calls++
+				var err error
+				if i < len(tt.errs) {
+					err = tt.errs[i]
+				}
+				return tt.versions[i], err
+			}
+			handler := VersionCheck(fetch, tt.build)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = pages.HomePage(nil, nil).Render(r.Context(), w)
+			}))
+
+			for i := 0; i < tt.requests; i++ {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+				if got := strings.Contains(rec.Body.String(), `id="version-warning"`); got != tt.wantBanner[i] {
+					t.Errorf("request %d banner = %v, want %v", i, got, tt.wantBanner[i])
+				}
+				if tt.wantUpdate == nil {
+					continue
+				}
+				if got := strings.Contains(rec.Body.String(), `data-update="ba9876543210"`); got != tt.wantUpdate[i] {
+					t.Errorf("request %d update = %v, want %v", i, got, tt.wantUpdate[i])
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("fetched the version %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestVersionCheck_DoesNotBlock(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(context.Context) (*domain.Version, error) {
+		close(fetching)
+		<-release
+		return &domain.Version{API: "2.0"}, nil
+	}
+	handler := VersionCheck(fetch, "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = pages.HomePage(nil, nil).Render(r.Context(), w)
+	}))
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		first <- rec
+	}()
+	<-fetching
+
+	// the server has not answered the first request yet
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rec.Body.String(), `id="version-warning"`) {
+		t.Errorf("request served during the check has a banner, want the last answer")
+	}
+	close(release)
+	if rec := <-first; !strings.Contains(rec.Body.String(), `id="version-warning"`) {
+		t.Errorf("request that checked has no banner, want the new answer")
+	}
+}
+
+func Test_retryDelay(t *testing.T) {
+	tests := map[string]struct {
+		failures int
+		want     time.Duration
+	}{
+		"First":  {failures: 1, want: 5 * time.Second},
+		"Second": {failures: 2, want: 10 * time.Second},
+		"Fourth": {failures: 4, want: 40 * time.Second},
+		"Capped": {failures: 10, want: time.Minute},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := retryDelay(tt.failures); got != tt.want {
+				t.Errorf("retryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateBanner(t *testing.T) {
+	fetch := func(context.Context) (*domain.Version, error) {
+		return &domain.Version{API: domain.APIVersion, Client: "ba9876543210"}, nil
+	}
+	router := chi.NewRouter()
+	router.Use(VersionCheck(fetch, "0123456789ab"))
+	Mount(router, NewHandler(nil, nil, nil, nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/update", nil))
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, `<div id="version-banner"`) || !strings.Contains(body, `data-update="ba9876543210"`) {
+		t.Errorf("GET /update = %q, want the banner offering the new build", body)
+	}
+}
+
+func Test_serviceError_IncompatibleAPI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serviceError(rec, httptest.NewRequest(http.MethodPost, "/todos", nil), domain.ErrIncompatibleAPI{API: "2.0"}, "failed to add todo")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("StatusCode = %v, want %v", rec.Code, http.StatusConflict)
+	}
+}
@@ -1,23 +1,90 @@
 package main
 
 import (
+	"net/http"
+	"os"
+	"syscall/js"
+	"time"
+
 	"github.com/go-chi/chi/v5"
 
 	wasmhttp "github.com/nlepage/go-wasm-http-server"
 
 	"github.com/stackus/todos-htmx-wasm/cmd/client/htmx"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
 	"github.com/stackus/todos-htmx-wasm/internal/features/home"
 	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/metrics"
+	"github.com/stackus/todos-htmx-wasm/internal/rpc"
+	"github.com/stackus/todos-htmx-wasm/internal/security"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
 )
 
+// transport selects how the proxy talks to the server: "json" or "cbor" for the REST
+// API, or "connect" for the TodosService RPC API; set it with -ldflags "-X main.transport=cbor"
+var transport = "json"
+
+// traceExporter is set to "stdout" to write finished trace spans to the browser console
+var traceExporter = ""
+
+// cspReportOnly is set to "true" to only report violations of the Content-Security-Policy
+// of the pages the proxy renders, as the server's -csp-report-only does for its own
+var cspReportOnly = ""
+
 func main() {
 	done := make(chan struct{})
 
+	// the server is the origin the service worker was installed from, so the proxy
+	// follows the page to HTTPS
+	serverHost := js.Global().Get("location").Get("origin").String()
+
+	// the service worker passes the manifest of the assets it loaded the proxy from
+	if m, err := manifest.Parse(os.Getenv(manifest.Env)); err == nil {
+		manifest.Use(m)
+	}
+
+	if traceExporter == "stdout" {
+		trace.DefaultTracer = trace.NewTracer(trace.NewStdoutExporter(os.Stdout))
+	}
+
+	// the browser sends the session cookie with every request to the server, so accounts
+	// and every transport share the signed in user
+	api := domain.NewTodoApi(serverHost)
+
+	reg := metrics.NewRegistry()
+	metrics.RegisterRuntime(reg)
+
+	// the pages are rendered here, so it is these headers that the browser enforces
+	headers := security.DefaultConfig()
+	headers.ReportOnly = cspReportOnly == "true"
+
 	router := chi.NewRouter()
-	list := domain.NewTodoApi("http://localhost:3000")
+	router.Use(
+		trace.Middleware(trace.DefaultTracer),
+		log.RequestID(log.DefaultLogger),
+		metrics.Middleware(reg),
+		security.Headers(headers),
+		htmx.VersionCheck(api.Version, manifest.Hash("client.wasm")),
+		htmx.CSRF(api.CSRFToken),
+		htmx.SelectList(api),
+	)
+	router.Handle("/metrics", metrics.Handler(reg))
+
+	var list domain.TodoRepository
+	switch transport {
+	case "cbor":
+		list = domain.NewTodoApi(serverHost, domain.WithCBOR())
+	case "connect":
+		list = rpc.NewTodoClient(&http.Client{Timeout: 10 * time.Second}, serverHost)
+	default:
+		list = api
+	}
+
+	list = metrics.NewTodoRepository(list, reg)
 
-	htmx.Mount(router, htmx.NewHandler(home.NewService(list), todos.NewService(list)))
+	htmx.Mount(router, htmx.NewHandler(home.NewService(list), todos.NewService(list), api, api))
 
 	println("WASM Client is running")
 
@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+type (
+	Handler interface {
+		// Query : POST /graphql
+		Query(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		schema   *gql.Schema
+		todosSvc todos.Service
+	}
+
+	// request is a single GraphQL operation; a batch is a JSON array of them
+	request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+)
+
+const (
+	// maxRequestSize is the largest request body, including batches, the handler will decode
+	maxRequestSize = 64 << 10
+	// maxBatchSize is the largest number of operations in a batch
+	maxBatchSize = 20
+	// maxDepth limits how deeply queries may nest
+	maxDepth = 10
+)
+
+//go:embed schema.graphql
+var schema string
+
+func NewHandler(todosSvc todos.Service) Handler {
+	return &handler{
+		schema:   gql.MustParseSchema(schema, &resolver{todosSvc: todosSvc}, gql.MaxDepth(maxDepth)),
+		todosSvc: todosSvc,
+	}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Post("/graphql", h.Query)
+}
+
+func (h handler) Query(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to read request")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var requests []request
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		requests = make([]request, 1)
+		err = json.Unmarshal(body, &requests[0])
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(requests) > maxBatchSize {
+		http.Error(w, "too many operations in batch", http.StatusBadRequest)
+		return
+	}
+
+	// all operations in the request share one loader
+	ctx := withLoader(r.Context(), h.todosSvc)
+	responses := make([]*gql.Response, len(requests))
+	for i, req := range requests {
+		responses[i] = h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		err = json.NewEncoder(w).Encode(responses)
+	} else {
+		err = json.NewEncoder(w).Encode(responses[0])
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to encode response")
+	}
+}
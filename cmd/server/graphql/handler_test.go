@@ -0,0 +1,166 @@
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+)
+
+func Test_handler_Query(t *testing.T) {
+	var createdAt = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	var cake = &domain.Todo{ID: uuid.New(), Description: "Bake a cake", CreatedAt: createdAt}
+	var cat = &domain.Todo{ID: uuid.New(), Description: "Feed the cat", Completed: true, CreatedAt: createdAt}
+	var list = []*domain.Todo{cake, cat}
+
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	tests := map[string]struct {
+		body           string
+		mock           func(f fields)
+		wantStatusCode int
+		want           string
+	}{
+		"Todos": {
+			body: `{"query":"{ todos(filter: {completed: true}) { id description completed createdAt } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return(list, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"todos":[{"id":"` + cat.ID.String() + `","description":"Feed the cat","completed":true,"createdAt":"2023-06-01T12:00:00Z"}]}}`,
+		},
+		"TodosSearch": {
+			body: `{"query":"query ($search: String) { todos(filter: {search: $search}) { description } }","variables":{"search":"cake"}}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(mock.Anything, "cake").Return([]*domain.Todo{cake}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"todos":[{"description":"Bake a cake"}]}}`,
+		},
+		"Counts": {
+			body: `{"query":"{ counts { total active completed } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return(list, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"counts":{"total":2,"active":1,"completed":1}}}`,
+		},
+		"LookupsShareOneSearch": {
+			body: `{"query":"{ a: todo(id: \"` + cake.ID.String() + `\") { description } b: todo(id: \"` + cat.ID.String() + `\") { description }` +
+				` c: todo(id: \"` + uuid.New().String() + `\") { description } d: todos(filter: {ids: [\"` + cat.ID.String() + `\"]}) { description } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return(list, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"a":{"description":"Bake a cake"},"b":{"description":"Feed the cat"},"c":null,"d":[{"description":"Feed the cat"}]}}`,
+		},
+		"BatchSharesOneSearch": {
+			body: `[{"query":"{ todo(id: \"` + cake.ID.String() + `\") { description } }"},` +
+				`{"query":"{ todo(id: \"` + cat.ID.String() + `\") { completed } }"}]`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return(list, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `[{"data":{"todo":{"description":"Bake a cake"}}},{"data":{"todo":{"completed":true}}}]`,
+		},
+		"MutationClearsLookups": {
+			body: `[{"query":"{ todo(id: \"` + cake.ID.String() + `\") { completed } }"},` +
+				`{"query":"mutation { updateTodo(id: \"` + cake.ID.String() + `\", completed: true, description: \"Bake a cake\") { completed } }"},` +
+				`{"query":"{ todo(id: \"` + cake.ID.String() + `\") { completed } }"}]`,
+			mock: func(f fields) {
+				completedCake := &domain.Todo{ID: cake.ID, Description: cake.Description, Completed: true, CreatedAt: createdAt}
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return(list, nil).Once()
+				f.todosSvc.EXPECT().Update(mock.Anything, cake.ID, true, "Bake a cake").Return(completedCake, nil)
+				f.todosSvc.EXPECT().Search(mock.Anything, "").Return([]*domain.Todo{completedCake, cat}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `[{"data":{"todo":{"completed":false}}},{"data":{"updateTodo":{"completed":true}}},{"data":{"todo":{"completed":true}}}]`,
+		},
+		"AddTodo": {
+			body: `{"query":"mutation { addTodo(description: \"Bake a cake\") { id } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Add(mock.Anything, "Bake a cake").Return(cake, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"addTodo":{"id":"` + cake.ID.String() + `"}}}`,
+		},
+		"AddTodoInvalid": {
+			body: `{"query":"mutation { addTodo(description: \" \") { id } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Add(mock.Anything, " ").Return(nil, domain.ErrValidation{Fields: map[string]string{"description": "must not be empty"}})
+			},
+			wantStatusCode: http.StatusOK,
+			want: `{"errors":[{"message":"validation failed: description must not be empty","path":["addTodo"],` +
+				`"extensions":{"code":"VALIDATION_FAILED","fields":{"description":"must not be empty"}}}],"data":null}`,
+		},
+		"RemoveTodo": {
+			body: `{"query":"mutation { removeTodo(id: \"` + cake.ID.String() + `\") }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Remove(mock.Anything, cake.ID).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"removeTodo":true}}`,
+		},
+		"RemoveTodoDenied": {
+			body: `{"query":"mutation { removeTodo(id: \"` + cake.ID.String() + `\") }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Remove(mock.Anything, cake.ID).Return(domain.ErrPermissionDenied{Permission: domain.PermissionEdit})
+			},
+			wantStatusCode: http.StatusOK,
+			want: `{"errors":[{"message":"not allowed to edit this list","path":["removeTodo"],` +
+				`"extensions":{"code":"FORBIDDEN","permission":"edit"}}],"data":null}`,
+		},
+		"SortTodos": {
+			body: `{"query":"mutation { sortTodos(ids: [\"` + cat.ID.String() + `\", \"` + cake.ID.String() + `\"]) { description } }"}`,
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Sort(mock.Anything, []uuid.UUID{cat.ID, cake.ID}).Return([]*domain.Todo{cat, cake}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           `{"data":{"sortTodos":[{"description":"Feed the cat"},{"description":"Bake a cake"}]}}`,
+		},
+		"InvalidBody": {
+			body:           `{"query":`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := NewHandler(f.todosSvc)
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			w := httptest.NewRecorder()
+			h.Query(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("handler.Query() StatusCode = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.want == "" {
+				return
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("handler.Query() Body = %v, not JSON: %v", w.Body.String(), err)
+			}
+			_ = json.Unmarshal([]byte(tt.want), &want)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("handler.Query() Body = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
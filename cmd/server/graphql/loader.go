@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+)
+
+type loaderKey struct{}
+
+// todoLoader looks todos up by id for a single HTTP request
+//
+// The first lookup fetches the whole list and every later lookup, including
+// those from other operations in a batch, is answered from it; this replaces
+// one Get per id with a single Search. Mutations clear the loaded list.
+type todoLoader struct {
+	todosSvc todos.Service
+	mu       sync.Mutex
+	todos    map[uuid.UUID]*domain.Todo
+}
+
+func withLoader(ctx context.Context, todosSvc todos.Service) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &todoLoader{todosSvc: todosSvc})
+}
+
+// loaderFrom returns the request's loader, or a new one when there is none
+func loaderFrom(ctx context.Context, todosSvc todos.Service) *todoLoader {
+	if loader, ok := ctx.Value(loaderKey{}).(*todoLoader); ok {
+		return loader
+	}
+	return &todoLoader{todosSvc: todosSvc}
+}
+
+// Load returns the todos with the ids, in the same order, with nil for unknown ids
+func (l *todoLoader) Load(ctx context.Context, ids []uuid.UUID) ([]*domain.Todo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.todos == nil {
+		list, err := l.todosSvc.Search(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		l.todos = make(map[uuid.UUID]*domain.Todo, len(list))
+		for _, todo := range list {
+			l.todos[todo.ID] = todo
+		}
+	}
+
+	found := make([]*domain.Todo, len(ids))
+	for i, id := range ids {
+		found[i] = l.todos[id]
+	}
+	return found, nil
+}
+
+// Clear drops the loaded list so that the next lookup sees any changes
+func (l *todoLoader) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.todos = nil
+}
@@ -0,0 +1,272 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+)
+
+type (
+	resolver struct {
+		todosSvc todos.Service
+	}
+
+	todoResolver struct {
+		todo *domain.Todo
+	}
+
+	countsResolver struct {
+		todos []*domain.Todo
+	}
+
+	todoFilter struct {
+		Search    *string
+		Completed *bool
+		IDs       *[]gql.ID
+	}
+
+	// validationError exposes the invalid fields in the GraphQL error extensions
+	validationError struct {
+		domain.ErrValidation
+	}
+
+	// permissionError tells clients the role of the user in the list does not allow
+	// the operation
+	permissionError struct {
+		domain.ErrPermissionDenied
+	}
+)
+
+func (r *resolver) Todos(ctx context.Context, args struct{ Filter *todoFilter }) ([]*todoResolver, error) {
+	list, err := r.filter(ctx, args.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return todoResolvers(list), nil
+}
+
+func (r *resolver) Todo(ctx context.Context, args struct{ ID gql.ID }) (*todoResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	found, err := loaderFrom(ctx, r.todosSvc).Load(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if found[0] == nil {
+		return nil, nil
+	}
+	return &todoResolver{todo: found[0]}, nil
+}
+
+func (r *resolver) Counts(ctx context.Context, args struct{ Filter *todoFilter }) (*countsResolver, error) {
+	list, err := r.filter(ctx, args.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &countsResolver{todos: list}, nil
+}
+
+func (r *resolver) AddTodo(ctx context.Context, args struct{ Description string }) (*todoResolver, error) {
+	defer loaderFrom(ctx, r.todosSvc).Clear()
+
+	todo, err := r.todosSvc.Add(ctx, args.Description)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &todoResolver{todo: todo}, nil
+}
+
+func (r *resolver) UpdateTodo(ctx context.Context, args struct {
+	ID          gql.ID
+	Completed   bool
+	Description string
+}) (*todoResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer loaderFrom(ctx, r.todosSvc).Clear()
+
+	todo, err := r.todosSvc.Update(ctx, id, args.Completed, args.Description)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if todo == nil {
+		return nil, nil
+	}
+	return &todoResolver{todo: todo}, nil
+}
+
+func (r *resolver) RemoveTodo(ctx context.Context, args struct{ ID gql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	defer loaderFrom(ctx, r.todosSvc).Clear()
+
+	if err := r.todosSvc.Remove(ctx, id); err != nil {
+		return false, resolverError(err)
+	}
+	return true, nil
+}
+
+func (r *resolver) SortTodos(ctx context.Context, args struct{ IDs []gql.ID }) ([]*todoResolver, error) {
+	ids, err := parseIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	defer loaderFrom(ctx, r.todosSvc).Clear()
+
+	list, err := r.todosSvc.Sort(ctx, ids)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return todoResolvers(list), nil
+}
+
+// filter returns the todos matching every condition in the filter
+func (r *resolver) filter(ctx context.Context, filter *todoFilter) ([]*domain.Todo, error) {
+	if filter == nil {
+		filter = &todoFilter{}
+	}
+
+	var list []*domain.Todo
+	if filter.IDs != nil {
+		ids, err := parseIDs(*filter.IDs)
+		if err != nil {
+			return nil, err
+		}
+		found, err := loaderFrom(ctx, r.todosSvc).Load(ctx, ids)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		for _, todo := range found {
+			if todo != nil && (filter.Search == nil || strings.Contains(todo.Description, *filter.Search)) {
+				list = append(list, todo)
+			}
+		}
+	} else {
+		var search string
+		if filter.Search != nil {
+			search = *filter.Search
+		}
+		var err error
+		if list, err = r.todosSvc.Search(ctx, search); err != nil {
+			return nil, resolverError(err)
+		}
+	}
+
+	if filter.Completed == nil {
+		return list, nil
+	}
+	matching := make([]*domain.Todo, 0, len(list))
+	for _, todo := range list {
+		if todo.Completed == *filter.Completed {
+			matching = append(matching, todo)
+		}
+	}
+	return matching, nil
+}
+
+func (t *todoResolver) ID() gql.ID {
+	return gql.ID(t.todo.ID.String())
+}
+
+func (t *todoResolver) Description() string {
+	return t.todo.Description
+}
+
+func (t *todoResolver) Completed() bool {
+	return t.todo.Completed
+}
+
+func (t *todoResolver) CreatedAt() gql.Time {
+	return gql.Time{Time: t.todo.CreatedAt}
+}
+
+func (t *todoResolver) DueAt() *gql.Time {
+	if t.todo.DueAt == nil {
+		return nil
+	}
+	return &gql.Time{Time: *t.todo.DueAt}
+}
+
+func (c *countsResolver) Total() int32 {
+	return int32(len(c.todos))
+}
+
+func (c *countsResolver) Active() int32 {
+	return c.Total() - c.Completed()
+}
+
+func (c *countsResolver) Completed() int32 {
+	var completed int32
+	for _, todo := range c.todos {
+		if todo.Completed {
+			completed++
+		}
+	}
+	return completed
+}
+
+func (e validationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   "VALIDATION_FAILED",
+		"fields": e.Fields,
+	}
+}
+
+func (e permissionError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       "FORBIDDEN",
+		"permission": e.Permission,
+	}
+}
+
+func todoResolvers(list []*domain.Todo) []*todoResolver {
+	resolvers := make([]*todoResolver, len(list))
+	for i, todo := range list {
+		resolvers[i] = &todoResolver{todo: todo}
+	}
+	return resolvers
+}
+
+func parseID(id gql.ID) (uuid.UUID, error) {
+	todoID, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, validationError{domain.ErrValidation{Fields: map[string]string{"id": "must be a valid id"}}}
+	}
+	return todoID, nil
+}
+
+func parseIDs(ids []gql.ID) ([]uuid.UUID, error) {
+	todoIDs := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		todoID, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		todoIDs[i] = todoID
+	}
+	return todoIDs, nil
+}
+
+func resolverError(err error) error {
+	var validationErr domain.ErrValidation
+	if errors.As(err, &validationErr) {
+		return validationError{validationErr}
+	}
+	var permissionErr domain.ErrPermissionDenied
+	if errors.As(err, &permissionErr) {
+		return permissionError{permissionErr}
+	}
+	return err
+}
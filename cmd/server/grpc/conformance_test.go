@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/net/http2"
+
+	"github.com/stackus/todos-htmx-wasm/cmd/server/rest"
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/domain/repositorytest"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/rpc"
+	todosv1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+)
+
+func TestTodoClient_Conformance(t *testing.T) {
+	// h2cClient speaks cleartext HTTP/2, which gRPC needs
+	h2cClient := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		newClient func(t *testing.T, h *handler) *rpc.TodoClient
+	}{
+		"Connect": {
+			newClient: func(t *testing.T, h *handler) *rpc.TodoClient {
+				router := chi.NewRouter()
+				Mount(router, h)
+				server := httptest.NewServer(router)
+				t.Cleanup(server.Close)
+				return rpc.NewTodoClient(server.Client(), server.URL)
+			},
+		},
+		"GRPCWeb": {
+			newClient: func(t *testing.T, h *handler) *rpc.TodoClient {
+				router := chi.NewRouter()
+				Mount(router, h)
+				server := httptest.NewServer(router)
+				t.Cleanup(server.Close)
+				return rpc.NewTodoClient(server.Client(), server.URL, connect.WithGRPCWeb())
+			},
+		},
+		"GRPC": {
+			newClient: func(t *testing.T, h *handler) *rpc.TodoClient {
+				server := httptest.NewServer(NewServer("", h).Handler)
+				t.Cleanup(server.Close)
+				return rpc.NewTodoClient(h2cClient, server.URL, connect.WithGRPC())
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.Run(t, func(t *testing.T) domain.TodoRepository {
+				return tt.newClient(t, &handler{todosSvc: todos.NewService(domain.NewTodos())})
+			})
+		})
+	}
+}
+
+func TestTodoClient_CSRF(t *testing.T) {
+	const session = "session-token"
+	router := chi.NewRouter()
+	router.Use(rest.VerifyCSRF)
+	Mount(router, &handler{todosSvc: todos.NewService(domain.NewTodos())})
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	jar, _ := cookiejar.New(nil)
+	serverURL, _ := url.Parse(server.URL)
+	jar.SetCookies(serverURL, []*http.Cookie{{Name: rest.SessionCookie, Value: session}})
+	client := rpc.NewTodoClient(&http.Client{Jar: jar}, server.URL)
+
+	if _, err := client.Add(context.Background(), "Bake a cake"); !errors.Is(err, domain.ErrInvalidCSRFToken{}) {
+		t.Errorf("Add() without a token error = %v, want %v", err, domain.ErrInvalidCSRFToken{})
+	}
+	ctx := domain.ContextWithCSRFToken(context.Background(), domain.CSRFToken(session))
+	if _, err := client.Add(ctx, "Bake a cake"); err != nil {
+		t.Errorf("Add() error = %v", err)
+	}
+}
+
+func TestNewServer_TLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(NewServer("", &handler{todosSvc: todos.NewService(domain.NewTodos())}).Handler)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client := rpc.NewTodoClient(server.Client(), server.URL, connect.WithGRPC())
+	if _, err := client.Add(context.Background(), "Bake a cake"); err != nil {
+		t.Errorf("Add() over TLS error = %v", err)
+	}
+}
+
+func TestHandler_Import_TooLarge(t *testing.T) {
+	h := &handler{todosSvc: todos.NewService(domain.NewTodos())}
+	req := connect.NewRequest(&todosv1.ImportRequest{Format: domain.FormatJSON, Data: make([]byte, maxImportSize+1)})
+
+	_, err := h.Import(context.Background(), req)
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("Import() error = %v, want %v", err, connect.CodeResourceExhausted)
+	}
+}
@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/rpc"
+	todosv1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+	"github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1/todosv1connect"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
+)
+
+type handler struct {
+	todosSvc todos.Service
+}
+
+const (
+	// maxImportSize is the largest list of todos that may be imported, as over REST
+	maxImportSize = 10 << 20
+	// maxMessageSize is the largest request message, which leaves room around the
+	// largest import for the other fields of the request
+	maxMessageSize = maxImportSize + 64<<10
+)
+
+var _ todosv1connect.TodosServiceHandler = (*handler)(nil)
+
+func NewHandler(todosSvc todos.Service) todosv1connect.TodosServiceHandler {
+	return &handler{todosSvc: todosSvc}
+}
+
+// Mount adds the service to the router for Connect and gRPC-Web clients over HTTP/1
+func Mount(r chi.Router, h todosv1connect.TodosServiceHandler) {
+	path, serviceHandler := todosv1connect.NewTodosServiceHandler(h, connect.WithReadMaxBytes(maxMessageSize))
+	r.Mount(path, serviceHandler)
+}
+
+// NewServer creates a server for the service that also accepts gRPC over cleartext
+// HTTP/2; the middlewares, such as authentication, run in order before the service.
+// Give the server a TLSConfig to serve it over HTTPS instead, as sessions and API
+// tokens must not travel in the clear.
+func NewServer(addr string, h todosv1connect.TodosServiceHandler, middlewares ...func(http.Handler) http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(todosv1connect.NewTodosServiceHandler(h, connect.WithReadMaxBytes(maxMessageSize)))
+
+	var handler http.Handler = mux
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return &http.Server{
+		Addr: addr,
+		Handler: h2c.NewHandler(
+			trace.Middleware(trace.DefaultTracer)(log.RequestID(log.DefaultLogger)(handler)),
+			&http2.Server{},
+		),
+	}
+}
+
+func (h handler) Add(ctx context.Context, req *connect.Request[todosv1.AddRequest]) (*connect.Response[todosv1.AddResponse], error) {
+	todo, err := h.todosSvc.Add(ctx, req.Msg.GetDescription())
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to add todo")
+	}
+
+	return connect.NewResponse(&todosv1.AddResponse{Todo: rpc.TodoToProto(todo)}), nil
+}
+
+func (h handler) Remove(ctx context.Context, req *connect.Request[todosv1.RemoveRequest]) (*connect.Response[todosv1.RemoveResponse], error) {
+	todoID, err := parseID(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.todosSvc.Remove(ctx, todoID); err != nil {
+		return nil, serviceError(ctx, err, "failed to remove todo")
+	}
+
+	return connect.NewResponse(&todosv1.RemoveResponse{}), nil
+}
+
+func (h handler) Update(ctx context.Context, req *connect.Request[todosv1.UpdateRequest]) (*connect.Response[todosv1.UpdateResponse], error) {
+	todoID, err := parseID(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := h.todosSvc.Update(ctx, todoID, req.Msg.GetCompleted(), req.Msg.GetDescription())
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to update todo")
+	}
+
+	return connect.NewResponse(&todosv1.UpdateResponse{Todo: rpc.TodoToProto(todo)}), nil
+}
+
+func (h handler) Search(ctx context.Context, req *connect.Request[todosv1.SearchRequest]) (*connect.Response[todosv1.SearchResponse], error) {
+	todos, err := h.todosSvc.Search(ctx, req.Msg.GetSearch())
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to retrieve todos")
+	}
+
+	return connect.NewResponse(&todosv1.SearchResponse{Todos: rpc.TodosToProto(todos)}), nil
+}
+
+func (h handler) Get(ctx context.Context, req *connect.Request[todosv1.GetRequest]) (*connect.Response[todosv1.GetResponse], error) {
+	todoID, err := parseID(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := h.todosSvc.Get(ctx, todoID)
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to get todo")
+	}
+
+	return connect.NewResponse(&todosv1.GetResponse{Todo: rpc.TodoToProto(todo)}), nil
+}
+
+func (h handler) Sort(ctx context.Context, req *connect.Request[todosv1.SortRequest]) (*connect.Response[todosv1.SortResponse], error) {
+	ids := make([]uuid.UUID, len(req.Msg.GetIds()))
+	for i, id := range req.Msg.GetIds() {
+		todoID, err := parseID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = todoID
+	}
+
+	todos, err := h.todosSvc.Sort(ctx, ids)
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to sort todos")
+	}
+
+	return connect.NewResponse(&todosv1.SortResponse{Todos: rpc.TodosToProto(todos)}), nil
+}
+
+func (h handler) Export(ctx context.Context, req *connect.Request[todosv1.ExportRequest]) (*connect.Response[todosv1.ExportResponse], error) {
+	data, err := h.todosSvc.Export(ctx, req.Msg.GetFormat())
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to export todos")
+	}
+
+	return connect.NewResponse(&todosv1.ExportResponse{Data: data}), nil
+}
+
+func (h handler) Import(ctx context.Context, req *connect.Request[todosv1.ImportRequest]) (*connect.Response[todosv1.ImportResponse], error) {
+	mode, err := domain.ParseImportMode(req.Msg.GetMode())
+	if err != nil {
+		return nil, rpc.ToConnectError(err)
+	}
+
+	if len(req.Msg.GetData()) > maxImportSize {
+		return nil, connect.NewError(connect.CodeResourceExhausted, errors.New("import is larger than "+strconv.Itoa(maxImportSize)+" bytes"))
+	}
+
+	report, err := h.todosSvc.Import(ctx, req.Msg.GetFormat(), bytes.NewReader(req.Msg.GetData()), mode, req.Msg.GetDryRun())
+	if err != nil {
+		return nil, serviceError(ctx, err, "failed to import todos")
+	}
+
+	return connect.NewResponse(&todosv1.ImportResponse{Report: rpc.ImportReportToProto(report)}), nil
+}
+
+func parseID(ctx context.Context, id string) (uuid.UUID, error) {
+	todoID, err := uuid.Parse(id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to parse todoId")
+		return uuid.Nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return todoID, nil
+}
+
+// serviceError logs unexpected service errors and converts them for the client
+func serviceError(ctx context.Context, err error, msg string) error {
+	connectErr := rpc.ToConnectError(err)
+	if connectErr.Code() == connect.CodeInternal {
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+	}
+	return connectErr
+}
@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+type (
+	Handler interface {
+		// Live : GET /healthz
+		Live(w http.ResponseWriter, r *http.Request)
+		// Ready : GET /readyz
+		Ready(w http.ResponseWriter, r *http.Request)
+		// Version : GET /version
+		Version(w http.ResponseWriter, r *http.Request)
+	}
+
+	// Check reports whether a dependency is able to serve requests
+	Check func(ctx context.Context) error
+
+	handler struct {
+		version domain.Version
+		checks  map[string]Check
+	}
+)
+
+// checkTimeout bounds how long a single readiness check may take
+const checkTimeout = 2 * time.Second
+
+// NewHandler creates a handler reporting the version and running the named readiness checks
+func NewHandler(version domain.Version, checks map[string]Check) Handler {
+	return &handler{
+		version: version,
+		checks:  checks,
+	}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Get("/healthz", h.Live)
+	r.Get("/readyz", h.Ready)
+	r.Get("/version", h.Version)
+}
+
+// RepositoryCheck checks that the repository can be read and, for repositories that
+// implement domain.WritableChecker, that it can store changes
+func RepositoryCheck(repo domain.TodoRepository) Check {
+	return func(ctx context.Context) error {
+		if _, err := repo.All(ctx); err != nil {
+			return err
+		}
+		if checker, ok := repo.(domain.WritableChecker); ok {
+			return checker.CheckWritable(ctx)
+		}
+		return nil
+	}
+}
+
+func (h handler) Live(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]string{"status": "ok"})
+}
+
+func (h handler) Ready(w http.ResponseWriter, r *http.Request) {
+	type readyResponse struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := readyResponse{Status: "ok", Checks: make(map[string]string, len(names))}
+	for _, name := range names {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := h.checks[name](ctx)
+		cancel()
+		if err != nil {
+			log.Ctx(r.Context()).Warn().Err(err).Str("Check", name).Msg("readiness check failed")
+			resp.Status = "unavailable"
+			resp.Checks[name] = err.Error()
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+
+	if resp.Status != "ok" {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, resp)
+}
+
+func (h handler) Version(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, h.version)
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func Test_handler(t *testing.T) {
+	tests := map[string]struct {
+		target     string
+		checks     map[string]Check
+		wantStatus int
+		wantBody   map[string]any
+	}{
+		"Live": {
+			target:     "/healthz",
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]any{"status": "ok"},
+		},
+		"Ready": {
+			target: "/readyz",
+			checks: map[string]Check{
+				"repository": func(context.Context) error { return nil },
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]any{"status": "ok", "checks": map[string]any{"repository": "ok"}},
+		},
+		"NotReady": {
+			target: "/readyz",
+			checks: map[string]Check{
+				"repository": func(context.Context) error { return errors.New("unreachable") },
+				"other":      func(context.Context) error { return nil },
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   map[string]any{"status": "unavailable", "checks": map[string]any{"repository": "unreachable", "other": "ok"}},
+		},
+		"Version": {
+			target:     "/version",
+			wantStatus: http.StatusOK,
+			wantBody: map[string]any{
+				"Module": "github.com/stackus/todos-htmx-wasm", "Version": "v1.2.3", "Revision": "abc123",
+				"Modified": false, "API": domain.APIVersion,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := chi.NewRouter()
+			Mount(router, NewHandler(domain.Version{
+				Module:   "github.com/stackus/todos-htmx-wasm",
+				Version:  "v1.2.3",
+				Revision: "abc123",
+				API:      domain.APIVersion,
+			}, tt.checks))
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.wantBody)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("body = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+type writableRepository struct {
+	*domain.MockTodoRepository
+	err error
+}
+
+func (r writableRepository) CheckWritable(context.Context) error {
+	return r.err
+}
+
+func TestRepositoryCheck(t *testing.T) {
+	tests := map[string]struct {
+		repo    func(m *domain.MockTodoRepository) domain.TodoRepository
+		wantErr bool
+	}{
+		"Reachable": {
+			repo: func(m *domain.MockTodoRepository) domain.TodoRepository {
+				m.On("All", mock.Anything).Return([]*domain.Todo{}, nil)
+				return m
+			},
+		},
+		"Unreachable": {
+			repo: func(m *domain.MockTodoRepository) domain.TodoRepository {
+				m.On("All", mock.Anything).Return(nil, errors.New("unreachable"))
+				return m
+			},
+			wantErr: true,
+		},
+		"Writable": {
+			repo: func(m *domain.MockTodoRepository) domain.TodoRepository {
+				m.On("All", mock.Anything).Return([]*domain.Todo{}, nil)
+				return writableRepository{MockTodoRepository: m}
+			},
+		},
+		"ReadOnly": {
+			repo: func(m *domain.MockTodoRepository) domain.TodoRepository {
+				m.On("All", mock.Anything).Return([]*domain.Todo{}, nil)
+				return writableRepository{MockTodoRepository: m, err: errors.New("read-only file system")}
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			check := RepositoryCheck(tt.repo(domain.NewMockTodoRepository(t)))
+			if err := check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,60 +1,174 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/stackus/todos-htmx-wasm/cmd/server/graphql"
+	"github.com/stackus/todos-htmx-wasm/cmd/server/grpc"
+	"github.com/stackus/todos-htmx-wasm/cmd/server/health"
 	"github.com/stackus/todos-htmx-wasm/cmd/server/rest"
 	"github.com/stackus/todos-htmx-wasm/internal/assets"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+	"github.com/stackus/todos-htmx-wasm/internal/certs"
+	"github.com/stackus/todos-htmx-wasm/internal/config"
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/lists"
 	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/features/users"
+	"github.com/stackus/todos-htmx-wasm/internal/lifecycle"
 	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/metrics"
+	"github.com/stackus/todos-htmx-wasm/internal/security"
 	"github.com/stackus/todos-htmx-wasm/internal/templates/pages"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
 )
 
 func main() {
-	var port = ":3000"
+	var printConfig bool
+	flag.BoolVar(&printConfig, "print-config", printConfig, "print the effective configuration as YAML and exit")
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:], os.LookupEnv)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if printConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	flag.StringVar(&port, "port", port, "port to listen on")
-	flag.Parse()
+	logFile, err := log.Configure(cfg.Log)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if cfg.Trace.Stdout {
+		trace.DefaultTracer = trace.NewTracer(trace.NewStdoutExporter(os.Stdout))
+	}
+
+	reg := metrics.NewRegistry()
+	metrics.RegisterRuntime(reg)
+	manager := lifecycle.NewManager(log.DefaultLogger, cfg.HTTP.ShutdownTimeout)
 
 	router := chi.NewRouter()
 	router.Use(
-		log.WebLogger(log.DefaultLogger),
+		trace.Middleware(trace.DefaultTracer),
+		log.RequestID(log.DefaultLogger),
+		log.WebLogger(log.DefaultLogger, cfg.Log.WebLoggerOptions()...),
+		metrics.Middleware(reg),
+		manager.Middleware,
+		security.Headers(cfg.Security),
 		middleware.Recoverer,
-		middleware.Compress(5),
+		middleware.Compress(cfg.HTTP.CompressionLevel),
 	)
 
+	ctx := context.Background()
 	list := domain.NewTodos()
-	list.Add("Bake a cake")
-	list.Add("Feed the cat")
-	list.Add("Take out the trash")
 
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		if err := pages.LoadingPage().Render(r.Context(), w); err != nil {
-			log.Error().Err(err).Msg("failed to render loading page")
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to render loading page")
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	rest.Mount(router, rest.NewHandler(todos.NewService(list)))
+	security.Mount(router)
 	assets.Mount(router)
+	version := domain.ReadVersion()
+	version.Client = manifest.Hash("client.wasm")
+	health.Mount(router, health.NewHandler(version, map[string]health.Check{
+		"repository": health.RepositoryCheck(list),
+		"lifecycle":  manager.Ready,
+	}))
+	repo := metrics.NewTodoRepository(list, reg)
+	accounts := domain.NewUsers()
+	members := domain.NewMembers()
+	todosSvc := todos.NewService(repo, todos.WithMembers(members))
+	usersSvc := users.NewService(accounts, domain.NewSessions(), domain.NewTokens(), users.WithSeed(repo, cfg.Seed))
+	requireUser := rest.RequireUser(usersSvc)
+	rpcHandler := grpc.NewHandler(todosSvc)
 
-	server := &http.Server{
-		Addr:    port,
-		Handler: http.TimeoutHandler(router, 30*time.Second, "request timed out"),
-	}
+	rest.MountAuth(router, rest.NewAuthHandler(usersSvc))
+	router.Group(func(r chi.Router) {
+		// API tokens are only accepted by the REST routes, which check their scopes
+		r.Use(rest.CheckAPIVersion, rest.AcceptTokens(usersSvc), rest.VerifyCSRF, requireUser)
+		rest.Mount(r, rest.NewHandler(todosSvc))
+		rest.MountLists(r, rest.NewListsHandler(lists.NewService(members, accounts)))
+	})
+	router.Group(func(r chi.Router) {
+		r.Use(rest.CheckAPIVersion, rest.VerifyCSRF, requireUser, rest.SelectList)
+		grpc.Mount(r, rpcHandler)
+		graphql.Mount(r, graphql.NewHandler(todosSvc))
+	})
 
-	// Display the localhost address and port
-	fmt.Printf("Listening on http://localhost%s\n", port)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 
-	if err := server.ListenAndServe(); err != nil {
-		fmt.Println(err)
+	var handler http.Handler = http.TimeoutHandler(router, cfg.HTTP.RequestTimeout, "request timed out")
+	grpcServer := grpc.NewServer(cfg.GRPC.Port, rpcHandler, rest.CheckAPIVersion, rest.VerifyCSRF, requireUser, rest.SelectList)
+	if cfg.TLS.Enabled {
+		reloader, err := loadCertificate(cfg.TLS)
+		if err != nil {
+			log.DefaultLogger.Fatal().Err(err).Msg("failed to load the TLS certificate")
+		}
+		go reloader.Watch(ctx, cfg.TLS.ReloadInterval)
+		manager.AddServer("https", &http.Server{
+			Addr:      cfg.TLS.Port,
+			Handler:   handler,
+			TLSConfig: reloader.TLSConfig(),
+		})
+		handler = certs.Redirect(cfg.TLS.Port)
+		// gRPC clients send the same sessions and tokens, so they get the same TLS
+		grpcServer.TLSConfig = reloader.TLSConfig()
+	}
+	manager.AddServer("http", &http.Server{
+		Addr:    cfg.HTTP.Port,
+		Handler: handler,
+	})
+	manager.AddServer("grpc", grpcServer)
+	if cfg.Metrics.Port != "" {
+		// the metrics count the todos of every user, so they have a listener of their own
+		admin := http.NewServeMux()
+		admin.Handle("/metrics", metrics.Handler(reg))
+		manager.AddServer("metrics", &http.Server{
+			Addr:    cfg.Metrics.Port,
+			Handler: admin,
+		})
+	}
+	manager.AddCloser("repository", lifecycle.RepositoryCloser(list))
+
+	err = manager.Run(ctx)
+	stop()
+	_ = logFile.Close()
+	if err != nil {
 		os.Exit(1)
 	}
 }
+
+// loadCertificate loads the configured certificate, or the development certificate,
+// which is generated on first run
+func loadCertificate(cfg config.TLSConfig) (*certs.Reloader, error) {
+	certFile, keyFile := cfg.CertFile, cfg.KeyFile
+	if certFile == "" {
+		var created bool
+		var err error
+		certFile, keyFile, created, err = certs.DevCertificate(cfg.DevCertDir)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			log.DefaultLogger.Info().Str("Dir", cfg.DevCertDir).Msg("generated a self-signed development certificate")
+		}
+	}
+	return certs.NewReloader(certFile, keyFile)
+}
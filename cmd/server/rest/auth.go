@@ -0,0 +1,369 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/users"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+const (
+	// SessionCookie names the cookie that carries the session token
+	SessionCookie = "session"
+	// accessTokenParam names the query parameter the calendar feed takes an API token in
+	accessTokenParam = "access_token"
+)
+
+type (
+	AuthHandler interface {
+		// Register : POST /auth/register
+		Register(w http.ResponseWriter, r *http.Request)
+		// Login : POST /auth/login
+		Login(w http.ResponseWriter, r *http.Request)
+		// Logout : POST /auth/logout
+		Logout(w http.ResponseWriter, r *http.Request)
+		// Me : GET /auth/me
+		Me(w http.ResponseWriter, r *http.Request)
+		// CSRF : GET /auth/csrf
+		CSRF(w http.ResponseWriter, r *http.Request)
+		// Tokens : GET /auth/tokens
+		Tokens(w http.ResponseWriter, r *http.Request)
+		// CreateToken : POST /auth/tokens
+		CreateToken(w http.ResponseWriter, r *http.Request)
+		// RevokeToken : DELETE /auth/tokens/{tokenId}
+		RevokeToken(w http.ResponseWriter, r *http.Request)
+	}
+
+	authHandler struct {
+		usersSvc users.Service
+	}
+)
+
+func NewAuthHandler(usersSvc users.Service) AuthHandler {
+	return &authHandler{usersSvc: usersSvc}
+}
+
+func MountAuth(r chi.Router, h AuthHandler) {
+	r.Route("/auth", func(r chi.Router) {
+		r.Post("/register", h.Register)
+		r.Post("/login", h.Login)
+		r.With(VerifyCSRF).Post("/logout", h.Logout)
+		r.Get("/me", h.Me)
+		r.Get("/csrf", h.CSRF)
+		r.Route("/tokens", func(r chi.Router) {
+			r.Use(VerifyCSRF)
+			r.Get("/", h.Tokens)
+			r.Post("/", h.CreateToken)
+			r.Delete("/{tokenId}", h.RevokeToken)
+		})
+	})
+}
+
+// RequireUser responds with 401 Unauthorized to requests without a valid session and
+// adds the user to the context of the others; requests already authenticated by
+// AcceptTokens are let through
+func RequireUser(usersSvc users.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if domain.UserFromContext(r.Context()) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			user, err := usersSvc.Authenticate(r.Context(), sessionToken(r))
+			if err != nil {
+				log.Ctx(r.Context()).Error().Err(err).Msg("failed to authenticate request")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if user == nil {
+				http.Error(w, domain.ErrUnauthenticated{}.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithUser(r.Context(), user)))
+		})
+	}
+}
+
+// AcceptTokens authenticates requests that carry an API token as a bearer token in the
+// Authorization header, ahead of RequireUser; requests with an unknown, expired or
+// revoked token are refused with 401 Unauthorized. Only routes that check the scopes
+// of the token, as those added by Mount do, may be served behind it.
+//
+// Calendar apps can neither sign in nor set headers, so the calendar feed also takes
+// the token in the access_token query parameter. URLs end up in logs and histories,
+// so a token given there may only have the read scope.
+func AcceptTokens(usersSvc users.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			inURL := false
+			if !found && isFeed(r) {
+				secret = r.URL.Query().Get(accessTokenParam)
+				found, inURL = secret != "", true
+			}
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
+			user, token, err := usersSvc.AuthenticateToken(r.Context(), strings.TrimSpace(secret))
+			if err != nil {
+				log.Ctx(r.Context()).Error().Err(err).Msg("failed to authenticate request")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if user == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, domain.ErrUnauthenticated{}.Error(), http.StatusUnauthorized)
+				return
+			}
+			if inURL && token.Allows(domain.ScopeWrite) {
+				http.Error(w, "API tokens in URLs may only have the "+string(domain.ScopeRead)+" scope", http.StatusForbidden)
+				return
+			}
+			ctx := domain.ContextWithToken(domain.ContextWithUser(r.Context(), user), token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// isFeed reports whether the request reads the calendar feed
+func isFeed(r *http.Request) bool {
+	return (r.Method == http.MethodGet || r.Method == http.MethodHead) && r.URL.Path == calendarPath
+}
+
+// VerifyCSRF responds with 403 Forbidden to requests that may change something and are
+// made with a session, but lack its CSRF token in the X-CSRF-Token header; browsers
+// send the session cookie with requests that other sites make, while the token is only
+// known to the pages of the site. Requests authenticated by AcceptTokens are let
+// through, as browsers never send API tokens on their own.
+func VerifyCSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+			return
+		}
+		session := sessionToken(r)
+		if session == "" || domain.TokenFromContext(r.Context()) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !domain.ValidCSRFToken(session, r.Header.Get(domain.CSRFHeader)) {
+			w.Header().Set(domain.CSRFHeader, "invalid")
+			connectError(w, http.StatusForbidden, "permission_denied", domain.ErrInvalidCSRFToken{}.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireScope responds with 403 Forbidden to requests made with an API token that
+// lacks the scope; requests made with a session may do anything
+func requireScope(scope domain.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token := domain.TokenFromContext(r.Context()); token != nil && !token.Allows(scope) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+string(scope)+`"`)
+				http.Error(w, domain.ErrInsufficientScope{Scope: scope}.Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type credentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (h authHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var request credentialsRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), decodeErrorStatus(err))
+		return
+	}
+
+	user, token, err := h.usersSvc.Register(r.Context(), request.Username, request.Password)
+	if err != nil {
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to register user")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.setSession(w, r, token)
+	respond(w, r, http.StatusCreated, user)
+}
+
+func (h authHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var request credentialsRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), decodeErrorStatus(err))
+		return
+	}
+
+	user, token, err := h.usersSvc.Login(r.Context(), request.Username, request.Password)
+	if err != nil {
+		var credentialsErr domain.ErrInvalidCredentials
+		if errors.As(err, &credentialsErr) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to log in")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.setSession(w, r, token)
+	respond(w, r, http.StatusOK, user)
+}
+
+func (h authHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if token := sessionToken(r); token != "" {
+		if err := h.usersSvc.Logout(r.Context(), token); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to log out")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.SetCookie(w, h.sessionCookie(r, "", -1))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h authHandler) Me(w http.ResponseWriter, r *http.Request) {
+	h.requireUser(w, r, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, http.StatusOK, domain.UserFromContext(r.Context()))
+	})
+}
+
+func (h authHandler) CSRF(w http.ResponseWriter, r *http.Request) {
+	type responseType struct {
+		Token string `json:"token"`
+	}
+	h.requireUser(w, r, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		respond(w, r, http.StatusOK, responseType{Token: domain.CSRFToken(sessionToken(r))})
+	})
+}
+
+type createTokenRequest struct {
+	Name      string         `json:"name"`
+	Scopes    []domain.Scope `json:"scopes"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
+
+// createTokenResponse is the only response that carries the secret of a token
+type createTokenResponse struct {
+	*domain.APIToken
+	Token string
+}
+
+func (h authHandler) Tokens(w http.ResponseWriter, r *http.Request) {
+	h.requireUser(w, r, func(w http.ResponseWriter, r *http.Request) {
+		tokens, err := h.usersSvc.Tokens(r.Context())
+		if err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to list API tokens")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		respond(w, r, http.StatusOK, tokens)
+	})
+}
+
+func (h authHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	h.requireUser(w, r, func(w http.ResponseWriter, r *http.Request) {
+		var request createTokenRequest
+		if err := decodeRequest(w, r, &request); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+			http.Error(w, err.Error(), decodeErrorStatus(err))
+			return
+		}
+
+		token, secret, err := h.usersSvc.CreateToken(r.Context(), request.Name, request.Scopes, request.ExpiresAt)
+		if err != nil {
+			var validationErr domain.ErrValidation
+			if errors.As(err, &validationErr) {
+				respond(w, r, http.StatusUnprocessableEntity, validationErr)
+				return
+			}
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to create API token")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		respond(w, r, http.StatusCreated, createTokenResponse{APIToken: token, Token: secret})
+	})
+}
+
+func (h authHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	h.requireUser(w, r, func(w http.ResponseWriter, r *http.Request) {
+		tokenID, err := uuid.Parse(chi.URLParam(r, "tokenId"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		token, err := h.usersSvc.RevokeToken(r.Context(), tokenID)
+		if err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to revoke API token")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if token == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// requireUser serves the request with next when it is made with a session; API
+// tokens are not accepted by the routes of the AuthHandler
+func (h authHandler) requireUser(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	RequireUser(h.usersSvc)(next).ServeHTTP(w, r)
+}
+
+// setSession sets the session cookie, and hands clients that keep it the CSRF token
+// of the session
+func (h authHandler) setSession(w http.ResponseWriter, r *http.Request, token string) {
+	http.SetCookie(w, h.sessionCookie(r, token, int(h.usersSvc.SessionTTL().Seconds())))
+	w.Header().Set(domain.CSRFHeader, domain.CSRFToken(token))
+}
+
+// sessionToken returns the token of the session cookie, or an empty string
+func sessionToken(r *http.Request) string {
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// sessionCookie keeps the token away from scripts and cross-site requests, and
+// off plain HTTP when the server is reached over HTTPS
+func (h authHandler) sessionCookie(r *http.Request, token string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
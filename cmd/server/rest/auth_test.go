@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func TestAuthHandler(t *testing.T) {
+	type request struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}
+	tests := map[string]struct {
+		requests   []request
+		wantCookie bool
+	}{
+		"Register": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"Alice","password":"correct horse"}`, wantStatus: http.StatusCreated},
+				{method: http.MethodGet, path: "/auth/me", wantStatus: http.StatusOK},
+			},
+			wantCookie: true,
+		},
+		"RegisterInvalid": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"al","password":"short"}`, wantStatus: http.StatusUnprocessableEntity},
+				{method: http.MethodGet, path: "/auth/me", wantStatus: http.StatusUnauthorized},
+			},
+		},
+		"RegisterTaken": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","password":"correct horse"}`, wantStatus: http.StatusCreated},
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"ALICE","password":"correct horse"}`, wantStatus: http.StatusUnprocessableEntity},
+			},
+			wantCookie: true,
+		},
+		"Login": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","password":"correct horse"}`, wantStatus: http.StatusCreated},
+				{method: http.MethodPost, path: "/auth/logout", wantStatus: http.StatusNoContent},
+				{method: http.MethodPost, path: "/auth/login", body: `{"username":"alice","password":"correct horse"}`, wantStatus: http.StatusOK},
+				{method: http.MethodGet, path: "/auth/me", wantStatus: http.StatusOK},
+			},
+			wantCookie: true,
+		},
+		"LoginWrongPassword": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","password":"correct horse"}`, wantStatus: http.StatusCreated},
+				{method: http.MethodPost, path: "/auth/logout", wantStatus: http.StatusNoContent},
+				{method: http.MethodPost, path: "/auth/login", body: `{"username":"alice","password":"wrong password"}`, wantStatus: http.StatusUnauthorized},
+				{method: http.MethodGet, path: "/auth/me", wantStatus: http.StatusUnauthorized},
+			},
+		},
+		"Logout": {
+			requests: []request{
+				{method: http.MethodPost, path: "/auth/register", body: `{"username":"alice","password":"correct horse"}`, wantStatus: http.StatusCreated},
+				{method: http.MethodPost, path: "/auth/logout", wantStatus: http.StatusNoContent},
+				{method: http.MethodGet, path: "/auth/me", wantStatus: http.StatusUnauthorized},
+				{method: http.MethodGet, path: "/todos", wantStatus: http.StatusUnauthorized},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newAccountsServer(t)
+			jar, _ := cookiejar.New(nil)
+			client := &http.Client{Jar: jar}
+
+			for _, req := range tt.requests {
+				resp, body := send(t, client, req.method, server.URL+req.path, req.body, nil)
+				if resp.StatusCode != req.wantStatus {
+					t.Errorf("%s %s status = %d, want %d: %s", req.method, req.path, resp.StatusCode, req.wantStatus, strings.TrimSpace(body))
+				}
+				if strings.Contains(body, "PasswordHash") {
+					t.Errorf("%s %s = %s, which shows the password hash", req.method, req.path, body)
+				}
+				for _, cookie := range resp.Cookies() {
+					if cookie.Name == SessionCookie && (!cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode) {
+						t.Errorf("%s %s session cookie = %v, want HttpOnly and SameSite=Lax", req.method, req.path, cookie)
+					}
+				}
+			}
+
+			serverURL, _ := url.Parse(server.URL)
+			if gotCookie := len(jar.Cookies(serverURL)) == 1; gotCookie != tt.wantCookie {
+				t.Errorf("session cookie kept = %v, want %v", gotCookie, tt.wantCookie)
+			}
+		})
+	}
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	server := newAccountsServer(t)
+	alice := register(t, server, "alice")
+	bob := register(t, server, "bob")
+	_, apiToken := createToken(t, server.URL, alice, domain.ScopeWrite)
+	serverURL, _ := url.Parse(server.URL)
+	bobToken := domain.CSRFToken(bob.Jar.Cookies(serverURL)[0].Value)
+
+	// each request is made with alice's session
+	tests := map[string]struct {
+		method      string
+		path        string
+		body        string
+		header      http.Header
+		wantStatus  int
+		wantInvalid bool
+	}{
+		"Token":         {method: http.MethodPost, path: "/todos", body: `{"description":"Bake a cake"}`, wantStatus: http.StatusCreated},
+		"Missing":       {method: http.MethodPost, path: "/todos", body: `{"description":"Bake a cake"}`, header: csrf(""), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"Wrong":         {method: http.MethodPost, path: "/todos", body: `{"description":"Bake a cake"}`, header: csrf("forged"), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"OtherSession":  {method: http.MethodPost, path: "/todos", body: `{"description":"Bake a cake"}`, header: csrf(bobToken), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"Delete":        {method: http.MethodDelete, path: "/todos/" + uuid.NewString(), header: csrf(""), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"Logout":        {method: http.MethodPost, path: "/auth/logout", header: csrf(""), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"CreateToken":   {method: http.MethodPost, path: "/auth/tokens", body: `{"name":"forged","scopes":["todos:admin"]}`, header: csrf(""), wantStatus: http.StatusForbidden, wantInvalid: true},
+		"SafeMethod":    {method: http.MethodGet, path: "/todos", header: csrf(""), wantStatus: http.StatusOK},
+		"APIToken":      {method: http.MethodPost, path: "/todos", body: `{"description":"Bake a cake"}`, header: http.Header{domain.CSRFHeader: []string{""}, "Authorization": []string{"Bearer " + apiToken}}, wantStatus: http.StatusCreated},
+		"SignedOutForm": {method: http.MethodPost, path: "/auth/login", body: `{"username":"alice","password":"correct horse"}`, header: csrf(""), wantStatus: http.StatusOK},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, body := send(t, alice, tt.method, server.URL+tt.path, tt.body, tt.header)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.wantStatus, strings.TrimSpace(body))
+			}
+			if gotInvalid := resp.Header.Get(domain.CSRFHeader) == "invalid"; gotInvalid != tt.wantInvalid {
+				t.Errorf("%s %s invalid token header = %v, want %v", tt.method, tt.path, gotInvalid, tt.wantInvalid)
+			}
+		})
+	}
+
+	t.Run("Fetch", func(t *testing.T) {
+		_, body := send(t, bob, http.MethodGet, server.URL+"/auth/csrf", "", nil)
+		if !strings.Contains(body, `"token":"`+bobToken+`"`) {
+			t.Errorf("GET /auth/csrf = %s, want bob's token", body)
+		}
+		resp, _ := send(t, http.DefaultClient, http.MethodGet, server.URL+"/auth/csrf", "", nil)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("anonymous GET /auth/csrf status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+		}
+	})
+}
+
+// csrf sets the CSRF token of a request, in place of the one of the session
+func csrf(token string) http.Header {
+	return http.Header{domain.CSRFHeader: []string{token}}
+}
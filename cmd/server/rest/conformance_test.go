@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/domain/repositorytest"
+)
+
+func TestTodoApi_Conformance(t *testing.T) {
+	tests := map[string]struct {
+		options []domain.TodoApiOption
+		token   bool
+	}{
+		"JSON":  {},
+		"CBOR":  {options: []domain.TodoApiOption{domain.WithCBOR()}},
+		"Token": {token: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.Run(t, func(t *testing.T) domain.TodoRepository {
+				server := newAccountsServer(t)
+
+				jar, _ := cookiejar.New(nil)
+				api := domain.NewTodoApi(server.URL, append(tt.options, domain.WithCookieJar(jar))...)
+				if _, err := api.Register(context.Background(), "alice", "correct horse"); err != nil {
+					t.Fatalf("Register() error = %v", err)
+				}
+				if tt.token {
+					_, secret := createToken(t, server.URL, &http.Client{Jar: jar}, domain.ScopeAdmin)
+					return domain.NewTodoApi(server.URL, append(tt.options, domain.WithToken(secret))...)
+				}
+				return api
+			})
+		})
+	}
+}
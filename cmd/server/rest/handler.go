@@ -1,20 +1,30 @@
 package rest
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 	"github.com/segmentio/encoding/json"
 
+	"github.com/stackus/todos-htmx-wasm/internal/assets/variants"
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
 	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
 	"github.com/stackus/todos-htmx-wasm/internal/log"
 )
 
 type (
 	Handler interface {
-		// All : GET /todos
+		// All : GET /todos?search=
 		All(w http.ResponseWriter, r *http.Request)
 		// Search : GET /todos
 		Search(w http.ResponseWriter, r *http.Request)
@@ -30,6 +40,12 @@ type (
 		Delete(w http.ResponseWriter, r *http.Request)
 		// Sort : POST /todos/sort
 		Sort(w http.ResponseWriter, r *http.Request)
+		// Export : GET /todos/export
+		Export(w http.ResponseWriter, r *http.Request)
+		// Import : POST /todos/import
+		Import(w http.ResponseWriter, r *http.Request)
+		// Calendar : GET /todos.ics
+		Calendar(w http.ResponseWriter, r *http.Request)
 	}
 
 	handler struct {
@@ -37,30 +53,57 @@ type (
 	}
 )
 
+const (
+	// maxRequestSize is the largest request body the handlers will decode
+	maxRequestSize = 64 << 10
+	// maxImportSize is the largest list of todos that may be imported
+	maxImportSize = 10 << 20
+	// calendarPath is the iCalendar feed of the list, which calendar apps subscribe to
+	calendarPath = "/todos.ics"
+)
+
 func NewHandler(todosSvc todos.Service) Handler {
 	return &handler{todosSvc: todosSvc}
 }
 
 func Mount(r chi.Router, h Handler) {
+	// the non-idempotent POST routes accept an Idempotency-Key
+	idempotent := idempotency(newIdempotencyStore(idempotencyKeyTTL, maxIdempotencyEntries, maxIdempotencyBytes))
+	// requests made with an API token need the scope of the route
+	read := requireScope(domain.ScopeRead)
+	write := requireScope(domain.ScopeWrite)
+	admin := requireScope(domain.ScopeAdmin)
+
+	r = r.With(SelectList)
+	r.With(read).Get(calendarPath, h.Calendar)
 	r.Route("/todos", func(r chi.Router) {
-		r.Get("/", h.All)
-		r.Post("/", h.Create)
+		r.With(read).Get("/", h.All)
+		r.With(write, idempotent).Post("/", h.Create)
 		r.Route("/{todoId}", func(r chi.Router) {
-			r.Patch("/", h.Update)
-			r.Post("/edit", h.Update)
-			r.Get("/", h.Get)
-			r.Delete("/", h.Delete)
-			r.Post("/delete", h.Delete)
+			r.With(write).Patch("/", h.Update)
+			r.With(write, idempotent).Post("/edit", h.Update)
+			r.With(read).Get("/", h.Get)
+			r.With(write).Delete("/", h.Delete)
+			r.With(write, idempotent).Post("/delete", h.Delete)
 		})
-		r.Post("/sort", h.Sort)
+		r.With(write, idempotent).Post("/sort", h.Sort)
+		r.With(read).Get("/export", h.Export)
+		r.With(admin).Post("/import", h.Import)
 	})
 }
 
 func (h handler) All(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.todosSvc.Search(r.Context(), "")
+	todos, err := h.todosSvc.Search(r.Context(), r.URL.Query().Get("search"))
 	if err != nil {
-		log.Error().Err(err).Msg("failed to retrieve todos")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to retrieve todos")
+		return
+	}
+
+	if codec := negotiateCodec(w, r); codec != nil {
+		w.Header().Set("Content-Type", codec.ContentType())
+		if err := codec.Encode(w, todos); err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to encode todos")
+		}
 		return
 	}
 
@@ -72,31 +115,30 @@ func (h handler) Sort(w http.ResponseWriter, r *http.Request) {
 		Ids []uuid.UUID `json:"ids"`
 	}
 	var request requestType
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Error().Err(err).Msg("failed to decode request")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(w, r, &request); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), decodeErrorStatus(err))
 		return
 	}
 
 	todos, err := h.todosSvc.Sort(r.Context(), request.Ids)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to sort todos")
 		return
 	}
 
-	render.JSON(w, r, todos)
+	respond(w, r, http.StatusOK, todos)
 }
 
 func (h handler) Search(w http.ResponseWriter, r *http.Request) {
 	var search = r.URL.Query().Get("search")
 	todos, err := h.todosSvc.Search(r.Context(), search)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to retrieve todos")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to retrieve todos")
 		return
 	}
 
-	render.JSON(w, r, todos)
+	respond(w, r, http.StatusOK, todos)
 }
 
 func (h handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -104,19 +146,26 @@ func (h handler) Create(w http.ResponseWriter, r *http.Request) {
 		Description string `json:"description"`
 	}
 	var request requestType
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Error().Err(err).Msg("failed to decode request")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(w, r, &request); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), decodeErrorStatus(err))
 		return
 	}
 
 	todo, err := h.todosSvc.Add(r.Context(), request.Description)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to add todo")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to add todo")
 		return
 	}
 
+	if writeCBOR(w, r, http.StatusCreated, todo) {
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 	render.JSON(w, r, todo)
 }
@@ -127,9 +176,9 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 		Description string `json:"description"`
 	}
 	var request requestType
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Error().Err(err).Msg("failed to decode request")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeRequest(w, r, &request); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to decode request")
+		http.Error(w, err.Error(), decodeErrorStatus(err))
 		return
 	}
 
@@ -137,19 +186,23 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 	var todoID uuid.UUID
 	var err error
 	if todoID, err = uuid.Parse(id); err != nil {
-		log.Error().Err(err).Msg("failed to parse todoId")
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to parse todoId")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	todo, err := h.todosSvc.Update(r.Context(), todoID, request.Completed, request.Description)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to update todo")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to update todo")
 		return
 	}
 
-	render.JSON(w, r, todo)
+	respond(w, r, http.StatusOK, todo)
 }
 
 func (h handler) Get(w http.ResponseWriter, r *http.Request) {
@@ -157,19 +210,18 @@ func (h handler) Get(w http.ResponseWriter, r *http.Request) {
 	var todoID uuid.UUID
 	var err error
 	if todoID, err = uuid.Parse(id); err != nil {
-		log.Error().Err(err).Msg("failed to parse todoId")
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to parse todoId")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	todo, err := h.todosSvc.Get(r.Context(), todoID)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to get todo")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to get todo")
 		return
 	}
 
-	render.JSON(w, r, todo)
+	respond(w, r, http.StatusOK, todo)
 }
 
 func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -177,16 +229,181 @@ func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
 	var todoID uuid.UUID
 	var err error
 	if todoID, err = uuid.Parse(id); err != nil {
-		log.Error().Err(err).Msg("failed to parse todoId")
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to parse todoId")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	if err := h.todosSvc.Remove(r.Context(), todoID); err != nil {
-		log.Error().Err(err).Msg("failed to remove todo")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "failed to remove todo")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h handler) Export(w http.ResponseWriter, r *http.Request) {
+	var format = r.URL.Query().Get("format")
+	if format == "" {
+		format = domain.FormatJSON
+	}
+	codec, err := domain.CodecFor(format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.todosSvc.Export(r.Context(), format)
+	if err != nil {
+		serviceError(w, r, err, "failed to export todos")
+		return
+	}
+
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="todos.`+codec.Extension()+`"`)
+	_, _ = w.Write(data)
+}
+
+func (h handler) Import(w http.ResponseWriter, r *http.Request) {
+	var query = r.URL.Query()
+	var format = query.Get("format")
+	if format == "" {
+		format = domain.FormatJSON
+	}
+	mode, err := domain.ParseImportMode(query.Get("mode"))
+	if err != nil {
+		respond(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(query.Get("dry_run"))
+
+	report, err := h.todosSvc.Import(r.Context(), format, http.MaxBytesReader(w, r.Body, maxImportSize), mode, dryRun)
+	if err != nil {
+		var validationErr domain.ErrValidation
+		var formatErr domain.ErrUnsupportedFormat
+		var decodeErr domain.ErrUnmarshaling
+		switch {
+		case errors.As(err, &validationErr):
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+		case errors.As(err, &formatErr), errors.As(err, &decodeErr):
+			http.Error(w, err.Error(), decodeErrorStatus(err))
+		default:
+			serviceError(w, r, err, "failed to import todos")
+		}
+		return
+	}
+
+	respond(w, r, http.StatusOK, report)
+}
+
+func (h handler) Calendar(w http.ResponseWriter, r *http.Request) {
+	data, err := h.todosSvc.Export(r.Context(), domain.FormatICalendar)
+	if err != nil {
+		serviceError(w, r, err, "failed to export calendar")
+		return
+	}
+	codec, _ := domain.CodecFor(domain.FormatICalendar)
+
+	// calendar clients poll the feed; the ETag lets them skip unchanged lists
+	sum := sha256.Sum256(data)
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	http.ServeContent(w, r, "todos.ics", time.Time{}, bytes.NewReader(data))
+}
+
+// negotiateCodec returns the codec of the most acceptable format, or nil when JSON
+// should be used, and marks the response as varying with the Accept header
+func negotiateCodec(w http.ResponseWriter, r *http.Request) domain.TodoCodec {
+	w.Header().Add("Vary", "Accept")
+	var best domain.TodoCodec
+	var bestQ float64
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(accept, ";")
+		// the first of the equally acceptable formats wins
+		q := variants.QValue(params)
+		if q <= bestQ {
+			continue
+		}
+		switch mediaType = strings.TrimSpace(mediaType); mediaType {
+		case "", "*/*", "application/*", "application/json":
+			best, bestQ = nil, q
+		default:
+			if codec, err := domain.CodecForMediaType(mediaType); err == nil {
+				best, bestQ = codec, q
+			}
+		}
+	}
+	return best
+}
+
+// decodeRequest decodes a JSON request body, or a CBOR one when the Content-Type says so
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) error {
+	body := http.MaxBytesReader(w, r.Body, maxRequestSize)
+	if mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";"); strings.TrimSpace(mediaType) == domain.MediaTypeCBOR {
+		return cbor.NewDecoder(body).Decode(v)
+	}
+	return json.NewDecoder(body).Decode(v)
+}
+
+// respond writes the response as CBOR when the client prefers it, and as JSON otherwise
+func respond(w http.ResponseWriter, r *http.Request, status int, v any) {
+	if writeCBOR(w, r, status, v) {
+		return
+	}
+	if status != http.StatusOK {
+		render.Status(r, status)
+	}
+	render.JSON(w, r, v)
+}
+
+// writeCBOR writes the response as CBOR if the client prefers it and reports whether it did
+func writeCBOR(w http.ResponseWriter, r *http.Request, status int, v any) bool {
+	if codec := negotiateCodec(w, r); codec == nil || codec.ContentType() != domain.MediaTypeCBOR {
+		return false
+	}
+
+	data, err := cbor.Marshal(v)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to encode response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("Content-Type", domain.MediaTypeCBOR)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+	return true
+}
+
+// serviceError responds with 403 Forbidden when the role of the user in the list does
+// not allow the request, and logs any other error before responding with 500
+func serviceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	var permissionErr domain.ErrPermissionDenied
+	if errors.As(err, &permissionErr) {
+		respond(w, r, http.StatusForbidden, permissionErr)
+		return
+	}
+	log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// decodeErrorStatus picks the status code for a request body that failed to decode
+func decodeErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// connectError responds with an error whose body is also an error of the Connect
+// protocol, as its clients drop the headers of errors they cannot read
+func connectError(w http.ResponseWriter, status int, code, message string) {
+	body, _ := json.Marshal(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{code, message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
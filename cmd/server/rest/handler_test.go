@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/segmentio/encoding/json"
@@ -19,6 +20,128 @@ import (
 	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
 )
 
+func Test_handler_All(t *testing.T) {
+	var todo = &domain.Todo{
+		ID:          uuid.New(),
+		Description: "first",
+		Completed:   true,
+		CreatedAt:   time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := map[string]struct {
+		args           args
+		mock           func(f fields)
+		wantStatusCode int
+		wantHeader     http.Header
+		want           string
+	}{
+		"AllJSON": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/", nil),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			want: `[{"ID":"` + todo.ID.String() + `","Description":"first","Completed":true,"CreatedAt":"2023-06-01T12:00:00Z"}]` + "\n",
+		},
+		"AllMarkdownRefused": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.Header.Set("Accept", "text/markdown;q=0, application/json")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			want: `[{"ID":"` + todo.ID.String() + `","Description":"first","Completed":true,"CreatedAt":"2023-06-01T12:00:00Z"}]` + "\n",
+		},
+		"AllMarkdownPreferred": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.Header.Set("Accept", "application/json;q=0.5, text/markdown")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/markdown; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			want: "- [x] first <!-- id:" + todo.ID.String() + " created:2023-06-01T12:00:00Z -->\n",
+		},
+		"AllMarkdown": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.Header.Set("Accept", "text/markdown, text/plain;q=0.5")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Search(context.Background(), "").Return([]*domain.Todo{todo}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": []string{"text/markdown; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			want: "- [x] first <!-- id:" + todo.ID.String() + " created:2023-06-01T12:00:00Z -->\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := handler{
+				todosSvc: f.todosSvc,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			h.All(tt.args.w, tt.args.r)
+
+			res := tt.args.w.(*httptest.ResponseRecorder)
+			if res.Result().StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.All() StatusCode = %v, want %v", res.Result().StatusCode, tt.wantStatusCode)
+			}
+			if !reflect.DeepEqual(res.Result().Header, tt.wantHeader) {
+				t.Errorf("handler.All() Header = %v, want %v", res.Result().Header, tt.wantHeader)
+			}
+			if res.Body.String() != tt.want {
+				t.Errorf("handler.All() Body = %v, want %v", res.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
 func Test_handler_Create(t *testing.T) {
 	var todo = &domain.Todo{
 		ID:          uuid.New(),
@@ -39,6 +162,7 @@ func Test_handler_Create(t *testing.T) {
 		wantStatusCode int
 		wantHeader     http.Header
 		want           *domain.Todo
+		wantFields     map[string]string
 	}{
 		"Create": {
 			args: args{
@@ -53,9 +177,46 @@ func Test_handler_Create(t *testing.T) {
 				f.todosSvc.EXPECT().Add(context.Background(), "first").Return(todo, nil)
 			},
 			wantStatusCode: http.StatusCreated,
-			wantHeader:     http.Header{},
+			wantHeader:     http.Header{"Vary": []string{"Accept"}},
 			want:           todo,
 		},
+		"CreateInvalid": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"description":"  "}`))
+					req.Header.Set("Content-Type", "application/json")
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Add(context.Background(), "  ").Return(nil, domain.ErrValidation{
+					Fields: map[string]string{domain.FieldDescription: "must not be empty"},
+				})
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantHeader: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			wantFields: map[string]string{domain.FieldDescription: "must not be empty"},
+		},
+		"CreateTooLarge": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					body := `{"description":"` + strings.Repeat("a", maxRequestSize) + `"}`
+					req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+					req.Header.Set("Content-Type", "application/json")
+					return req
+				}(),
+			},
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantHeader: http.Header{
+				"Content-Type":           []string{"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": []string{"nosniff"},
+			},
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -80,8 +241,18 @@ func Test_handler_Create(t *testing.T) {
 			}
 
 			gotJSON := res.Body.Bytes()
+			if tt.wantFields != nil {
+				var gotErr domain.ErrValidation
+				if err := json.Unmarshal(gotJSON, &gotErr); err != nil {
+					t.Errorf("handler.Create() Body = %v, want %v", string(gotJSON), tt.wantFields)
+				}
+				if !reflect.DeepEqual(gotErr.Fields, tt.wantFields) {
+					t.Errorf("handler.Create() Fields = %v, want %v", gotErr.Fields, tt.wantFields)
+				}
+				return
+			}
 			if tt.want == nil {
-				if len(gotJSON) > 0 {
+				if len(gotJSON) > 0 && tt.wantStatusCode < http.StatusBadRequest {
 					t.Errorf("handler.Create() Body = %v, want %v", string(gotJSON), "")
 				}
 				return
@@ -210,6 +381,29 @@ func Test_handler_Get(t *testing.T) {
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
 				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
+			},
+			want: todo,
+		},
+		"GetCBOR": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.Header.Set("Accept", domain.MediaTypeCBOR)
+					rCtx := chi.NewRouteContext()
+					rCtx.URLParams.Add("todoId", todoID.String())
+					req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rCtx))
+					return req
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Get(mock.AnythingOfType("*context.valueCtx"), todoID).Return(todo, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": []string{domain.MediaTypeCBOR},
+				"Vary":         []string{"Accept"},
 			},
 			want: todo,
 		},
@@ -242,8 +436,12 @@ func Test_handler_Get(t *testing.T) {
 				}
 				return
 			}
+			unmarshal := json.Unmarshal
+			if res.Result().Header.Get("Content-Type") == domain.MediaTypeCBOR {
+				unmarshal = cbor.Unmarshal
+			}
 			var gotTodo domain.Todo
-			if err := json.Unmarshal(gotJSON, &gotTodo); err != nil {
+			if err := unmarshal(gotJSON, &gotTodo); err != nil {
 				t.Errorf("handler.Create() Body = %v, want %v", string(gotJSON), tt.want)
 			}
 			if gotTodo.ID != tt.want.ID || gotTodo.Description != tt.want.Description || gotTodo.Completed != tt.want.Completed {
@@ -283,11 +481,12 @@ func Test_handler_Search(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Search(mock.AnythingOfType("*context.emptyCtx"), "test").Return([]*domain.Todo{todo}, nil)
+				f.todosSvc.EXPECT().Search(context.Background(), "test").Return([]*domain.Todo{todo}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
 				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
 			},
 			want: []*domain.Todo{todo},
 		},
@@ -384,13 +583,14 @@ func Test_handler_Sort(t *testing.T) {
 				}(),
 			},
 			mock: func(f fields) {
-				f.todosSvc.EXPECT().Sort(mock.AnythingOfType("*context.emptyCtx"), todoIDs).Return([]*domain.Todo{
+				f.todosSvc.EXPECT().Sort(context.Background(), todoIDs).Return([]*domain.Todo{
 					firstTodo, secondTodo,
 				}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
 				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
 			},
 			want: []*domain.Todo{firstTodo, secondTodo},
 		},
@@ -479,6 +679,7 @@ func Test_handler_Update(t *testing.T) {
 			wantStatusCode: http.StatusOK,
 			wantHeader: http.Header{
 				"Content-Type": []string{"application/json; charset=utf-8"},
+				"Vary":         []string{"Accept"},
 			},
 			want: updated,
 		},
@@ -521,3 +722,241 @@ func Test_handler_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_Export(t *testing.T) {
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := map[string]struct {
+		args           args
+		mock           func(f fields)
+		wantStatusCode int
+		wantHeader     http.Header
+		want           string
+	}{
+		"ExportCSV": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/?format=csv", nil),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Export(context.Background(), "csv").Return([]byte("id,description,completed,created_at\n"), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":        []string{"text/csv"},
+				"Content-Disposition": []string{`attachment; filename="todos.csv"`},
+			},
+			want: "id,description,completed,created_at\n",
+		},
+		"ExportUnsupported": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/?format=xml", nil),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantHeader: http.Header{
+				"Content-Type":           []string{"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": []string{"nosniff"},
+			},
+			want: "unsupported format: \"xml\"\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := handler{
+				todosSvc: f.todosSvc,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			h.Export(tt.args.w, tt.args.r)
+
+			res := tt.args.w.(*httptest.ResponseRecorder)
+			if res.Result().StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.Export() StatusCode = %v, want %v", res.Result().StatusCode, tt.wantStatusCode)
+			}
+			if !reflect.DeepEqual(res.Result().Header, tt.wantHeader) {
+				t.Errorf("handler.Export() Header = %v, want %v", res.Result().Header, tt.wantHeader)
+			}
+			if res.Body.String() != tt.want {
+				t.Errorf("handler.Export() Body = %v, want %v", res.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_handler_Import(t *testing.T) {
+	var report = &domain.ImportReport{
+		Mode:    domain.ImportMerge,
+		DryRun:  true,
+		Added:   []*domain.Todo{{ID: uuid.New(), Description: "first"}},
+		Updated: []*domain.Todo{},
+		Removed: []*domain.Todo{},
+	}
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := map[string]struct {
+		args           args
+		mock           func(f fields)
+		wantStatusCode int
+		want           *domain.ImportReport
+	}{
+		"ImportDryRun": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/?format=csv&dry_run=true", strings.NewReader("description\nfirst\n")),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Import(context.Background(), "csv", mock.Anything, domain.ImportMerge, true).Return(report, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			want:           report,
+		},
+		"ImportInvalidMode": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/?mode=append", strings.NewReader("[]")),
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		"ImportInvalid": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/?mode=replace", strings.NewReader(`[{"Description":""}]`)),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Import(context.Background(), "json", mock.Anything, domain.ImportReplace, false).Return(nil, domain.ErrValidation{
+					Fields: map[string]string{"todos[0].description": "must not be empty"},
+				})
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := handler{
+				todosSvc: f.todosSvc,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			h.Import(tt.args.w, tt.args.r)
+
+			res := tt.args.w.(*httptest.ResponseRecorder)
+			if res.Result().StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.Import() StatusCode = %v, want %v", res.Result().StatusCode, tt.wantStatusCode)
+			}
+			if tt.want == nil {
+				return
+			}
+			var got domain.ImportReport
+			if err := json.Unmarshal(res.Body.Bytes(), &got); err != nil {
+				t.Fatalf("handler.Import() Body = %v, want %v", res.Body.String(), tt.want)
+			}
+			if got.Mode != tt.want.Mode || got.DryRun != tt.want.DryRun || len(got.Added) != len(tt.want.Added) {
+				t.Errorf("handler.Import() Body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_handler_Calendar(t *testing.T) {
+	var feed = []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n")
+	const etag = `"67a73b3cd2232e1743d87c55ebfbed28"`
+	type fields struct {
+		todosSvc *todos.MockService
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := map[string]struct {
+		args           args
+		mock           func(f fields)
+		wantStatusCode int
+		wantHeader     http.Header
+		want           string
+	}{
+		"Calendar": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/todos.ics", nil),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Export(context.Background(), domain.FormatICalendar).Return(feed, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Accept-Ranges":  []string{"bytes"},
+				"Cache-Control":  []string{"no-cache"},
+				"Content-Length": []string{"45"},
+				"Content-Type":   []string{"text/calendar; charset=utf-8"},
+				"Etag":           []string{etag},
+			},
+			want: string(feed),
+		},
+		"NotModified": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest(http.MethodGet, "/todos.ics", nil)
+					r.Header.Set("If-None-Match", etag)
+					return r
+				}(),
+			},
+			mock: func(f fields) {
+				f.todosSvc.EXPECT().Export(context.Background(), domain.FormatICalendar).Return(feed, nil)
+			},
+			wantStatusCode: http.StatusNotModified,
+			wantHeader: http.Header{
+				"Cache-Control": []string{"no-cache"},
+				"Etag":          []string{etag},
+			},
+			want: "",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todosSvc: todos.NewMockService(t),
+			}
+			h := handler{
+				todosSvc: f.todosSvc,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+
+			h.Calendar(tt.args.w, tt.args.r)
+
+			res := tt.args.w.(*httptest.ResponseRecorder)
+			if res.Result().StatusCode != tt.wantStatusCode {
+				t.Errorf("handler.Calendar() StatusCode = %v, want %v", res.Result().StatusCode, tt.wantStatusCode)
+			}
+			if !reflect.DeepEqual(res.Result().Header, tt.wantHeader) {
+				t.Errorf("handler.Calendar() Header = %v, want %v", res.Result().Header, tt.wantHeader)
+			}
+			if res.Body.String() != tt.want {
+				t.Errorf("handler.Calendar() Body = %v, want %v", res.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+const (
+	// idempotentReplayHeader marks responses that were replayed rather than produced again
+	idempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength is the longest key that will be accepted
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyTTL is how long a key and its response are remembered
+	idempotencyKeyTTL = 24 * time.Hour
+	// maxIdempotencyEntries and maxIdempotencyBytes bound the responses that are
+	// remembered, so clients cannot grow the store without end by sending new keys;
+	// the oldest responses are forgotten first
+	maxIdempotencyEntries = 10000
+	maxIdempotencyBytes   = 32 << 20
+)
+
+type (
+	// idempotencyStore remembers recent idempotency keys and the responses they produced
+	idempotencyStore struct {
+		mu         sync.Mutex
+		ttl        time.Duration
+		maxEntries int
+		maxBytes   int
+		entries    map[string]*idempotencyEntry
+		// stored holds the keys of the stored responses, oldest first
+		stored    *list.List
+		size      int
+		lastPrune time.Time
+	}
+
+	idempotencyEntry struct {
+		fingerprint [sha256.Size]byte
+		// done is closed once the first request with the key has finished
+		done     chan struct{}
+		response *recordedResponse
+		expires  time.Time
+		// element is the key in idempotencyStore.stored once the response is stored
+		element *list.Element
+	}
+
+	recordedResponse struct {
+		status int
+		header http.Header
+		body   []byte
+	}
+
+	// responseRecorder passes the response through while keeping a copy of it
+	responseRecorder struct {
+		http.ResponseWriter
+		response recordedResponse
+	}
+)
+
+func newIdempotencyStore(ttl time.Duration, maxEntries, maxBytes int) *idempotencyStore {
+	return &idempotencyStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*idempotencyEntry),
+		stored:     list.New(),
+	}
+}
+
+// idempotency replays the stored response when a request repeats the
+// Idempotency-Key of an earlier one
+//
+// Duplicates that arrive while the first request is still being handled wait
+// for it to finish. Reusing a key for a different request is a 409 Conflict, and
+// server errors are not stored so that the request can be retried.
+func idempotency(store *idempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(domain.IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+			if err != nil {
+				http.Error(w, err.Error(), decodeErrorStatus(err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := requestFingerprint(r, body)
+			// keys are scoped to the user so nobody can replay another user's response
+			if user := domain.UserFromContext(r.Context()); user != nil {
+				key = user.ID.String() + ":" + key
+			}
+			// and to the list, as the same request means something else in another list
+			if listID, ok := domain.ListFromContext(r.Context()); ok {
+				key = listID.String() + ":" + key
+			}
+
+			for {
+				entry, first := store.begin(key, fingerprint)
+				if first {
+					rec := &responseRecorder{ResponseWriter: w}
+					defer func() {
+						store.finish(key, entry, &rec.response)
+					}()
+					next.ServeHTTP(rec, r)
+					return
+				}
+
+				if entry.fingerprint != fingerprint {
+					http.Error(w, domain.ErrIdempotencyKeyReused{}.Error(), http.StatusConflict)
+					return
+				}
+				select {
+				case <-entry.done:
+				case <-r.Context().Done():
+					return
+				}
+				// the first request failed and was forgotten; try again
+				if entry.response == nil {
+					continue
+				}
+				entry.response.replay(w)
+				return
+			}
+		})
+	}
+}
+
+// begin returns the entry for the key and whether this request is the first to use it
+func (s *idempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotencyEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastPrune) > time.Minute {
+		for k, entry := range s.entries {
+			if entry.response != nil && now.After(entry.expires) {
+				s.forget(k, entry)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	if entry, exists := s.entries[key]; exists {
+		if entry.response == nil || now.Before(entry.expires) {
+			return entry, false
+		}
+		s.forget(key, entry)
+	}
+	entry := &idempotencyEntry{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+	s.entries[key] = entry
+	return entry, true
+}
+
+// finish stores the response for the key, or forgets the key when the request failed
+func (s *idempotencyStore) finish(key string, entry *idempotencyEntry, response *recordedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if response.status == 0 || response.status >= http.StatusInternalServerError {
+		delete(s.entries, key)
+	} else {
+		entry.response = response
+		entry.expires = time.Now().Add(s.ttl)
+		entry.element = s.stored.PushBack(key)
+		s.size += len(response.body)
+		for s.stored.Len() > s.maxEntries || s.size > s.maxBytes {
+			oldest := s.stored.Front().Value.(string)
+			s.forget(oldest, s.entries[oldest])
+		}
+	}
+	close(entry.done)
+}
+
+// forget removes the key and its stored response
+func (s *idempotencyStore) forget(key string, entry *idempotencyEntry) {
+	delete(s.entries, key)
+	if entry.element != nil {
+		s.stored.Remove(entry.element)
+		s.size -= len(entry.response.body)
+		entry.element = nil
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	_, _ = h.Write(body)
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.response.status == 0 {
+		rec.response.status = status
+		rec.response.header = rec.Header().Clone()
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	if rec.response.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.response.body = append(rec.response.body, data...)
+	return rec.ResponseWriter.Write(data)
+}
+
+func (r *recordedResponse) replay(w http.ResponseWriter) {
+	for name, values := range r.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(idempotentReplayHeader, "true")
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body)
+}
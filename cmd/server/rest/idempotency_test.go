@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+)
+
+func Test_idempotency(t *testing.T) {
+	type request struct {
+		key  string
+		body string
+	}
+	tests := map[string]struct {
+		status       int
+		requests     []request
+		wantCalls    int32
+		wantStatuses []int
+		wantReplayed []bool
+	}{
+		"NoKey": {
+			status:       http.StatusCreated,
+			requests:     []request{{body: "a"}, {body: "a"}},
+			wantCalls:    2,
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+		},
+		"Replayed": {
+			status:       http.StatusCreated,
+			requests:     []request{{key: "1", body: "a"}, {key: "1", body: "a"}},
+			wantCalls:    1,
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, true},
+		},
+		"DifferentKeys": {
+			status:       http.StatusCreated,
+			requests:     []request{{key: "1", body: "a"}, {key: "2", body: "a"}},
+			wantCalls:    2,
+			wantStatuses: []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+		},
+		"KeyReusedForDifferentRequest": {
+			status:       http.StatusCreated,
+			requests:     []request{{key: "1", body: "a"}, {key: "1", body: "b"}},
+			wantCalls:    1,
+			wantStatuses: []int{http.StatusCreated, http.StatusConflict},
+			wantReplayed: []bool{false, false},
+		},
+		"ServerErrorsAreNotStored": {
+			status:       http.StatusInternalServerError,
+			requests:     []request{{key: "1", body: "a"}, {key: "1", body: "a"}},
+			wantCalls:    2,
+			wantStatuses: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantReplayed: []bool{false, false},
+		},
+		"KeyTooLong": {
+			status:       http.StatusCreated,
+			requests:     []request{{key: strings.Repeat("k", maxIdempotencyKeyLength+1), body: "a"}},
+			wantCalls:    0,
+			wantStatuses: []int{http.StatusBadRequest},
+			wantReplayed: []bool{false},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			h := idempotency(newIdempotencyStore(idempotencyKeyTTL, maxIdempotencyEntries, maxIdempotencyBytes))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("done"))
+			}))
+
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(req.body))
+				if req.key != "" {
+					r.Header.Set(domain.IdempotencyKeyHeader, req.key)
+				}
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+
+				if w.Code != tt.wantStatuses[i] {
+					t.Errorf("request %d StatusCode = %v, want %v", i, w.Code, tt.wantStatuses[i])
+				}
+				if replayed := w.Header().Get(idempotentReplayHeader) == "true"; replayed != tt.wantReplayed[i] {
+					t.Errorf("request %d replayed = %v, want %v", i, replayed, tt.wantReplayed[i])
+				}
+				if tt.wantReplayed[i] && (w.Body.String() != "done" || w.Header().Get("Content-Type") != "text/plain") {
+					t.Errorf("request %d replayed %v %q, want the original response", i, w.Header(), w.Body.String())
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_idempotency_ConcurrentDuplicates(t *testing.T) {
+	const submissions = 20
+
+	list := domain.NewTodos()
+	router := chi.NewRouter()
+	Mount(router, NewHandler(todos.NewService(list)))
+
+	var wg sync.WaitGroup
+	ids := make([]string, submissions)
+	statuses := make([]int, submissions)
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"description":"Bake a cake"}`))
+			r.Header.Set(domain.IdempotencyKeyHeader, "same-key")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			statuses[i] = w.Code
+			var todo domain.Todo
+			_ = json.Unmarshal(w.Body.Bytes(), &todo)
+			ids[i] = todo.ID.String()
+		}(i)
+	}
+	wg.Wait()
+
+	created, _ := list.Scan(context.Background())
+	if len(created) != 1 {
+		t.Fatalf("concurrent duplicates created %d todos, want 1", len(created))
+	}
+	for i := range ids {
+		if statuses[i] != http.StatusCreated || ids[i] != created[0].ID.String() {
+			t.Errorf("submission %d = %d %v, want %d %v", i, statuses[i], ids[i], http.StatusCreated, created[0].ID)
+		}
+	}
+}
+
+func Test_idempotencyStore_Limits(t *testing.T) {
+	tests := map[string]struct {
+		maxEntries int
+		maxBytes   int
+		bodies     []string
+		wantKept   []bool
+	}{
+		"WithinLimits": {
+			maxEntries: 3, maxBytes: 100,
+			bodies:   []string{"one", "two", "three"},
+			wantKept: []bool{true, true, true},
+		},
+		"TooManyEntries": {
+			maxEntries: 2, maxBytes: 100,
+			bodies:   []string{"one", "two", "three"},
+			wantKept: []bool{false, true, true},
+		},
+		"TooManyBytes": {
+			maxEntries: 3, maxBytes: 7,
+			bodies:   []string{"one", "two", "three"},
+			wantKept: []bool{false, false, true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := newIdempotencyStore(time.Hour, tt.maxEntries, tt.maxBytes)
+			for i, body := range tt.bodies {
+				key := strconv.Itoa(i)
+				entry, _ := store.begin(key, [32]byte{})
+				store.finish(key, entry, &recordedResponse{status: http.StatusCreated, body: []byte(body)})
+			}
+			for i, want := range tt.wantKept {
+				if _, kept := store.entries[strconv.Itoa(i)]; kept != want {
+					t.Errorf("response %d kept = %v, want %v", i, kept, want)
+				}
+			}
+			if store.stored.Len() > tt.maxEntries || store.size > tt.maxBytes {
+				t.Errorf("store holds %d responses of %d bytes, want at most %d of %d", store.stored.Len(), store.size, tt.maxEntries, tt.maxBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/lists"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/features/users"
+)
+
+func TestMain(m *testing.M) {
+	domain.PasswordCost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+// newAccountsServer serves the REST API to signed in users only, as the server does
+func newAccountsServer(t *testing.T) *httptest.Server {
+	accounts := domain.NewUsers()
+	members := domain.NewMembers()
+	usersSvc := users.NewService(accounts, domain.NewSessions(), domain.NewTokens())
+	router := chi.NewRouter()
+	MountAuth(router, NewAuthHandler(usersSvc))
+	router.Group(func(r chi.Router) {
+		r.Use(CheckAPIVersion, AcceptTokens(usersSvc), VerifyCSRF, RequireUser(usersSvc))
+		Mount(r, NewHandler(todos.NewService(domain.NewTodos(), todos.WithMembers(members))))
+		MountLists(r, NewListsHandler(lists.NewService(members, accounts)))
+	})
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// register signs a new user up and returns a client that carries their session
+func register(t *testing.T, server *httptest.Server, username string) *http.Client {
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	resp, err := client.Post(server.URL+"/auth/register", "application/json",
+		strings.NewReader(`{"username":"`+username+`","password":"correct horse"}`))
+	if err != nil {
+		t.Fatalf("register %s: %v", username, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register %s: status = %d, want %d", username, resp.StatusCode, http.StatusCreated)
+	}
+	return client
+}
+
+// send makes the request as a page of the site would, with the CSRF token of the
+// session the client holds, unless the header sets the token itself
+func send(t *testing.T, client *http.Client, method, url, body string, header http.Header) (*http.Response, string) {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if client.Jar != nil {
+		for _, cookie := range client.Jar.Cookies(req.URL) {
+			if cookie.Name == SessionCookie {
+				req.Header.Set(domain.CSRFHeader, domain.CSRFToken(cookie.Value))
+			}
+		}
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+func TestHandler_OwnerIsolation(t *testing.T) {
+	const secret = "alice's secret"
+	idempotent := http.Header{domain.IdempotencyKeyHeader: []string{"alice-key"}}
+
+	// each request is made by bob against alice's todo, whose id replaces {id}
+	tests := map[string]struct {
+		method string
+		path   string
+		body   string
+		header http.Header
+	}{
+		"List":          {method: http.MethodGet, path: "/todos"},
+		"Search":        {method: http.MethodGet, path: "/todos?search=secret"},
+		"Get":           {method: http.MethodGet, path: "/todos/{id}"},
+		"Patch":         {method: http.MethodPatch, path: "/todos/{id}", body: `{"completed":true,"description":"bob was here"}`},
+		"Edit":          {method: http.MethodPost, path: "/todos/{id}/edit", body: `{"completed":true,"description":"bob was here"}`},
+		"Delete":        {method: http.MethodDelete, path: "/todos/{id}"},
+		"PostDelete":    {method: http.MethodPost, path: "/todos/{id}/delete"},
+		"Sort":          {method: http.MethodPost, path: "/todos/sort", body: `{"ids":["{id}"]}`},
+		"ExportJSON":    {method: http.MethodGet, path: "/todos/export"},
+		"ExportCSV":     {method: http.MethodGet, path: "/todos/export?format=csv"},
+		"Calendar":      {method: http.MethodGet, path: "/todos.ics"},
+		"ImportMerge":   {method: http.MethodPost, path: "/todos/import?mode=merge", body: `[{"ID":"{id}","Description":"bob was here","Completed":true}]`},
+		"ImportReplace": {method: http.MethodPost, path: "/todos/import?mode=replace", body: `[{"ID":"{id}","Description":"bob was here","Completed":true}]`},
+		"ImportDryRun":  {method: http.MethodPost, path: "/todos/import?mode=replace&dry_run=true", body: `[{"ID":"{id}","Description":"bob was here"}]`},
+		"IdempotentReplay": {
+			method: http.MethodPost,
+			path:   "/todos",
+			body:   `{"description":"` + secret + `"}`,
+			header: idempotent,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newAccountsServer(t)
+			alice := register(t, server, "alice")
+			bob := register(t, server, "bob")
+			_, _ = send(t, bob, http.MethodPost, server.URL+"/todos", `{"description":"bob's todo"}`, nil)
+
+			resp, body := send(t, alice, http.MethodPost, server.URL+"/todos", `{"description":"`+secret+`"}`, idempotent)
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("alice's POST /todos status = %d, want %d", resp.StatusCode, http.StatusCreated)
+			}
+			var todo domain.Todo
+			if err := json.Unmarshal([]byte(body), &todo); err != nil {
+				t.Fatalf("alice's POST /todos body: %v", err)
+			}
+			path := strings.ReplaceAll(tt.path, "{id}", todo.ID.String())
+			requestBody := strings.ReplaceAll(tt.body, "{id}", todo.ID.String())
+
+			resp, body = send(t, http.DefaultClient, tt.method, server.URL+path, requestBody, tt.header)
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("anonymous %s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, http.StatusUnauthorized)
+			}
+
+			resp, body = send(t, bob, tt.method, server.URL+path, requestBody, tt.header)
+			if strings.Contains(body, todo.ID.String()) {
+				t.Errorf("bob's %s %s = %q, which shows alice's todo", tt.method, tt.path, body)
+			}
+			if resp.Header.Get(idempotentReplayHeader) != "" {
+				t.Errorf("bob's %s %s replayed alice's response", tt.method, tt.path)
+			}
+
+			_, body = send(t, alice, http.MethodGet, server.URL+"/todos", "", nil)
+			var list []*domain.Todo
+			if err := json.Unmarshal([]byte(body), &list); err != nil {
+				t.Fatalf("alice's GET /todos body: %v", err)
+			}
+			if len(list) != 1 || list[0].ID != todo.ID || list[0].Description != secret || list[0].Completed {
+				t.Errorf("alice's list = %s, want only her todo, unchanged", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/lists"
+)
+
+type (
+	ListsHandler interface {
+		// Lists : GET /lists
+		Lists(w http.ResponseWriter, r *http.Request)
+		// Members : GET /lists/members
+		Members(w http.ResponseWriter, r *http.Request)
+		// Invite : POST /lists/members
+		Invite(w http.ResponseWriter, r *http.Request)
+		// RemoveMember : DELETE /lists/members/{userId}
+		RemoveMember(w http.ResponseWriter, r *http.Request)
+	}
+
+	listsHandler struct {
+		listsSvc lists.Service
+	}
+)
+
+func NewListsHandler(listsSvc lists.Service) ListsHandler {
+	return &listsHandler{listsSvc: listsSvc}
+}
+
+func MountLists(r chi.Router, h ListsHandler) {
+	r.Route("/lists", func(r chi.Router) {
+		r.Use(SelectList)
+		r.With(requireScope(domain.ScopeRead)).Get("/", h.Lists)
+		r.Route("/members", func(r chi.Router) {
+			r.With(requireScope(domain.ScopeRead)).Get("/", h.Members)
+			r.With(requireScope(domain.ScopeAdmin)).Post("/", h.Invite)
+			r.With(requireScope(domain.ScopeAdmin)).Delete("/{userId}", h.RemoveMember)
+		})
+	})
+}
+
+// SelectList adds the list named by the X-Todo-List header, or the list query
+// parameter, to the context; requests without either act on the user's own list
+func SelectList(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected := r.Header.Get(domain.ListHeader)
+		if selected == "" {
+			selected = r.URL.Query().Get("list")
+		}
+		if selected == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		listID, err := uuid.Parse(selected)
+		if err != nil {
+			http.Error(w, "invalid list: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithList(r.Context(), listID)))
+	})
+}
+
+func (h listsHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	memberships, err := h.listsSvc.Lists(r.Context())
+	if err != nil {
+		serviceError(w, r, err, "failed to retrieve lists")
+		return
+	}
+
+	respond(w, r, http.StatusOK, memberships)
+}
+
+func (h listsHandler) Members(w http.ResponseWriter, r *http.Request) {
+	memberships, err := h.listsSvc.Members(r.Context())
+	if err != nil {
+		serviceError(w, r, err, "failed to retrieve list members")
+		return
+	}
+
+	respond(w, r, http.StatusOK, memberships)
+}
+
+func (h listsHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	type requestType struct {
+		Username string      `json:"username"`
+		Role     domain.Role `json:"role"`
+	}
+	var request requestType
+	if err := decodeRequest(w, r, &request); err != nil {
+		http.Error(w, err.Error(), decodeErrorStatus(err))
+		return
+	}
+
+	membership, err := h.listsSvc.Invite(r.Context(), request.Username, request.Role)
+	if err != nil {
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to share list")
+		return
+	}
+
+	respond(w, r, http.StatusCreated, membership)
+}
+
+func (h listsHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.listsSvc.RemoveMember(r.Context(), userID); err != nil {
+		var validationErr domain.ErrValidation
+		if errors.As(err, &validationErr) {
+			respond(w, r, http.StatusUnprocessableEntity, validationErr)
+			return
+		}
+		serviceError(w, r, err, "failed to remove list member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
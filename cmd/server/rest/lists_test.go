@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// me returns the id of the signed in user, which is also the id of their list
+func me(t *testing.T, server *httptest.Server, client *http.Client) string {
+	_, body := send(t, client, http.MethodGet, server.URL+"/auth/me", "", nil)
+	var user domain.User
+	if err := json.Unmarshal([]byte(body), &user); err != nil {
+		t.Fatalf("GET /auth/me body: %v", err)
+	}
+	return user.ID.String()
+}
+
+func inList(listID string) http.Header {
+	return http.Header{domain.ListHeader: []string{listID}}
+}
+
+func TestListsHandler_Sharing(t *testing.T) {
+	server := newAccountsServer(t)
+	alice := register(t, server, "alice")
+	bob := register(t, server, "bob")
+	carol := register(t, server, "carol")
+	dave := register(t, server, "dave")
+	list := me(t, server, alice)
+	bobID := me(t, server, bob)
+
+	for username, role := range map[string]domain.Role{"bob": domain.RoleViewer, "carol": domain.RoleEditor} {
+		resp, body := send(t, alice, http.MethodPost, server.URL+"/lists/members", `{"username":"`+username+`","role":"`+string(role)+`"}`, nil)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("invite %s status = %d, want %d: %s", username, resp.StatusCode, http.StatusCreated, body)
+		}
+	}
+	_, _ = send(t, alice, http.MethodPost, server.URL+"/todos", `{"description":"Bake a cake"}`, nil)
+
+	// each request is made against alice's list
+	tests := map[string]struct {
+		client *http.Client
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		"Viewer reads":         {client: bob, method: http.MethodGet, path: "/todos", want: http.StatusOK},
+		"Viewer adds":          {client: bob, method: http.MethodPost, path: "/todos", body: `{"description":"bob was here"}`, want: http.StatusForbidden},
+		"Viewer invites":       {client: bob, method: http.MethodPost, path: "/lists/members", body: `{"username":"dave","role":"viewer"}`, want: http.StatusForbidden},
+		"Viewer lists members": {client: bob, method: http.MethodGet, path: "/lists/members", want: http.StatusOK},
+		"Editor adds":          {client: carol, method: http.MethodPost, path: "/todos", body: `{"description":"Feed the cat"}`, want: http.StatusCreated},
+		"Editor imports":       {client: carol, method: http.MethodPost, path: "/todos/import?mode=merge", body: `[]`, want: http.StatusOK},
+		"Editor removes":       {client: carol, method: http.MethodDelete, path: "/lists/members/" + bobID, want: http.StatusForbidden},
+		"Stranger reads":       {client: dave, method: http.MethodGet, path: "/todos", want: http.StatusForbidden},
+		"Stranger calendar":    {client: dave, method: http.MethodGet, path: "/todos.ics?list=" + list, want: http.StatusForbidden},
+		"Invite unknown user":  {client: alice, method: http.MethodPost, path: "/lists/members", body: `{"username":"erin","role":"viewer"}`, want: http.StatusUnprocessableEntity},
+		"Invite bad role":      {client: alice, method: http.MethodPost, path: "/lists/members", body: `{"username":"dave","role":"admin"}`, want: http.StatusUnprocessableEntity},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, body := send(t, tt.client, tt.method, server.URL+tt.path, tt.body, inList(list))
+			if resp.StatusCode != tt.want {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.want, body)
+			}
+		})
+	}
+
+	t.Run("Invalid list", func(t *testing.T) {
+		resp, _ := send(t, bob, http.MethodGet, server.URL+"/todos", "", inList("alice"))
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET /todos status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		_, body := send(t, bob, http.MethodGet, server.URL+"/lists", "", nil)
+		var memberships []*domain.Membership
+		if err := json.Unmarshal([]byte(body), &memberships); err != nil {
+			t.Fatalf("GET /lists body: %v", err)
+		}
+		if len(memberships) != 2 || memberships[1].ListName != "alice" || memberships[1].Role != domain.RoleViewer {
+			t.Errorf("GET /lists = %s, want bob's own list and alice's as viewer", body)
+		}
+	})
+
+	t.Run("Leave", func(t *testing.T) {
+		resp, _ := send(t, bob, http.MethodDelete, server.URL+"/lists/members/"+bobID, "", inList(list))
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("DELETE /lists/members status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+		resp, _ = send(t, bob, http.MethodGet, server.URL+"/todos", "", inList(list))
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("GET /todos after leaving status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+	})
+}
+
+func TestTodoApi_Sharing(t *testing.T) {
+	server := newAccountsServer(t)
+	ctx := context.Background()
+	newApi := func(username string) *domain.TodoApi {
+		jar, _ := cookiejar.New(nil)
+		api := domain.NewTodoApi(server.URL, domain.WithCookieJar(jar))
+		if _, err := api.Register(ctx, username, "correct horse"); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		return api
+	}
+	alice, bob := newApi("alice"), newApi("bob")
+
+	if _, err := alice.Invite(ctx, "bob", domain.RoleViewer); err != nil {
+		t.Fatalf("Invite() error = %v", err)
+	}
+	if _, err := alice.Invite(ctx, "carol", domain.RoleViewer); !errors.As(err, &domain.ErrValidation{}) {
+		t.Errorf("Invite() of an unknown user error = %v, want %T", err, domain.ErrValidation{})
+	}
+	if _, err := alice.Add(ctx, "Bake a cake"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	memberships, err := bob.Lists(ctx)
+	if err != nil || len(memberships) != 2 {
+		t.Fatalf("Lists() = %v, %v, want bob's own list and alice's", memberships, err)
+	}
+	shared := domain.ContextWithList(ctx, memberships[1].ListID)
+	todos, err := bob.All(shared)
+	if err != nil || len(todos) != 1 {
+		t.Errorf("All() = %v, %v, want alice's todo", todos, err)
+	}
+	want := domain.ErrPermissionDenied{Permission: domain.PermissionEdit}
+	if _, err := bob.Add(shared, "bob was here"); !errors.Is(err, want) {
+		t.Errorf("Add() error = %v, want %v", err, want)
+	}
+	members, err := bob.Members(shared)
+	if err != nil || len(members) != 2 {
+		t.Errorf("Members() = %v, %v, want alice and bob", members, err)
+	}
+	if err := bob.RemoveMember(shared, memberships[0].UserID); err != nil {
+		t.Errorf("RemoveMember() error = %v", err)
+	}
+	if _, err := bob.All(shared); !errors.Is(err, domain.ErrPermissionDenied{Permission: domain.PermissionView}) {
+		t.Errorf("All() after leaving error = %v, want %v", err, domain.ErrPermissionDenied{Permission: domain.PermissionView})
+	}
+}
@@ -0,0 +1,184 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// createToken creates an API token with the scopes for the signed in user of client
+func createToken(t *testing.T, serverURL string, client *http.Client, scopes ...domain.Scope) (string, string) {
+	request, _ := json.Marshal(createTokenRequest{Name: "script", Scopes: scopes})
+	resp, body := send(t, client, http.MethodPost, serverURL+"/auth/tokens", string(request), nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /auth/tokens status = %d, want %d: %s", resp.StatusCode, http.StatusCreated, body)
+	}
+	var created struct {
+		ID    string
+		Token string
+	}
+	if err := json.Unmarshal([]byte(body), &created); err != nil {
+		t.Fatalf("POST /auth/tokens body: %v", err)
+	}
+	return created.ID, created.Token
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + token}}
+}
+
+func TestMount_TokenScopes(t *testing.T) {
+	// each request is made against a todo of the token's owner, whose id replaces {id}
+	routes := map[string]struct {
+		method string
+		path   string
+		body   string
+		scope  domain.Scope
+	}{
+		"List":     {method: http.MethodGet, path: "/todos", scope: domain.ScopeRead},
+		"Get":      {method: http.MethodGet, path: "/todos/{id}", scope: domain.ScopeRead},
+		"Export":   {method: http.MethodGet, path: "/todos/export", scope: domain.ScopeRead},
+		"Calendar": {method: http.MethodGet, path: "/todos.ics", scope: domain.ScopeRead},
+		"Create":   {method: http.MethodPost, path: "/todos", body: `{"description":"scripted"}`, scope: domain.ScopeWrite},
+		"Patch":    {method: http.MethodPatch, path: "/todos/{id}", body: `{"completed":true,"description":"scripted"}`, scope: domain.ScopeWrite},
+		"Edit":     {method: http.MethodPost, path: "/todos/{id}/edit", body: `{"completed":true,"description":"scripted"}`, scope: domain.ScopeWrite},
+		"Delete":   {method: http.MethodDelete, path: "/todos/{id}", scope: domain.ScopeWrite},
+		"Sort":     {method: http.MethodPost, path: "/todos/sort", body: `{"ids":["{id}"]}`, scope: domain.ScopeWrite},
+		"Import":   {method: http.MethodPost, path: "/todos/import?mode=merge", body: `[{"Description":"scripted"}]`, scope: domain.ScopeAdmin},
+	}
+	granted := map[domain.Scope][]domain.Scope{
+		domain.ScopeRead:  {domain.ScopeRead},
+		domain.ScopeWrite: {domain.ScopeRead, domain.ScopeWrite},
+		domain.ScopeAdmin: {domain.ScopeRead, domain.ScopeWrite, domain.ScopeAdmin},
+	}
+	for tokenScope, allowed := range granted {
+		tokenScope, allowed := tokenScope, allowed
+		for name, tt := range routes {
+			tt := tt
+			t.Run(string(tokenScope)+"/"+name, func(t *testing.T) {
+				server := newAccountsServer(t)
+				alice := register(t, server, "alice")
+				_, body := send(t, alice, http.MethodPost, server.URL+"/todos", `{"description":"first"}`, nil)
+				var todo domain.Todo
+				_ = json.Unmarshal([]byte(body), &todo)
+				_, secret := createToken(t, server.URL, alice, tokenScope)
+
+				wantAllowed := false
+				for _, scope := range allowed {
+					wantAllowed = wantAllowed || scope == tt.scope
+				}
+				path := strings.ReplaceAll(tt.path, "{id}", todo.ID.String())
+				requestBody := strings.ReplaceAll(tt.body, "{id}", todo.ID.String())
+				resp, body := send(t, http.DefaultClient, tt.method, server.URL+path, requestBody, bearer(secret))
+				switch {
+				case wantAllowed && resp.StatusCode >= http.StatusBadRequest:
+					t.Errorf("%s %s status = %d, want success: %s", tt.method, tt.path, resp.StatusCode, body)
+				case !wantAllowed && resp.StatusCode != http.StatusForbidden:
+					t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, http.StatusForbidden)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthHandler_Tokens(t *testing.T) {
+	server := newAccountsServer(t)
+	alice := register(t, server, "alice")
+	bob := register(t, server, "bob")
+	id, secret := createToken(t, server.URL, alice, domain.ScopeWrite)
+
+	tests := map[string]struct {
+		client     *http.Client
+		method     string
+		path       string
+		body       string
+		header     http.Header
+		wantStatus int
+	}{
+		"UseToken": {
+			client: http.DefaultClient, method: http.MethodGet, path: "/todos", header: bearer(secret),
+			wantStatus: http.StatusOK,
+		},
+		"UnknownToken": {
+			client: http.DefaultClient, method: http.MethodGet, path: "/todos", header: bearer("todo_unknown"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		"TokenCannotListTokens": {
+			client: http.DefaultClient, method: http.MethodGet, path: "/auth/tokens", header: bearer(secret),
+			wantStatus: http.StatusUnauthorized,
+		},
+		"TokenCannotCreateTokens": {
+			client: http.DefaultClient, method: http.MethodPost, path: "/auth/tokens", header: bearer(secret),
+			body:       `{"name":"more","scopes":["todos:admin"]}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"InvalidToken": {
+			client: alice, method: http.MethodPost, path: "/auth/tokens",
+			body:       `{"name":"","scopes":["todos:everything"]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		"OtherUserCannotRevoke": {
+			client: bob, method: http.MethodDelete, path: "/auth/tokens/" + id,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, body := send(t, tt.client, tt.method, server.URL+tt.path, tt.body, tt.header)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.wantStatus, body)
+			}
+		})
+	}
+
+	_, body := send(t, alice, http.MethodGet, server.URL+"/auth/tokens", "", nil)
+	if !strings.Contains(body, id) || strings.Contains(body, secret) || strings.Contains(body, domain.HashToken(secret)) {
+		t.Errorf("GET /auth/tokens = %s, want the token without its secret or hash", body)
+	}
+	if _, body = send(t, bob, http.MethodGet, server.URL+"/auth/tokens", "", nil); strings.Contains(body, id) {
+		t.Errorf("bob's GET /auth/tokens = %s, which shows alice's token", body)
+	}
+
+	if resp, _ := send(t, alice, http.MethodDelete, server.URL+"/auth/tokens/"+id, "", nil); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE /auth/tokens/{id} status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if resp, _ := send(t, http.DefaultClient, http.MethodGet, server.URL+"/todos", "", bearer(secret)); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /todos with a revoked token status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestAcceptTokens_Feed(t *testing.T) {
+	tests := map[string]struct {
+		scope      domain.Scope
+		path       string
+		wantStatus int
+	}{
+		"Calendar":     {scope: domain.ScopeRead, path: "/todos.ics", wantStatus: http.StatusOK},
+		"WriteToken":   {scope: domain.ScopeWrite, path: "/todos.ics", wantStatus: http.StatusForbidden},
+		"NotAFeed":     {scope: domain.ScopeRead, path: "/todos", wantStatus: http.StatusUnauthorized},
+		"UnknownToken": {path: "/todos.ics", wantStatus: http.StatusUnauthorized},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newAccountsServer(t)
+			alice := register(t, server, "alice")
+			_, _ = send(t, alice, http.MethodPost, server.URL+"/todos", `{"description":"first"}`, nil)
+			secret := "todo_unknown"
+			if tt.scope != "" {
+				_, secret = createToken(t, server.URL, alice, tt.scope)
+			}
+
+			resp, body := send(t, http.DefaultClient, http.MethodGet, server.URL+tt.path+"?access_token="+secret, "", nil)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", tt.path, resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(body, "SUMMARY:first") {
+				t.Errorf("GET %s = %q, want the todos of alice", tt.path, body)
+			}
+		})
+	}
+}
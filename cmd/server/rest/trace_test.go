@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/features/todos"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
+)
+
+func TestTodoApi_TracePropagation(t *testing.T) {
+	exporter := trace.NewMemoryExporter()
+	tracer := trace.NewTracer(exporter)
+
+	router := chi.NewRouter()
+	router.Use(trace.Middleware(tracer))
+	Mount(router, NewHandler(todos.NewService(domain.NewTodos())))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// the root span stands in for the htmx handler on the proxy
+	ctx, root := tracer.Start(context.Background(), "proxy POST /todos", trace.WithKind(trace.KindServer))
+	if _, err := domain.NewTodoApi(server.URL).Add(ctx, "Bake a cake"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	root.End()
+
+	spans := make(map[string]trace.SpanData)
+	for _, span := range exporter.Spans() {
+		spans[span.Name] = span
+	}
+	chain := []string{"proxy POST /todos", "TodoApi POST /todos", "POST /todos", "Todos.Add"}
+	for i, name := range chain {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("span %q was not exported; got %v", name, exporter.Spans())
+		}
+		if span.TraceID != root.SpanContext().TraceID {
+			t.Errorf("span %q TraceID = %v, want %v", name, span.TraceID, root.SpanContext().TraceID)
+		}
+		if i > 0 && span.ParentID != spans[chain[i-1]].SpanID {
+			t.Errorf("span %q ParentID = %v, want %v", name, span.ParentID, spans[chain[i-1]].SpanID)
+		}
+	}
+	if got := spans["POST /todos"].Attributes["http.status_code"]; got != "201" {
+		t.Errorf("server span http.status_code = %q, want %q", got, "201")
+	}
+}
@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// CheckAPIVersion responds with 412 Precondition Failed to requests from clients that
+// speak a version of the API incompatible with the one of the server, which it names
+// in the X-API-Version header; requests that name no version are let through
+func CheckAPIVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get(domain.APIVersionHeader)
+		if version == "" || domain.CompatibleAPI(version) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(domain.APIVersionHeader, domain.APIVersion)
+		connectError(w, http.StatusPreconditionFailed, "failed_precondition",
+			"the server speaks version "+domain.APIVersion+" of the API, which is incompatible with version "+version)
+	})
+}
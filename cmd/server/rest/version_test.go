@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := map[string]struct {
+		version    string
+		wantStatus int
+	}{
+		"NoVersion":    {wantStatus: http.StatusOK},
+		"Same":         {version: domain.APIVersion, wantStatus: http.StatusOK},
+		"NewerMinor":   {version: "1.9", wantStatus: http.StatusOK},
+		"Incompatible": {version: "2.0", wantStatus: http.StatusPreconditionFailed},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := CheckAPIVersion(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			if tt.version != "" {
+				req.Header.Set(domain.APIVersionHeader, tt.version)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("StatusCode = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			if rec.Code == http.StatusPreconditionFailed && rec.Header().Get(domain.APIVersionHeader) != domain.APIVersion {
+				t.Errorf("%s = %q, want %q", domain.APIVersionHeader, rec.Header().Get(domain.APIVersionHeader), domain.APIVersion)
+			}
+		})
+	}
+}
@@ -9,3 +9,49 @@ htmx.onLoad(function (content) {
     });
   }
 });
+
+htmx.on("htmx:beforeSwap", function (evt) {
+  // validation errors are rendered into the error target chosen by the server
+  if (evt.detail.xhr.status === 422) {
+    evt.detail.shouldSwap = true;
+    evt.detail.isError = false;
+  }
+});
+
+document.addEventListener("click", function (evt) {
+  // the reload button of the version banner installs the worker of the offered build and
+  // hands the page over to it; the old worker serves until then
+  var button = evt.target.closest("[data-update]");
+  if (!button) return;
+  button.disabled = true;
+
+  var build = button.dataset.update;
+  if (!build || !navigator.serviceWorker) {
+    document.location.reload();
+    return;
+  }
+  navigator.serviceWorker.addEventListener("controllerchange", function () {
+    document.location.reload();
+  });
+  navigator.serviceWorker.register("/dist/sw.js?build=" + encodeURIComponent(build), { scope: "/" })
+    .then(function (registration) {
+      var worker = registration.installing || registration.waiting;
+      if (!worker) {
+        document.location.reload();
+        return;
+      }
+      var skipWaiting = function () {
+        if (worker.state === "installed") {
+          worker.postMessage({ type: "SKIP_WAITING" });
+        } else if (worker.state === "redundant") {
+          document.location.reload();
+        }
+      };
+      worker.addEventListener("statechange", skipWaiting);
+      skipWaiting();
+    })
+    .catch(function (error) {
+      console.error("Service Worker error: ", error);
+      document.location.reload();
+    });
+});
@@ -1,41 +1,91 @@
-importScripts("https://cdn.jsdelivr.net/gh/golang/go@go1.20.5/misc/wasm/wasm_exec.js");
+// the wasm_exec.js of the Go toolchain that built client.wasm, copied by go generate
+importScripts("/dist/vendor/wasm_exec.js");
 
-// for tinygo
-// importScripts("/dist/wasm_exec.js");
+// a TinyGo build of client.wasm needs the wasm_exec.js of TinyGo instead, from
+// $(tinygo env TINYGOROOT)/targets/wasm_exec.js
 
-function registerWasmHTTPListener(wasm, { urlMatch = (url) => true, args = [] } = {}) {
+// pages register this worker with the content hash of the proxy they want, so that a
+// new build is a new worker, which waits until the user reloads to take over
+const build = new URL(location.href).searchParams.get("build") ?? "";
+
+// the worker keeps the manifest and proxy it was installed with in its own cache; it
+// keeps serving them after a deploy has removed them from the server
+const cacheName = "proxy-" + build;
+
+async function cached(url, options) {
+  const cache = await caches.open(cacheName);
+  let response = await cache.match(url);
+  if (!response) {
+    response = await fetch(url, options);
+    if (!response.ok) {
+      throw new Error(`failed to fetch ${url}: ${response.status}`);
+    }
+    await cache.put(url, response.clone());
+  }
+  return response;
+}
+
+// the manifest maps the names of the assets to their content-hashed URLs
+function manifest() {
+  return cached("/dist/manifest.json", { cache: "no-cache" }).then(response => response.json());
+}
+
+function registerWasmHTTPListener(wasm, { urlMatch = (url) => true, args = [], env = {} } = {}) {
   let path = new URL(registration.scope).pathname;
+  let handlerPromise;
 
-  const handlerPromise = new Promise(setHandler => {
-    self.wasmhttp = {
-      path,
-      setHandler,
-    };
-  });
+  // the proxy starts with the first request, so a worker waiting to take over does not
+  // run alongside the one in use
+  function start() {
+    handlerPromise ??= new Promise(setHandler => {
+      self.wasmhttp = {
+        path,
+        setHandler,
+      };
 
-  const go = new Go();
-  go.argv = [ wasm, ...args ];
-  WebAssembly.instantiateStreaming(fetch(wasm), go.importObject)
-    .then(({ instance }) => go.run(instance));
+      const go = new Go();
+      Promise.all([wasm, env])
+        .then(([wasm, env]) => {
+          go.argv = [ wasm, ...args ];
+          go.env = env;
+          return WebAssembly.instantiateStreaming(cached(wasm), go.importObject);
+        })
+        .then(({ instance }) => go.run(instance));
+    });
+    return handlerPromise;
+  }
 
   addEventListener("fetch", e => {
     const url = new URL(e.request.url);
     if (!urlMatch(url)) return;
 
-    e.respondWith(handlerPromise.then(handler => handler(e.request)));
+    e.respondWith(start().then(handler => handler(e.request)));
   });
 }
 
-addEventListener("install", (event) => {
-  event.waitUntil(skipWaiting());
+addEventListener("install", event => {
+  event.waitUntil(manifest().then(m => cached(m["client.wasm"] ?? "/dist/client.wasm")));
 });
 
 addEventListener("activate", event => {
-  event.waitUntil(clients.claim());
+  // the proxies of the workers this one replaced
+  event.waitUntil(caches.keys().then(keys => Promise.all(
+    keys.filter(key => key.startsWith("proxy-") && key !== cacheName).map(key => caches.delete(key))
+  )));
+});
+
+// the reload button of the version banner asks a waiting worker to take over
+addEventListener("message", event => {
+  if (event.data?.type === "SKIP_WAITING") {
+    skipWaiting();
+  }
 });
 
-registerWasmHTTPListener("/dist/client.wasm", {
+// the proxy renders the pages with the URLs of the same manifest
+registerWasmHTTPListener(manifest().then(m => m["client.wasm"] ?? "/dist/client.wasm"), {
+  env: manifest().then(m => ({ ASSET_MANIFEST: JSON.stringify(m) })),
   urlMatch: (url) => {
-    return url.host === "localhost:3000" && !url.pathname.startsWith("/dist");
+    // CSP violation reports go to the collector of the server
+    return url.origin === self.location.origin && !url.pathname.startsWith("/dist") && url.pathname !== "/csp-report";
   }
 });
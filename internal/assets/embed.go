@@ -2,17 +2,46 @@ package assets
 
 import (
 	"embed"
+	"io"
+	"io/fs"
+	"mime"
 	"net/http"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/variants"
 )
 
+//go:generate go run ./gen
+//go:generate go run ./precompress
+
 //go:embed all:dist
 var Assets embed.FS
 
-// Mount mounts the embedded assets to a Chi Router
+// immutable is the Cache-Control of content-hashed URLs, whose content never changes
+const immutable = "public, max-age=31536000, immutable"
+
+// Mount mounts the embedded assets, at their plain and content-hashed URLs, and their
+// manifest to a Chi Router, and makes the manifest the one templates resolve asset
+// URLs with
 func Mount(r chi.Router) {
+	dist, _ := fs.Sub(Assets, "dist")
+	m, err := manifest.New(dist)
+	if err != nil {
+		// the embedded files can always be read
+		panic(err)
+	}
+	manifest.Use(m)
+	precompressed, err := variants.Read(dist)
+	if err != nil {
+		panic(err)
+	}
+
 	r.Route("/dist", func(r chi.Router) {
 		r.Use(func(next http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -22,6 +51,63 @@ func Mount(r chi.Router) {
 				next.ServeHTTP(w, r)
 			})
 		})
-		r.Handle("/*", http.FileServer(http.FS(Assets)))
+		r.Get("/manifest.json", manifestHandler(m))
+		r.Get("/*", fileHandler(dist, m, precompressed))
+		r.Head("/*", fileHandler(dist, m, precompressed))
 	})
 }
+
+// manifestHandler serves the manifest, which changes with every build
+func manifestHandler(m manifest.Manifest) http.HandlerFunc {
+	data, _ := json.Marshal(m)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+// fileHandler serves a file by its name, which must be revalidated with its ETag, or by
+// its content-hashed name, which is cached forever; a hash of an older build gets the
+// current file, so that pages of the older build keep working, but not cached. A
+// precompressed variant is served in place of the file when the request accepts it.
+func fileHandler(dist fs.FS, m manifest.Manifest, precompressed variants.Variants) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "*")
+		hash := m.Hash(name)
+		cacheControl := "no-cache"
+		if hash == "" {
+			var requested string
+			name, requested = manifest.Split(name)
+			if hash = m.Hash(name); hash == requested && hash != "" {
+				cacheControl = immutable
+			}
+		}
+		if hash == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, etag := name, hash
+		available := precompressed.For(name, hash)
+		if len(available) != 0 {
+			w.Header().Add("Vary", "Accept-Encoding")
+		}
+		if encoding := variants.Negotiate(r.Header.Get("Accept-Encoding"), available); encoding != nil {
+			file, etag = name+encoding.Ext, hash+"-"+encoding.Name
+			w.Header().Set("Content-Encoding", encoding.Name)
+		}
+		f, err := dist.Open(file)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", `"`+etag+`"`)
+		http.ServeContent(w, r, name, time.Time{}, f.(io.ReadSeeker))
+	}
+}
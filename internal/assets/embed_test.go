@@ -0,0 +1,237 @@
+package assets
+
+import (
+	"bytes"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/thirdparty"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/variants"
+)
+
+func TestAssets_ThirdParty(t *testing.T) {
+	data, err := os.ReadFile("thirdparty/thirdparty.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts, err := thirdparty.Scripts(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, script := range scripts {
+		t.Run(script.Name, func(t *testing.T) {
+			if script.Integrity == "" {
+				t.Fatalf("%s has no pinned integrity; run go generate ./internal/assets", script.File)
+			}
+			content, err := fs.ReadFile(Assets, "dist/"+thirdparty.Asset(script.Name))
+			if err != nil {
+				t.Fatalf("%s is not vendored; run go generate ./internal/assets", script.File)
+			}
+			if got := thirdparty.SRI(content); got != script.Integrity {
+				t.Errorf("SRI(%s) = %s, want %s", script.File, got, script.Integrity)
+			}
+		})
+	}
+}
+
+func TestAssets_WasmExecMatchesToolchain(t *testing.T) {
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		t.Skipf("go env: %v", err)
+	}
+	goroot := strings.TrimSpace(string(out))
+	var want []byte
+	for _, dir := range []string{"lib", "misc"} {
+		if want, err = os.ReadFile(filepath.Join(goroot, dir, "wasm", thirdparty.WasmExec)); err == nil {
+			break
+		}
+	}
+	if want == nil {
+		t.Skipf("%s is not part of the toolchain in %s", thirdparty.WasmExec, goroot)
+	}
+	got, err := fs.ReadFile(Assets, "dist/"+thirdparty.Dir+thirdparty.WasmExec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the one of the toolchain; run go generate ./internal/assets", thirdparty.WasmExec)
+	}
+}
+
+func TestMount(t *testing.T) {
+	router := chi.NewRouter()
+	Mount(router)
+	dist, _ := fs.Sub(Assets, "dist")
+	m, err := manifest.New(dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := m.URL("app.js")
+	etag := `"` + m.Hash("app.js") + `"`
+	stale := "/dist/app.000000000000.js"
+
+	tests := map[string]struct {
+		path             string
+		header           http.Header
+		wantStatus       int
+		wantCacheControl string
+		wantContentType  string
+	}{
+		"Hashed":       {path: hashed, wantStatus: http.StatusOK, wantCacheControl: immutable, wantContentType: "text/javascript; charset=utf-8"},
+		"Plain":        {path: "/dist/app.js", wantStatus: http.StatusOK, wantCacheControl: "no-cache"},
+		"Revalidated":  {path: "/dist/app.js", header: http.Header{"If-None-Match": {etag}}, wantStatus: http.StatusNotModified},
+		"Range":        {path: hashed, header: http.Header{"Range": {"bytes=0-3"}}, wantStatus: http.StatusPartialContent, wantCacheControl: immutable},
+		"OlderBuild":   {path: stale, wantStatus: http.StatusOK, wantCacheControl: "no-cache"},
+		"Vendored":     {path: m.URL("vendor/wasm_exec.js"), wantStatus: http.StatusOK, wantCacheControl: immutable},
+		"Missing":      {path: "/dist/missing.js", wantStatus: http.StatusNotFound},
+		"MissingHash":  {path: "/dist/missing.0123456789ab.js", wantStatus: http.StatusNotFound},
+		"ManifestJSON": {path: manifest.Path, wantStatus: http.StatusOK, wantCacheControl: "no-cache", wantContentType: "application/json"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if tt.wantCacheControl != "" && rec.Header().Get("Cache-Control") != tt.wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", rec.Header().Get("Cache-Control"), tt.wantCacheControl)
+			}
+			if tt.wantContentType != "" && rec.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.wantContentType)
+			}
+		})
+	}
+	if got := manifest.URL("app.js"); got != hashed {
+		t.Errorf("manifest.URL(app.js) = %q after Mount, want %q", got, hashed)
+	}
+}
+
+func TestMount_Precompressed(t *testing.T) {
+	dist := fstest.MapFS{
+		"client.wasm":    {Data: []byte("\x00asm and a lot more")},
+		"client.wasm.br": {Data: []byte("brotli bytes")},
+		"client.wasm.gz": {Data: []byte("gzip bytes")},
+		"app.js":         {Data: []byte("changed since it was compressed")},
+		"app.js.gz":      {Data: []byte("stale gzip bytes")},
+	}
+	m, err := manifest.New(dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasm := m.Hash("client.wasm")
+	precompressed := variants.Variants{
+		"client.wasm": {Hash: wasm, Encodings: []string{"br", "gzip"}},
+		"app.js":      {Hash: "0123456789ab", Encodings: []string{"gzip"}},
+	}
+	router := chi.NewRouter()
+	router.Get("/dist/*", fileHandler(dist, m, precompressed))
+
+	tests := map[string]struct {
+		path         string
+		header       http.Header
+		wantStatus   int
+		wantBody     string
+		wantEncoding string
+		wantVary     bool
+		wantETag     string
+	}{
+		"Brotli": {
+			path:         m.URL("client.wasm"),
+			header:       http.Header{"Accept-Encoding": {"gzip, deflate, br"}},
+			wantStatus:   http.StatusOK,
+			wantBody:     "brotli bytes",
+			wantEncoding: "br",
+			wantVary:     true,
+			wantETag:     `"` + wasm + `-br"`,
+		},
+		"Gzip": {
+			path:         "/dist/client.wasm",
+			header:       http.Header{"Accept-Encoding": {"gzip"}},
+			wantStatus:   http.StatusOK,
+			wantBody:     "gzip bytes",
+			wantEncoding: "gzip",
+			wantVary:     true,
+			wantETag:     `"` + wasm + `-gzip"`,
+		},
+		"Identity": {
+			path:       "/dist/client.wasm",
+			wantStatus: http.StatusOK,
+			wantBody:   "\x00asm and a lot more",
+			wantVary:   true,
+			wantETag:   `"` + wasm + `"`,
+		},
+		"Range": {
+			path:         "/dist/client.wasm",
+			header:       http.Header{"Accept-Encoding": {"br"}, "Range": {"bytes=0-5"}},
+			wantStatus:   http.StatusPartialContent,
+			wantBody:     "brotli",
+			wantEncoding: "br",
+			wantVary:     true,
+		},
+		"Revalidated": {
+			path:       "/dist/client.wasm",
+			header:     http.Header{"Accept-Encoding": {"br"}, "If-None-Match": {`"` + wasm + `-br"`}},
+			wantStatus: http.StatusNotModified,
+			wantVary:   true,
+		},
+		"OtherEncodingNotRevalidated": {
+			path:         "/dist/client.wasm",
+			header:       http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {`"` + wasm + `-br"`}},
+			wantStatus:   http.StatusOK,
+			wantBody:     "gzip bytes",
+			wantEncoding: "gzip",
+			wantVary:     true,
+		},
+		"StaleVariant": {
+			path:       "/dist/app.js",
+			header:     http.Header{"Accept-Encoding": {"gzip"}},
+			wantStatus: http.StatusOK,
+			wantBody:   "changed since it was compressed",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary") == "Accept-Encoding"; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding %v", rec.Header().Get("Vary"), tt.wantVary)
+			}
+			if tt.wantETag != "" && rec.Header().Get("ETag") != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), tt.wantETag)
+			}
+			if tt.wantStatus != http.StatusNotModified && rec.Header().Get("Content-Type") != "application/wasm" && strings.HasSuffix(tt.path, ".wasm") {
+				t.Errorf("Content-Type = %q, want application/wasm", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
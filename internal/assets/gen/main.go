@@ -0,0 +1,154 @@
+// Command gen vendors the front-end dependencies of thirdparty.json into dist/vendor,
+// checking each file against its pinned SRI hash, and copies the wasm_exec.js of the
+// Go toolchain next to them. It is run by go generate in internal/assets.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/assets/thirdparty"
+)
+
+const manifestFile = "thirdparty/thirdparty.json"
+
+var vendorDir = filepath.Join("dist", filepath.FromSlash(thirdparty.Dir))
+
+func main() {
+	var offline bool
+	flag.BoolVar(&offline, "offline", offline, "only check the vendored files, without downloading missing ones")
+	flag.Parse()
+
+	if err := errors.Join(vendorWasmExec(), vendorScripts(offline)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// vendorScripts downloads the missing scripts of the manifest and checks every script
+// against its SRI hash; hashes that are not pinned yet are written to the manifest
+func vendorScripts(offline bool) error {
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return err
+	}
+	scripts, err := thirdparty.Scripts(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", manifestFile, err)
+	}
+
+	var errs []error
+	var pinned bool
+	for i, script := range scripts {
+		name := filepath.Join(vendorDir, script.File)
+		content, err := os.ReadFile(name)
+		downloaded := false
+		if errors.Is(err, fs.ErrNotExist) && !offline {
+			content, err = download(script.URL)
+			downloaded = true
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", script.Name, script.Version, err))
+			continue
+		}
+
+		switch integrity := thirdparty.SRI(content); script.Integrity {
+		case integrity:
+		case "":
+			fmt.Printf("pinned %s %s to %s\n", script.Name, script.Version, integrity)
+			scripts[i].Integrity = integrity
+			pinned = true
+		default:
+			errs = append(errs, fmt.Errorf("%s %s: %s has the hash %s, want %s", script.Name, script.Version, name, integrity, script.Integrity))
+			continue
+		}
+		if downloaded {
+			if err := writeFile(name, content); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Printf("vendored %s %s\n", script.Name, script.Version)
+		}
+	}
+
+	if pinned {
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(scripts); err != nil {
+			return err
+		}
+		errs = append(errs, os.WriteFile(manifestFile, b.Bytes(), 0o644))
+	}
+	return errors.Join(errs...)
+}
+
+// vendorWasmExec copies the wasm_exec.js of the toolchain that builds client.wasm,
+// which must come from the same Go version
+func vendorWasmExec() error {
+	content, version, err := toolchainWasmExec()
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(vendorDir, thirdparty.WasmExec)
+	if current, err := os.ReadFile(name); err == nil && bytes.Equal(current, content) {
+		return nil
+	}
+	if err := writeFile(name, content); err != nil {
+		return err
+	}
+	fmt.Printf("vendored %s of %s\n", thirdparty.WasmExec, version)
+	return nil
+}
+
+// toolchainWasmExec reads the wasm_exec.js of the go command on the PATH, which moved
+// from misc/wasm to lib/wasm in Go 1.24
+func toolchainWasmExec() ([]byte, string, error) {
+	out, err := exec.Command("go", "env", "GOROOT", "GOVERSION").Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("go env: %w", err)
+	}
+	env := strings.Fields(string(out))
+	if len(env) != 2 {
+		return nil, "", fmt.Errorf("go env: unexpected output %q", out)
+	}
+	for _, dir := range []string{"lib", "misc"} {
+		content, err := os.ReadFile(filepath.Join(env[0], dir, "wasm", thirdparty.WasmExec))
+		if err == nil {
+			return content, env[1], nil
+		}
+	}
+	return nil, "", fmt.Errorf("%s is not part of %s in %s", thirdparty.WasmExec, env[1], env[0])
+}
+
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func writeFile(name string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, content, 0o644)
+}
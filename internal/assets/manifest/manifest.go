@@ -0,0 +1,134 @@
+// Package manifest maps the names of the assets to content-hashed URLs, which can be
+// cached forever since a new build of an asset gets a new URL
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/assets/variants"
+)
+
+const (
+	// Prefix is the path the assets are served from
+	Prefix = "/dist/"
+	// Path is the path the server serves the manifest from
+	Path = Prefix + "manifest.json"
+	// Env is the environment variable the service worker passes the manifest to the proxy in
+	Env = "ASSET_MANIFEST"
+
+	hashLen = 12
+)
+
+// Manifest maps the names of the assets, their paths below Prefix, to their URLs
+type Manifest map[string]string
+
+var current atomic.Pointer[Manifest]
+
+// New hashes every file of fsys but the precompressed variants, which are served in
+// place of the assets they were compressed from
+func New(fsys fs.FS) (Manifest, error) {
+	m := Manifest{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || variants.IsVariant(name) {
+			return err
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		m[name] = Prefix + hashed(name, hex.EncodeToString(h.Sum(nil))[:hashLen])
+		return nil
+	})
+	return m, err
+}
+
+// Parse decodes a manifest from its JSON
+func Parse(data string) (Manifest, error) {
+	var m Manifest
+	err := json.Unmarshal([]byte(data), &m)
+	return m, err
+}
+
+// URL returns the content-hashed URL of the named asset, or its plain URL when the
+// manifest does not list it
+func (m Manifest) URL(name string) string {
+	if url, ok := m[name]; ok {
+		return url
+	}
+	return Prefix + name
+}
+
+// Hash returns the content hash of the named asset, or an empty string when the
+// manifest does not list it
+func (m Manifest) Hash(name string) string {
+	url, ok := m[name]
+	if !ok {
+		return ""
+	}
+	_, hash := Split(strings.TrimPrefix(url, Prefix))
+	return hash
+}
+
+// Split returns the name and hash of a content-hashed name, such as app.0123456789ab.js,
+// or the name and an empty hash for any other name
+func Split(name string) (string, string) {
+	dir, file := path.Split(name)
+	ext := path.Ext(file)
+	base := strings.TrimSuffix(file, ext)
+	hash := path.Ext(base)
+	if len(hash) != hashLen+1 || !isHex(hash[1:]) {
+		return name, ""
+	}
+	return dir + strings.TrimSuffix(base, hash) + ext, hash[1:]
+}
+
+// Use makes m the manifest URL resolves names with
+func Use(m Manifest) {
+	current.Store(&m)
+}
+
+// URL returns the content-hashed URL of the named asset in the manifest given to Use,
+// or its plain URL before Use is called
+func URL(name string) string {
+	if m := current.Load(); m != nil {
+		return m.URL(name)
+	}
+	return Prefix + name
+}
+
+// Hash returns the content hash of the named asset in the manifest given to Use, or an
+// empty string before Use is called
+func Hash(name string) string {
+	if m := current.Load(); m != nil {
+		return m.Hash(name)
+	}
+	return ""
+}
+
+// hashed inserts the hash before the extension of name
+func hashed(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestNew(t *testing.T) {
+	m, err := New(fstest.MapFS{
+		"app.js":             {Data: []byte("htmx.onLoad()")},
+		"vendor/htmx.min.js": {Data: []byte("var htmx")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 {
+		t.Fatalf("New() = %v, want both files", m)
+	}
+	for name, url := range m {
+		if got, hash := Split(url[len(Prefix):]); got != name || hash != m.Hash(name) || len(hash) != hashLen {
+			t.Errorf("New()[%q] = %q, want the name with a hash of %d hex digits", name, url, hashLen)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		wantName string
+		wantHash string
+	}{
+		"Hashed":      {name: "app.0123456789ab.js", wantName: "app.js", wantHash: "0123456789ab"},
+		"HashedInDir": {name: "vendor/htmx.min.0123456789ab.js", wantName: "vendor/htmx.min.js", wantHash: "0123456789ab"},
+		"Plain":       {name: "vendor/htmx.min.js", wantName: "vendor/htmx.min.js"},
+		"ShortHash":   {name: "app.0123.js", wantName: "app.0123.js"},
+		"NotHex":      {name: "app.0123456789xy.js", wantName: "app.0123456789xy.js"},
+		"NoExtension": {name: "LICENSE", wantName: "LICENSE"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotName, gotHash := Split(tt.name)
+			if gotName != tt.wantName || gotHash != tt.wantHash {
+				t.Errorf("Split(%q) = %q, %q, want %q, %q", tt.name, gotName, gotHash, tt.wantName, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	if got := URL("app.js"); got != "/dist/app.js" {
+		t.Errorf("URL() before Use = %q, want the plain URL", got)
+	}
+	Use(Manifest{"app.js": "/dist/app.0123456789ab.js"})
+	t.Cleanup(func() { current.Store(nil) })
+	if got := URL("app.js"); got != "/dist/app.0123456789ab.js" {
+		t.Errorf("URL() = %q, want the hashed URL", got)
+	}
+	if got := URL("styles.css"); got != "/dist/styles.css" {
+		t.Errorf("URL() of an unlisted asset = %q, want the plain URL", got)
+	}
+}
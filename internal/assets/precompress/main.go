@@ -0,0 +1,135 @@
+// Command precompress writes the gzip and, when the brotli command is on the PATH,
+// Brotli variants of the compressible assets in dist, and lists them in the variants
+// index. It is run by go generate in internal/assets, and again after client.wasm is built.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+	"github.com/stackus/todos-htmx-wasm/internal/assets/variants"
+)
+
+const dir = "dist"
+
+// compressible are the extensions of the assets worth compressing; images such as PNGs
+// are compressed already
+var compressible = map[string]bool{
+	".css":  true,
+	".html": true,
+	".js":   true,
+	".json": true,
+	".svg":  true,
+	".wasm": true,
+}
+
+// minSize is the size below which compressing saves less than the headers cost
+const minSize = 1 << 10
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	dist := os.DirFS(dir)
+	if err := removeVariants(dist); err != nil {
+		return err
+	}
+	m, err := manifest.New(dist)
+	if err != nil {
+		return err
+	}
+	brotli, err := exec.LookPath("brotli")
+	if err != nil {
+		fmt.Println("brotli is not on the PATH; only gzip variants are written")
+	}
+
+	index := variants.Variants{}
+	for name := range m {
+		if !compressible[path.Ext(name)] || name == variants.Index {
+			continue
+		}
+		content, err := fs.ReadFile(dist, name)
+		if err != nil {
+			return err
+		}
+		if len(content) < minSize {
+			continue
+		}
+		variant := variants.Variant{Hash: m.Hash(name)}
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		for _, encoding := range variants.Encodings {
+			var compressed []byte
+			switch {
+			case encoding.Name == "gzip":
+				compressed, err = gzipped(content)
+			case encoding.Name == "br" && brotli != "":
+				compressed, err = exec.Command(brotli, "--best", "--stdout", file).Output()
+			default:
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", encoding.Name, name, err)
+			}
+			if len(compressed) >= len(content) {
+				continue
+			}
+			if err := os.WriteFile(file+encoding.Ext, compressed, 0o644); err != nil {
+				return err
+			}
+			variant.Encodings = append(variant.Encodings, encoding.Name)
+		}
+		if len(variant.Encodings) != 0 {
+			index[name] = variant
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("precompressed %d assets\n", len(index))
+	return os.WriteFile(filepath.Join(dir, variants.Index), append(data, '\n'), 0o644)
+}
+
+// removeVariants removes the variants of an earlier run, so that none outlives its asset
+func removeVariants(dist fs.FS) error {
+	var errs []error
+	err := fs.WalkDir(dist, ".", func(name string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && variants.IsVariant(name) {
+			errs = append(errs, os.Remove(filepath.Join(dir, filepath.FromSlash(name))))
+		}
+		return err
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// gzipped compresses content at the best level, without a name or time in the header
+// so that the output only changes with the content
+func gzipped(content []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w, err := gzip.NewWriterLevel(&b, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
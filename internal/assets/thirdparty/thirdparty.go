@@ -0,0 +1,68 @@
+// Package thirdparty lists the front-end dependencies that are served from the
+// embedded assets; it is kept apart from them so that templates can read it without
+// embedding the assets
+package thirdparty
+
+import (
+	"crypto/sha512"
+	_ "embed"
+	"encoding/base64"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// Dir is the directory of the files among the assets in internal/assets/dist
+const Dir = "vendor/"
+
+// WasmExec is the file of the wasm_exec.js of the Go toolchain that builds client.wasm
+const WasmExec = "wasm_exec.js"
+
+// Script is a pinned front-end dependency
+type Script struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// URL is where the generator downloads the file from
+	URL  string `json:"url"`
+	File string `json:"file"`
+	// Integrity is the SRI hash of the file; the generator pins it on the first
+	// download, and refuses any file that does not match it after that
+	Integrity string `json:"integrity"`
+}
+
+//go:embed thirdparty.json
+var manifest []byte
+
+var scripts = func() map[string]Script {
+	list, err := Scripts(manifest)
+	if err != nil {
+		panic(err)
+	}
+	byName := make(map[string]Script, len(list))
+	for _, script := range list {
+		byName[script.Name] = script
+	}
+	return byName
+}()
+
+// Scripts decodes a manifest
+func Scripts(data []byte) ([]Script, error) {
+	var list []Script
+	err := json.Unmarshal(data, &list)
+	return list, err
+}
+
+// SRI returns the sha384 Subresource Integrity hash of data
+func SRI(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Asset returns the name of the named script among the assets
+func Asset(name string) string {
+	return Dir + scripts[name].File
+}
+
+// Integrity returns the SRI hash of the named script
+func Integrity(name string) string {
+	return scripts[name].Integrity
+}
@@ -0,0 +1,117 @@
+// Package variants describes the precompressed variants of the assets, which go
+// generate writes next to them, and picks the one a request accepts
+package variants
+
+import (
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// Index is the file, among the assets, that lists their variants
+const Index = "precompressed.json"
+
+// Encoding is a content coding and the extension of its variants
+type Encoding struct {
+	Name string
+	Ext  string
+}
+
+// Encodings are the content codings of the variants, in order of preference
+var Encodings = []Encoding{
+	{Name: "br", Ext: ".br"},
+	{Name: "gzip", Ext: ".gz"},
+}
+
+type (
+	// Variants lists the variants of each asset by its name
+	Variants map[string]Variant
+
+	// Variant lists the encodings an asset was compressed with, and the content hash of
+	// the asset they were compressed from
+	Variant struct {
+		Hash      string   `json:"hash"`
+		Encodings []string `json:"encodings"`
+	}
+)
+
+// Read reads the Index of fsys; there are no variants without one
+func Read(fsys fs.FS) (Variants, error) {
+	data, err := fs.ReadFile(fsys, Index)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Variants{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var v Variants
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
+
+// For returns the encodings of the variants of the named asset, or none when they were
+// compressed from a different version of it than the one with the hash
+func (v Variants) For(name, hash string) []string {
+	if variant, ok := v[name]; ok && variant.Hash == hash {
+		return variant.Encodings
+	}
+	return nil
+}
+
+// IsVariant reports whether name is the file of a variant
+func IsVariant(name string) bool {
+	for _, encoding := range Encodings {
+		if strings.HasSuffix(name, encoding.Ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate returns the most preferred of the available encodings that the
+// Accept-Encoding header accepts, or nil for the asset itself
+func Negotiate(acceptEncoding string, available []string) *Encoding {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		accepted[strings.ToLower(strings.TrimSpace(coding))] = QValue(params) > 0
+	}
+	for i, encoding := range Encodings {
+		ok, listed := accepted[encoding.Name]
+		if !listed {
+			ok = accepted["*"]
+		}
+		if ok && contains(available, encoding.Name) {
+			return &Encodings[i]
+		}
+	}
+	return nil
+}
+
+// QValue returns the quality of the parameters of a coding or media range, which is 1
+// when they do not set it
+func QValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(name, "q") {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0
+			}
+			return q
+		}
+	}
+	return 1
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
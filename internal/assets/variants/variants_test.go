@@ -0,0 +1,57 @@
+package variants
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestNegotiate(t *testing.T) {
+	both := []string{"br", "gzip"}
+	tests := map[string]struct {
+		acceptEncoding string
+		available      []string
+		want           string
+	}{
+		"Brotli":           {acceptEncoding: "gzip, deflate, br", available: both, want: "br"},
+		"Gzip":             {acceptEncoding: "gzip, deflate", available: both, want: "gzip"},
+		"OnlyGzipVariant":  {acceptEncoding: "gzip, deflate, br", available: []string{"gzip"}, want: "gzip"},
+		"BrotliRefused":    {acceptEncoding: "br;q=0, gzip;q=0.5", available: both, want: "gzip"},
+		"Wildcard":         {acceptEncoding: "*", available: both, want: "br"},
+		"WildcardExcluded": {acceptEncoding: "*, br;q=0", available: both, want: "gzip"},
+		"UpperCase":        {acceptEncoding: "GZIP", available: both, want: "gzip"},
+		"Identity":         {acceptEncoding: "identity", available: both},
+		"NoHeader":         {available: both},
+		"NoVariants":       {acceptEncoding: "gzip, br"},
+		"BadQuality":       {acceptEncoding: "br;q=high", available: both},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if encoding := Negotiate(tt.acceptEncoding, tt.available); encoding != nil {
+				got = encoding.Name
+			}
+			if got != tt.want {
+				t.Errorf("Negotiate(%q, %v) = %q, want %q", tt.acceptEncoding, tt.available, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	v, err := Read(fstest.MapFS{
+		Index: {Data: []byte(`{"app.js":{"hash":"0123456789ab","encodings":["br","gzip"]}}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.For("app.js", "0123456789ab"); len(got) != 2 {
+		t.Errorf("For() = %v, want both encodings", got)
+	}
+	if got := v.For("app.js", "ba9876543210"); got != nil {
+		t.Errorf("For() of a changed asset = %v, want none", got)
+	}
+
+	if v, err := Read(fstest.MapFS{}); err != nil || len(v) != 0 {
+		t.Errorf("Read() without an index = %v, %v, want no variants", v, err)
+	}
+}
@@ -0,0 +1,92 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Names of the files written by DevCertificate
+const (
+	DevCertFile = "cert.pem"
+	DevKeyFile  = "key.pem"
+)
+
+// devValidFor is how long a development certificate is valid
+const devValidFor = 365 * 24 * time.Hour
+
+// DevCertificate returns the paths of a self-signed certificate for localhost in dir.
+// The certificate is generated when dir holds none, or when it cannot be loaded or has
+// expired; created reports whether that happened.
+func DevCertificate(dir string) (certFile, keyFile string, created bool, err error) {
+	certFile = filepath.Join(dir, DevCertFile)
+	keyFile = filepath.Join(dir, DevKeyFile)
+
+	if cert, err := tls.LoadX509KeyPair(certFile, keyFile); err == nil {
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil && time.Now().Before(leaf.NotAfter) {
+			return certFile, keyFile, false, nil
+		}
+	}
+
+	certPEM, keyPEM, err := generateDev(time.Now())
+	if err != nil {
+		return "", "", false, err
+	}
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", false, err
+	}
+	if err = os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		return "", "", false, err
+	}
+	if err = os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		return "", "", false, err
+	}
+	return certFile, keyFile, true, nil
+}
+
+func generateDev(now time.Time) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"todos-htmx-wasm development"},
+			CommonName:   "localhost",
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(devValidFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,93 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDevCertificate(t *testing.T) {
+	tests := map[string]struct {
+		setup       func(t *testing.T, dir string)
+		wantCreated bool
+	}{
+		"FirstRun": {
+			setup:       func(t *testing.T, dir string) {},
+			wantCreated: true,
+		},
+		"Existing": {
+			setup: func(t *testing.T, dir string) {
+				if _, _, _, err := DevCertificate(dir); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCreated: false,
+		},
+		"Expired": {
+			setup: func(t *testing.T, dir string) {
+				writeDev(t, dir, time.Now().Add(-2*devValidFor))
+			},
+			wantCreated: true,
+		},
+		"Corrupt": {
+			setup: func(t *testing.T, dir string) {
+				writeDev(t, dir, time.Now())
+				if err := os.WriteFile(filepath.Join(dir, DevKeyFile), []byte("not a key"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCreated: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), ".certs")
+			tt.setup(t, dir)
+
+			certFile, keyFile, created, err := DevCertificate(dir)
+			if err != nil {
+				t.Fatalf("DevCertificate() error = %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("DevCertificate() created = %v, want %v", created, tt.wantCreated)
+			}
+
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				t.Fatalf("LoadX509KeyPair() error = %v", err)
+			}
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+				if err := leaf.VerifyHostname(host); err != nil {
+					t.Errorf("VerifyHostname(%s) error = %v", host, err)
+				}
+			}
+			if !time.Now().Before(leaf.NotAfter) {
+				t.Errorf("certificate expired at %s", leaf.NotAfter)
+			}
+		})
+	}
+}
+
+func writeDev(t *testing.T, dir string, now time.Time) {
+	t.Helper()
+	certPEM, keyPEM, err := generateDev(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DevCertFile), certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DevKeyFile), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
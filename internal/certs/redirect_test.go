@@ -0,0 +1,49 @@
+package certs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	tests := map[string]struct {
+		addr   string
+		target string
+		want   string
+	}{
+		"WithPort": {
+			addr:   ":3443",
+			target: "http://localhost:3000/todos?search=cat",
+			want:   "https://localhost:3443/todos?search=cat",
+		},
+		"DefaultPort": {
+			addr:   ":443",
+			target: "http://example.com/",
+			want:   "https://example.com/",
+		},
+		"IPv6": {
+			addr:   ":3443",
+			target: "http://[::1]:3000/todos",
+			want:   "https://[::1]:3443/todos",
+		},
+		"IPv6DefaultPort": {
+			addr:   "0.0.0.0:443",
+			target: "http://[::1]/",
+			want:   "https://[::1]/",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Redirect(tt.addr).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+type (
+	// Reloader serves a certificate and key pair from files and picks up new files
+	// without restarting the server
+	Reloader struct {
+		certFile string
+		keyFile  string
+
+		mu    sync.RWMutex
+		cert  *tls.Certificate
+		stamp stamp
+	}
+
+	// stamp identifies the versions of the two files that were loaded
+	stamp struct {
+		certMod, keyMod   time.Time
+		certSize, keySize int64
+	}
+)
+
+// NewReloader loads the certificate and key pair from the files
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns a configuration that serves the current certificate over HTTP/2
+// or HTTP/1.1
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2", "http/1.1"},
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// GetCertificate returns the current certificate; it is used as tls.Config.GetCertificate
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Reload loads the files again when either has changed since they were last loaded.
+// A pair that fails to load, such as a certificate written before its key, leaves the
+// current certificate in place and is tried again on the next call.
+func (r *Reloader) Reload() (bool, error) {
+	current, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+	r.mu.RLock()
+	unchanged := r.cert != nil && current == r.stamp
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("loading certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.stamp = current
+	return true, nil
+}
+
+// Watch calls Reload every interval until ctx is canceled and logs the outcome
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			switch {
+			case err != nil:
+				log.Ctx(ctx).Error().Err(err).Str("CertFile", r.certFile).Msg("failed to reload the TLS certificate")
+			case reloaded:
+				log.Ctx(ctx).Info().Str("CertFile", r.certFile).Msg("reloaded the TLS certificate")
+			}
+		}
+	}
+}
+
+func (r *Reloader) stat() (stamp, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return stamp{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return stamp{}, err
+	}
+	return stamp{
+		certMod:  certInfo.ModTime(),
+		keyMod:   keyInfo.ModTime(),
+		certSize: certInfo.Size(),
+		keySize:  keyInfo.Size(),
+	}, nil
+}
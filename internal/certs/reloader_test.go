@@ -0,0 +1,76 @@
+package certs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	tests := map[string]struct {
+		change       func(t *testing.T, dir string)
+		wantReloaded bool
+		wantErr      bool
+		wantNewCert  bool
+	}{
+		"Unchanged": {
+			change: func(t *testing.T, dir string) {},
+		},
+		"Replaced": {
+			change: func(t *testing.T, dir string) {
+				writeDev(t, dir, time.Now())
+			},
+			wantReloaded: true,
+			wantNewCert:  true,
+		},
+		"KeyNotYetWritten": {
+			change: func(t *testing.T, dir string) {
+				certPEM, _, err := generateDev(time.Now())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, DevCertFile), certPEM, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+		"Removed": {
+			change: func(t *testing.T, dir string) {
+				if err := os.Remove(filepath.Join(dir, DevKeyFile)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeDev(t, dir, time.Now())
+			r, err := NewReloader(filepath.Join(dir, DevCertFile), filepath.Join(dir, DevKeyFile))
+			if err != nil {
+				t.Fatalf("NewReloader() error = %v", err)
+			}
+			before, _ := r.GetCertificate(nil)
+
+			// make sure a rewrite gets a new modification time
+			time.Sleep(10 * time.Millisecond)
+			tt.change(t, dir)
+
+			reloaded, err := r.Reload()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if reloaded != tt.wantReloaded {
+				t.Errorf("Reload() = %v, want %v", reloaded, tt.wantReloaded)
+			}
+			after, _ := r.GetCertificate(nil)
+			if changed := !bytes.Equal(before.Certificate[0], after.Certificate[0]); changed != tt.wantNewCert {
+				t.Errorf("certificate changed = %v, want %v", changed, tt.wantNewCert)
+			}
+		})
+	}
+}
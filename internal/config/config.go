@@ -0,0 +1,321 @@
+package config
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/security"
+)
+
+// FileEnv names the environment variable that is read for the config file when the
+// -config flag is not given
+const FileEnv = "CONFIG_FILE"
+
+type (
+	// Config is the configuration of the server. It is built by Load from, in increasing
+	// order of precedence, Default, a YAML or TOML file, environment variables and flags.
+	Config struct {
+		HTTP    HTTPConfig    `yaml:"http" toml:"http"`
+		TLS     TLSConfig     `yaml:"tls" toml:"tls"`
+		GRPC    GRPCConfig    `yaml:"grpc" toml:"grpc"`
+		Metrics MetricsConfig `yaml:"metrics" toml:"metrics"`
+		Trace   TraceConfig   `yaml:"trace" toml:"trace"`
+		Log     log.Config    `yaml:"log" toml:"log"`
+		// Security configures the security headers of every response
+		Security security.Config `yaml:"security" toml:"security"`
+		// Seed is the list of todos every new user starts with; it is only read from the config file
+		Seed []string `yaml:"seed" toml:"seed"`
+	}
+
+	// HTTPConfig configures the main HTTP server
+	HTTPConfig struct {
+		// Port is the address to listen on, e.g. ":3000"
+		Port string `yaml:"port" toml:"port"`
+		// RequestTimeout is the time a handler has to write its response
+		RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout"`
+		// CompressionLevel is the gzip and deflate level of responses, from 1 to 9
+		CompressionLevel int `yaml:"compression_level" toml:"compression_level"`
+		// ShutdownTimeout is the time in-flight requests have to finish on shutdown
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	}
+
+	// TLSConfig configures serving HTTPS and HTTP/2; when it is enabled the main HTTP
+	// server redirects to HTTPS
+	TLSConfig struct {
+		// Enabled serves the router over HTTPS
+		Enabled bool `yaml:"enabled" toml:"enabled"`
+		// Port is the address to listen on for HTTPS, e.g. ":3443"
+		Port string `yaml:"port" toml:"port"`
+		// CertFile and KeyFile hold the certificate and key; when both are empty a
+		// self-signed development certificate is generated into DevCertDir
+		CertFile string `yaml:"cert_file" toml:"cert_file"`
+		KeyFile  string `yaml:"key_file" toml:"key_file"`
+		// DevCertDir is the directory of the development certificate
+		DevCertDir string `yaml:"dev_cert_dir" toml:"dev_cert_dir"`
+		// ReloadInterval is how often the files are checked for a new certificate
+		ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval"`
+	}
+
+	// GRPCConfig configures the server for gRPC over cleartext HTTP/2, or over HTTPS
+	// when TLS is enabled
+	GRPCConfig struct {
+		// Port is the address to listen on, e.g. ":3001"
+		Port string `yaml:"port" toml:"port"`
+	}
+
+	// MetricsConfig configures the admin server that serves the metrics; they count the
+	// todos of every user, so they are kept off the public router
+	MetricsConfig struct {
+		// Port is the address to listen on, e.g. "localhost:9090"; empty disables it
+		Port string `yaml:"port" toml:"port"`
+	}
+
+	// TraceConfig configures the exporting of trace spans
+	TraceConfig struct {
+		// Stdout writes finished spans to stdout as JSON
+		Stdout bool `yaml:"stdout" toml:"stdout"`
+	}
+)
+
+// Default returns the configuration used when nothing else is set
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Port:             ":3000",
+			RequestTimeout:   30 * time.Second,
+			CompressionLevel: 5,
+			ShutdownTimeout:  15 * time.Second,
+		},
+		TLS: TLSConfig{
+			Port:           ":3443",
+			DevCertDir:     ".certs",
+			ReloadInterval: 10 * time.Second,
+		},
+		GRPC: GRPCConfig{
+			Port: ":3001",
+		},
+		Metrics: MetricsConfig{
+			Port: "localhost:9090",
+		},
+		Log:      log.DefaultConfig(),
+		Security: security.DefaultConfig(),
+		Seed: []string{
+			"Bake a cake",
+			"Feed the cat",
+			"Take out the trash",
+		},
+	}
+}
+
+// Load registers the flags of every setting, plus -config, on fs and parses args. It
+// then reads the config file, the environment using lookup, usually os.LookupEnv, and
+// the flags that were set over the defaults, and validates the result.
+func Load(fs *flag.FlagSet, args []string, lookup func(string) (string, bool)) (Config, error) {
+	var file string
+	flags := Default()
+	flags.RegisterFlags(fs)
+	fs.StringVar(&file, "config", "", "read the configuration from this YAML or TOML file; env "+FileEnv)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if file == "" {
+		file, _ = lookup(FileEnv)
+	}
+
+	cfg := Default()
+	if file != "" {
+		if err := cfg.LoadFile(file); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.LoadEnv(lookup); err != nil {
+		return Config{}, err
+	}
+
+	// only the flags that were set override the file and the environment
+	set := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	cfg.RegisterFlags(set)
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if set.Lookup(f.Name) != nil && err == nil {
+			err = set.Set(f.Name, f.Value.String())
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// LoadFile reads a .yaml, .yml or .toml file over the configuration; settings the
+// file leaves out keep their values and unknown settings are an error
+func (c *Config) LoadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(c); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), c)
+		if undecoded := md.Undecoded(); err == nil && len(undecoded) > 0 {
+			err = fmt.Errorf("unknown setting %q", undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q", name, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadEnv reads environment variables over the configuration using lookup
+func (c *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	var err error
+	str := func(key string, v *string) {
+		if value, ok := lookup(key); ok {
+			*v = value
+		}
+	}
+	parse := func(key string, set func(string) error) {
+		if value, ok := lookup(key); ok && err == nil {
+			if parseErr := set(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		}
+	}
+	duration := func(v *time.Duration) func(string) (err error) {
+		return func(s string) (err error) { *v, err = time.ParseDuration(s); return }
+	}
+
+	str("HTTP_PORT", &c.HTTP.Port)
+	parse("HTTP_REQUEST_TIMEOUT", duration(&c.HTTP.RequestTimeout))
+	parse("HTTP_COMPRESSION_LEVEL", func(s string) (err error) { c.HTTP.CompressionLevel, err = strconv.Atoi(s); return })
+	parse("HTTP_SHUTDOWN_TIMEOUT", duration(&c.HTTP.ShutdownTimeout))
+	parse("TLS_ENABLED", func(s string) (err error) { c.TLS.Enabled, err = strconv.ParseBool(s); return })
+	str("TLS_PORT", &c.TLS.Port)
+	str("TLS_CERT_FILE", &c.TLS.CertFile)
+	str("TLS_KEY_FILE", &c.TLS.KeyFile)
+	str("TLS_DEV_CERT_DIR", &c.TLS.DevCertDir)
+	parse("TLS_RELOAD_INTERVAL", duration(&c.TLS.ReloadInterval))
+	str("GRPC_PORT", &c.GRPC.Port)
+	str("METRICS_PORT", &c.Metrics.Port)
+	parse("TRACE_STDOUT", func(s string) (err error) { c.Trace.Stdout, err = strconv.ParseBool(s); return })
+	if err != nil {
+		return err
+	}
+	if err := c.Log.LoadEnv(lookup); err != nil {
+		return err
+	}
+	return c.Security.LoadEnv(lookup)
+}
+
+// RegisterFlags adds a flag for every setting but Seed to fs, whose defaults are the
+// current configuration
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.HTTP.Port, "port", c.HTTP.Port, "port to listen on; env HTTP_PORT")
+	fs.DurationVar(&c.HTTP.RequestTimeout, "request-timeout", c.HTTP.RequestTimeout, "time allowed for a request; env HTTP_REQUEST_TIMEOUT")
+	fs.IntVar(&c.HTTP.CompressionLevel, "compression-level", c.HTTP.CompressionLevel, "gzip level of responses, 1 to 9; env HTTP_COMPRESSION_LEVEL")
+	fs.DurationVar(&c.HTTP.ShutdownTimeout, "shutdown-timeout", c.HTTP.ShutdownTimeout, "time allowed for in-flight requests to finish on shutdown; env HTTP_SHUTDOWN_TIMEOUT")
+	fs.BoolVar(&c.TLS.Enabled, "tls", c.TLS.Enabled, "serve HTTPS and redirect HTTP to it; env TLS_ENABLED")
+	fs.StringVar(&c.TLS.Port, "tls-port", c.TLS.Port, "port to listen on for HTTPS; env TLS_PORT")
+	fs.StringVar(&c.TLS.CertFile, "tls-cert", c.TLS.CertFile, "certificate file; env TLS_CERT_FILE")
+	fs.StringVar(&c.TLS.KeyFile, "tls-key", c.TLS.KeyFile, "key file; env TLS_KEY_FILE")
+	fs.StringVar(&c.TLS.DevCertDir, "tls-dev-cert-dir", c.TLS.DevCertDir, "directory of the generated development certificate; env TLS_DEV_CERT_DIR")
+	fs.DurationVar(&c.TLS.ReloadInterval, "tls-reload-interval", c.TLS.ReloadInterval, "how often to check for a new certificate; env TLS_RELOAD_INTERVAL")
+	fs.StringVar(&c.GRPC.Port, "grpc-port", c.GRPC.Port, "port to listen on for gRPC, over HTTPS when TLS is enabled; env GRPC_PORT")
+	fs.StringVar(&c.Metrics.Port, "metrics-port", c.Metrics.Port, "port to serve /metrics on, empty to disable; env METRICS_PORT")
+	fs.BoolVar(&c.Trace.Stdout, "trace", c.Trace.Stdout, "write finished trace spans to stdout as JSON; env TRACE_STDOUT")
+	c.Log.RegisterFlags(fs)
+	c.Security.RegisterFlags(fs)
+}
+
+// Validate reports the first invalid setting
+func (c Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.HTTP.Port); err != nil {
+		return fmt.Errorf("http port %q: %w", c.HTTP.Port, err)
+	}
+	if _, _, err := net.SplitHostPort(c.GRPC.Port); err != nil {
+		return fmt.Errorf("grpc port %q: %w", c.GRPC.Port, err)
+	}
+	if c.HTTP.Port == c.GRPC.Port {
+		return fmt.Errorf("http and grpc port: both are %q", c.HTTP.Port)
+	}
+	if c.Metrics.Port != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Port); err != nil {
+			return fmt.Errorf("metrics port %q: %w", c.Metrics.Port, err)
+		}
+		if c.Metrics.Port == c.HTTP.Port || c.Metrics.Port == c.GRPC.Port {
+			return fmt.Errorf("metrics port %q: already used by http or grpc", c.Metrics.Port)
+		}
+	}
+	if c.HTTP.RequestTimeout <= 0 {
+		return fmt.Errorf("http request timeout %s: must be positive", c.HTTP.RequestTimeout)
+	}
+	if c.HTTP.CompressionLevel < flate.BestSpeed || c.HTTP.CompressionLevel > flate.BestCompression {
+		return fmt.Errorf("http compression level %d: must be from %d to %d", c.HTTP.CompressionLevel, flate.BestSpeed, flate.BestCompression)
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		return fmt.Errorf("http shutdown timeout %s: must be positive", c.HTTP.ShutdownTimeout)
+	}
+	if c.TLS.Enabled {
+		if err := c.TLS.validate(c.HTTP.Port, c.GRPC.Port); err != nil {
+			return err
+		}
+	}
+	if err := c.Log.Validate(); err != nil {
+		return err
+	}
+	return c.Security.Validate()
+}
+
+func (c TLSConfig) validate(httpPort, grpcPort string) error {
+	if _, _, err := net.SplitHostPort(c.Port); err != nil {
+		return fmt.Errorf("tls port %q: %w", c.Port, err)
+	}
+	if c.Port == httpPort || c.Port == grpcPort {
+		return fmt.Errorf("tls port %q: already used by http or grpc", c.Port)
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("tls cert and key files: set both or neither")
+	}
+	if c.CertFile == "" && c.DevCertDir == "" {
+		return fmt.Errorf("tls dev cert dir: must be set when no cert file is")
+	}
+	if c.ReloadInterval <= 0 {
+		return fmt.Errorf("tls reload interval %s: must be positive", c.ReloadInterval)
+	}
+	return nil
+}
+
+// Write writes the configuration to w as YAML
+func (c Config) Write(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
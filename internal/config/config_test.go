@@ -0,0 +1,250 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		file    string
+		content string
+		env     map[string]string
+		args    []string
+		want    func(cfg *Config)
+		wantErr string
+	}{
+		"Defaults": {
+			want: func(cfg *Config) {},
+		},
+		"YAML": {
+			file: "config.yaml",
+			content: `
+http:
+  port: ":4000"
+  request_timeout: 10s
+log:
+  format: json
+seed: ["One", "Two"]
+`,
+			want: func(cfg *Config) {
+				cfg.HTTP.Port = ":4000"
+				cfg.HTTP.RequestTimeout = 10 * time.Second
+				cfg.Log.Format = "json"
+				cfg.Seed = []string{"One", "Two"}
+			},
+		},
+		"TOML": {
+			file: "config.toml",
+			content: `
+seed = []
+
+[http]
+compression_level = 9
+shutdown_timeout = "1m"
+
+[trace]
+stdout = true
+`,
+			want: func(cfg *Config) {
+				cfg.HTTP.CompressionLevel = 9
+				cfg.HTTP.ShutdownTimeout = time.Minute
+				cfg.Trace.Stdout = true
+				cfg.Seed = []string{}
+			},
+		},
+		"FileFromEnv": {
+			file:    "config.yml",
+			content: "grpc:\n  port: \":4001\"\n",
+			env:     map[string]string{FileEnv: "config.yml"},
+			want: func(cfg *Config) {
+				cfg.GRPC.Port = ":4001"
+			},
+		},
+		"EnvOverridesFile": {
+			file:    "config.yaml",
+			content: "http:\n  port: \":4000\"\nlog:\n  level: warn\n",
+			env:     map[string]string{"HTTP_PORT": ":5000", "LOG_LEVEL": "error"},
+			want: func(cfg *Config) {
+				cfg.HTTP.Port = ":5000"
+				cfg.Log.Level = "error"
+			},
+		},
+		"FlagsOverrideEnv": {
+			file:    "config.yaml",
+			content: "http:\n  port: \":4000\"\n  request_timeout: 10s\n",
+			env:     map[string]string{"HTTP_PORT": ":5000"},
+			args:    []string{"-port", ":6000", "-log-level", "info"},
+			want: func(cfg *Config) {
+				cfg.HTTP.Port = ":6000"
+				cfg.HTTP.RequestTimeout = 10 * time.Second
+				cfg.Log.Level = "info"
+			},
+		},
+		"TLS": {
+			file:    "config.yaml",
+			content: "tls:\n  enabled: true\n  cert_file: cert.pem\n",
+			env:     map[string]string{"TLS_KEY_FILE": "key.pem"},
+			args:    []string{"-tls-port", ":8443"},
+			want: func(cfg *Config) {
+				cfg.TLS.Enabled = true
+				cfg.TLS.Port = ":8443"
+				cfg.TLS.CertFile = "cert.pem"
+				cfg.TLS.KeyFile = "key.pem"
+			},
+		},
+		"UnknownYAMLSetting": {
+			file:    "config.yaml",
+			content: "http:\n  prot: \":4000\"\n",
+			wantErr: "field prot not found",
+		},
+		"UnknownTOMLSetting": {
+			file:    "config.toml",
+			content: "[http]\nprot = \":4000\"\n",
+			wantErr: `unknown setting "http.prot"`,
+		},
+		"UnsupportedFormat": {
+			file:    "config.json",
+			content: "{}",
+			wantErr: `unsupported format ".json"`,
+		},
+		"InvalidEnv": {
+			env:     map[string]string{"HTTP_REQUEST_TIMEOUT": "soon"},
+			wantErr: "HTTP_REQUEST_TIMEOUT",
+		},
+		"InvalidPort": {
+			args:    []string{"-port", "3000"},
+			wantErr: `http port "3000"`,
+		},
+		"SamePorts": {
+			args:    []string{"-grpc-port", ":3000"},
+			wantErr: "http and grpc port",
+		},
+		"MetricsPort": {
+			env: map[string]string{"METRICS_PORT": ":9100"},
+			want: func(cfg *Config) {
+				cfg.Metrics.Port = ":9100"
+			},
+		},
+		"MetricsDisabled": {
+			args: []string{"-metrics-port", ""},
+			want: func(cfg *Config) {
+				cfg.Metrics.Port = ""
+			},
+		},
+		"MetricsPortInUse": {
+			args:    []string{"-metrics-port", ":3000"},
+			wantErr: `metrics port ":3000"`,
+		},
+		"InvalidCompressionLevel": {
+			env:     map[string]string{"HTTP_COMPRESSION_LEVEL": "10"},
+			wantErr: "http compression level 10",
+		},
+		"InvalidTimeout": {
+			args:    []string{"-request-timeout", "0s"},
+			wantErr: "http request timeout 0s",
+		},
+		"TLSPortInUse": {
+			args:    []string{"-tls", "-tls-port", ":3001"},
+			wantErr: `tls port ":3001"`,
+		},
+		"TLSCertWithoutKey": {
+			args:    []string{"-tls", "-tls-cert", "cert.pem"},
+			wantErr: "set both or neither",
+		},
+		"TLSDisabledIsNotValidated": {
+			args: []string{"-tls-cert", "cert.pem"},
+			want: func(cfg *Config) {
+				cfg.TLS.CertFile = "cert.pem"
+			},
+		},
+		"InvalidLogConfig": {
+			args:    []string{"-log-format", "xml"},
+			wantErr: `log format "xml"`,
+		},
+		"Security": {
+			file:    "config.toml",
+			content: "[security]\ncsp = \"default-src 'self'\"\n",
+			env:     map[string]string{"SECURITY_REPORT_ONLY": "true"},
+			args:    []string{"-hsts-max-age", "0s"},
+			want: func(cfg *Config) {
+				cfg.Security.CSP = "default-src 'self'"
+				cfg.Security.ReportOnly = true
+				cfg.Security.HSTSMaxAge = 0
+			},
+		},
+		"InvalidSecurityConfig": {
+			env:     map[string]string{"SECURITY_EMBEDDER_POLICY": "require-cors"},
+			wantErr: `embedder policy "require-cors"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			args := tt.args
+			env := make(map[string]string)
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			if tt.file != "" {
+				name := filepath.Join(dir, tt.file)
+				if err := os.WriteFile(name, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := env[FileEnv]; ok {
+					env[FileEnv] = name
+				} else {
+					args = append([]string{"-config", name}, args...)
+				}
+			}
+			lookup := func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			}
+			fs := flag.NewFlagSet("server", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+
+			got, err := Load(fs, args, lookup)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			want := Default()
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Load() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestConfig_Write(t *testing.T) {
+	var b strings.Builder
+	if err := Default().Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(name, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the printed configuration can be loaded as it is
+	var got Config
+	if err := got.LoadFile(name); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if want := Default(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadFile() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+)
+
+// MediaTypeCBOR is the media type of the compact binary wire format
+const MediaTypeCBOR = "application/cbor"
+
+// cborTodo is the wire form of a todo: a five element array holding the raw
+// 16 byte id and times as Unix nanoseconds, which keeps their full precision
+type cborTodo struct {
+	_           struct{} `cbor:",toarray"`
+	ID          uuid.UUID
+	Description string
+	Completed   bool
+	CreatedAt   int64
+	DueAt       *int64
+}
+
+// MarshalCBOR encodes the todo as a compact array rather than a map of field names
+func (t Todo) MarshalCBOR() ([]byte, error) {
+	wire := cborTodo{
+		ID:          t.ID,
+		Description: t.Description,
+		Completed:   t.Completed,
+		CreatedAt:   t.CreatedAt.UnixNano(),
+	}
+	if t.DueAt != nil {
+		dueAt := t.DueAt.UnixNano()
+		wire.DueAt = &dueAt
+	}
+	return cbor.Marshal(wire)
+}
+
+// UnmarshalCBOR decodes a todo written by MarshalCBOR
+func (t *Todo) UnmarshalCBOR(data []byte) error {
+	var wire cborTodo
+	if err := cbor.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*t = Todo{
+		ID:          wire.ID,
+		Description: wire.Description,
+		Completed:   wire.Completed,
+		CreatedAt:   time.Unix(0, wire.CreatedAt).UTC(),
+	}
+	if wire.DueAt != nil {
+		dueAt := time.Unix(0, *wire.DueAt).UTC()
+		t.DueAt = &dueAt
+	}
+	return nil
+}
+
+// cborCodec writes the list as a CBOR array of compact todos
+type cborCodec struct{}
+
+func (cborCodec) ContentType() string {
+	return MediaTypeCBOR
+}
+
+func (cborCodec) Extension() string {
+	return FormatCBOR
+}
+
+func (cborCodec) Encode(w io.Writer, todos []*Todo) error {
+	if todos == nil {
+		todos = []*Todo{}
+	}
+	if err := cbor.NewEncoder(w).Encode(todos); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (cborCodec) Decode(r io.Reader) ([]*Todo, error) {
+	var todos []*Todo
+	if err := cbor.NewDecoder(r).Decode(&todos); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	for _, todo := range todos {
+		if todo == nil {
+			return nil, ErrUnmarshaling{Err: errors.New("null todo in list")}
+		}
+		fillMissing(todo)
+	}
+	return todos, nil
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FormatJSON      = "json"
+	FormatCSV       = "csv"
+	FormatTodoTxt   = "todotxt"
+	FormatMarkdown  = "markdown"
+	FormatICalendar = "ics"
+	FormatCBOR      = "cbor"
+)
+
+// TodoCodec encodes and decodes a list of todos in a single file format
+type TodoCodec interface {
+	// ContentType returns the media type of the encoded list
+	ContentType() string
+	// Extension returns the file extension, without the dot, used for the format
+	Extension() string
+	// Encode writes the list of todos to w
+	Encode(w io.Writer, todos []*Todo) error
+	// Decode reads a list of todos from r
+	Decode(r io.Reader) ([]*Todo, error)
+}
+
+// codecs are registered under their format name and their file extension
+var codecs = map[string]TodoCodec{
+	FormatJSON:      jsonCodec{},
+	FormatCSV:       csvCodec{},
+	FormatTodoTxt:   todoTxtCodec{},
+	"txt":           todoTxtCodec{},
+	FormatMarkdown:  markdownCodec{},
+	"md":            markdownCodec{},
+	FormatICalendar: icalCodec{},
+	FormatCBOR:      cborCodec{},
+}
+
+// CodecFor returns the codec for the named format
+func CodecFor(format string) (TodoCodec, error) {
+	codec, exists := codecs[strings.ToLower(format)]
+	if !exists {
+		return nil, ErrUnsupportedFormat{Format: format}
+	}
+	return codec, nil
+}
+
+// CodecForMediaType returns the codec producing the media type, ignoring any parameters
+func CodecForMediaType(mediaType string) (TodoCodec, error) {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, format := range []string{FormatJSON, FormatCSV, FormatMarkdown, FormatTodoTxt, FormatICalendar, FormatCBOR} {
+		codec := codecs[format]
+		if contentType, _, _ := strings.Cut(codec.ContentType(), ";"); contentType == mediaType {
+			return codec, nil
+		}
+	}
+	return nil, ErrUnsupportedFormat{Format: mediaType}
+}
+
+// jsonCodec uses the same representation as the REST API
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Extension() string {
+	return FormatJSON
+}
+
+func (jsonCodec) Encode(w io.Writer, todos []*Todo) error {
+	if todos == nil {
+		todos = []*Todo{}
+	}
+	if err := json.NewEncoder(w).Encode(todos); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (jsonCodec) Decode(r io.Reader) ([]*Todo, error) {
+	var todos []*Todo
+	if err := json.NewDecoder(r).Decode(&todos); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	for _, todo := range todos {
+		if todo == nil {
+			return nil, ErrUnmarshaling{Err: errors.New("null todo in list")}
+		}
+		fillMissing(todo)
+	}
+	return todos, nil
+}
+
+var csvHeader = []string{"id", "description", "completed", "created_at", "due_at"}
+
+// csvCodec writes one todo per row beneath a header row
+type csvCodec struct{}
+
+func (csvCodec) ContentType() string {
+	return "text/csv"
+}
+
+func (csvCodec) Extension() string {
+	return FormatCSV
+}
+
+func (csvCodec) Encode(w io.Writer, todos []*Todo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	for _, todo := range todos {
+		var dueAt string
+		if todo.DueAt != nil {
+			dueAt = todo.DueAt.Format(time.RFC3339Nano)
+		}
+		if err := cw.Write([]string{
+			todo.ID.String(),
+			todo.Description,
+			strconv.FormatBool(todo.Completed),
+			todo.CreatedAt.Format(time.RFC3339Nano),
+			dueAt,
+		}); err != nil {
+			return ErrMarshaling{Err: err}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (csvCodec) Decode(r io.Reader) ([]*Todo, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return []*Todo{}, nil
+	}
+	if err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// spreadsheets may prefix the first column with a byte order mark
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, exists := columns["description"]; !exists {
+		return nil, ErrUnmarshaling{Err: errors.New("missing description column")}
+	}
+
+	todos := make([]*Todo, 0)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrUnmarshaling{Err: err}
+		}
+		field := func(name string) string {
+			if i, exists := columns[name]; exists && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		todo := &Todo{Description: field("description")}
+		if id := field("id"); id != "" {
+			if todo.ID, err = uuid.Parse(id); err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+		}
+		if completed := field("completed"); completed != "" {
+			if todo.Completed, err = strconv.ParseBool(completed); err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+		}
+		if createdAt := field("created_at"); createdAt != "" {
+			if todo.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+		}
+		if dueAt := field("due_at"); dueAt != "" {
+			due, err := time.Parse(time.RFC3339Nano, dueAt)
+			if err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+			todo.DueAt = &due
+		}
+		fillMissing(todo)
+		todos = append(todos, todo)
+	}
+	return todos, nil
+}
+
+// fillMissing gives imported todos without an id or creation time new ones
+func fillMissing(todo *Todo) {
+	if todo.ID == uuid.Nil {
+		todo.ID = uuid.New()
+	}
+	if todo.CreatedAt.IsZero() {
+		todo.CreatedAt = time.Now()
+	}
+}
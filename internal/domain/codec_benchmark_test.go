@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func benchmarkTodos(n int) []*Todo {
+	createdAt := time.Date(2023, 6, 1, 14, 30, 15, 123456789, time.UTC)
+	todos := make([]*Todo, n)
+	for i := range todos {
+		todos[i] = &Todo{
+			ID:          uuid.New(),
+			Description: "Take out the trash " + strconv.Itoa(i),
+			Completed:   i%3 == 0,
+			CreatedAt:   createdAt.Add(time.Duration(i) * time.Minute),
+		}
+		if i%4 == 0 {
+			dueAt := createdAt.Add(time.Duration(i) * time.Hour)
+			todos[i].DueAt = &dueAt
+		}
+	}
+	return todos
+}
+
+// BenchmarkWireFormats compares the JSON and CBOR encodings of the todo list;
+// the payload-bytes metric is the size of the encoded list
+func BenchmarkWireFormats(b *testing.B) {
+	for _, size := range []int{10, 1_000, 100_000} {
+		todos := benchmarkTodos(size)
+		for _, format := range []string{FormatJSON, FormatCBOR} {
+			codec, _ := CodecFor(format)
+			var encoded bytes.Buffer
+			if err := codec.Encode(&encoded, todos); err != nil {
+				b.Fatalf("Encode() error = %v", err)
+			}
+
+			b.Run(fmt.Sprintf("Encode/%s/%d", format, size), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(encoded.Len()))
+				for i := 0; i < b.N; i++ {
+					if err := codec.Encode(io.Discard, todos); err != nil {
+						b.Fatalf("Encode() error = %v", err)
+					}
+				}
+				b.ReportMetric(float64(encoded.Len()), "payload-bytes")
+			})
+			b.Run(fmt.Sprintf("Decode/%s/%d", format, size), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(encoded.Len()))
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.Decode(bytes.NewReader(encoded.Bytes())); err != nil {
+						b.Fatalf("Decode() error = %v", err)
+					}
+				}
+				b.ReportMetric(float64(encoded.Len()), "payload-bytes")
+			})
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testTodos() []*Todo {
+	createdAt := time.Date(2023, 6, 1, 14, 30, 15, 123456789, time.UTC)
+	dueAt := time.Date(2023, 6, 9, 0, 0, 0, 0, time.UTC)
+	dueTime := time.Date(2023, 6, 9, 17, 45, 30, 500, time.UTC)
+	return []*Todo{
+		{ID: uuid.New(), Description: "Bake a cake", Completed: false, CreatedAt: createdAt},
+		{ID: uuid.New(), Description: `Feed "the" cat, twice`, Completed: true, CreatedAt: createdAt.Add(time.Hour)},
+		{ID: uuid.New(), Description: "Take out the trash", Completed: false, CreatedAt: createdAt.Add(2 * time.Hour), DueAt: &dueAt},
+		{ID: uuid.New(), Description: "Call mom @phone +family pri:A", Completed: false, CreatedAt: createdAt.Add(48 * time.Hour)},
+		{ID: uuid.New(), Description: "x marks the spot", Completed: false, CreatedAt: createdAt.Add(72 * time.Hour)},
+		{ID: uuid.New(), Description: "- [x] see https://example.com", Completed: true, CreatedAt: createdAt.Add(96 * time.Hour)},
+		{ID: uuid.New(), Description: "Catch the train", Completed: false, CreatedAt: createdAt.Add(120 * time.Hour), DueAt: &dueTime},
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		format string
+	}{
+		"JSON":      {format: FormatJSON},
+		"CSV":       {format: FormatCSV},
+		"TodoTxt":   {format: FormatTodoTxt},
+		"Markdown":  {format: FormatMarkdown},
+		"ICalendar": {format: FormatICalendar},
+		"CBOR":      {format: FormatCBOR},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			codec, err := CodecFor(tt.format)
+			if err != nil {
+				t.Fatalf("CodecFor() error = %v", err)
+			}
+			want := testTodos()
+
+			var buf bytes.Buffer
+			if err := codec.Encode(&buf, want); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			got, err := codec.Decode(&buf)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("Decode() got %d todos, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !sameTodo(got[i], want[i]) {
+					t.Errorf("Decode() todo %d = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	tests := map[string]struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		"JSON":        {format: "json", want: "application/json"},
+		"CSV":         {format: "CSV", want: "text/csv"},
+		"TodoTxt":     {format: "txt", want: "text/plain; charset=utf-8"},
+		"Markdown":    {format: "md", want: "text/markdown; charset=utf-8"},
+		"ICalendar":   {format: "ICS", want: "text/calendar; charset=utf-8"},
+		"Unsupported": {format: "xml", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := CodecFor(tt.format)
+			if tt.wantErr {
+				var formatErr ErrUnsupportedFormat
+				if !errors.As(err, &formatErr) {
+					t.Errorf("CodecFor() error = %v, want ErrUnsupportedFormat", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CodecFor() error = %v", err)
+			}
+			if got.ContentType() != tt.want {
+				t.Errorf("CodecFor() ContentType = %v, want %v", got.ContentType(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVCodec_Decode(t *testing.T) {
+	id := uuid.New()
+	tests := map[string]struct {
+		input   string
+		want    []*Todo
+		wantErr bool
+	}{
+		"DescriptionOnly": {
+			input: "description\nBake a cake\n",
+			want:  []*Todo{{Description: "Bake a cake"}},
+		},
+		"ReorderedColumns": {
+			input: "\ufeffCompleted,Description,ID\ntrue,Bake a cake," + id.String() + "\n",
+			want:  []*Todo{{ID: id, Description: "Bake a cake", Completed: true}},
+		},
+		"Empty": {
+			input: "",
+			want:  []*Todo{},
+		},
+		"MissingDescription": {
+			input:   "id,completed\n" + id.String() + ",true\n",
+			wantErr: true,
+		},
+		"InvalidID": {
+			input:   "id,description\nnope,Bake a cake\n",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := csvCodec{}.Decode(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Decode() got %d todos, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if want.ID != uuid.Nil && got[i].ID != want.ID {
+					t.Errorf("Decode() ID = %v, want %v", got[i].ID, want.ID)
+				}
+				if got[i].ID == uuid.Nil || got[i].CreatedAt.IsZero() {
+					t.Errorf("Decode() did not fill in missing fields: %v", got[i])
+				}
+				if got[i].Description != want.Description || got[i].Completed != want.Completed {
+					t.Errorf("Decode() todo = %v, want %v", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCodecForMediaType(t *testing.T) {
+	tests := map[string]struct {
+		mediaType string
+		want      string
+		wantErr   bool
+	}{
+		"Markdown":    {mediaType: "text/markdown", want: FormatMarkdown},
+		"Parameters":  {mediaType: "Text/CSV; charset=utf-8", want: FormatCSV},
+		"ICalendar":   {mediaType: "text/calendar", want: FormatICalendar},
+		"Unsupported": {mediaType: "application/xml", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := CodecForMediaType(tt.mediaType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CodecForMediaType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want, _ := CodecFor(tt.want); got != want {
+				t.Errorf("CodecForMediaType() = %T, want %T", got, want)
+			}
+		})
+	}
+}
+
+func TestICalCodec_Decode(t *testing.T) {
+	id := uuid.New()
+	tests := map[string]struct {
+		input   string
+		want    Todo
+		wantDue string
+	}{
+		"FoldedAndEscaped": {
+			input: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:" + id.String() + "\r\nSUMMARY:Buy milk\\, eggs\r\n  and bread\\; soon\r\n" +
+				"STATUS:COMPLETED\r\nCREATED:20230601T143015Z\r\nDUE;VALUE=DATE:20230609\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			want: Todo{
+				ID:          id,
+				Description: "Buy milk, eggs and bread; soon",
+				Completed:   true,
+				CreatedAt:   time.Date(2023, 6, 1, 14, 30, 15, 0, time.UTC),
+			},
+			wantDue: "2023-06-09T00:00:00Z",
+		},
+		"ForeignUID": {
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Not a todo\nEND:VEVENT\nBEGIN:VTODO\nUID:task-1@example.com\n" +
+				"SUMMARY:Water plants\nCREATED;TZID=America/New_York:20230601T100000\nEND:VTODO\nEND:VCALENDAR\n",
+			want: Todo{
+				ID:          uuid.NewSHA1(uuid.NameSpaceURL, []byte("task-1@example.com")),
+				Description: "Water plants",
+				CreatedAt:   time.Date(2023, 6, 1, 14, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := icalCodec{}.Decode(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("Decode() got %d todos, want 1", len(got))
+			}
+			if tt.wantDue != "" {
+				due, _ := time.Parse(time.RFC3339, tt.wantDue)
+				tt.want.DueAt = &due
+			}
+			if !sameTodo(got[0], &tt.want) {
+				t.Errorf("Decode() todo = %v, want %v", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestICalCodec_Encode_Revision(t *testing.T) {
+	createdAt := time.Date(2023, 6, 1, 14, 30, 15, 0, time.UTC)
+	todo := &Todo{ID: uuid.New(), Description: "Bake a cake", CreatedAt: createdAt}
+	todo.Update(true, "Bake a cake")
+	todo.UpdatedAt = createdAt.Add(time.Hour)
+
+	var buf bytes.Buffer
+	if err := (icalCodec{}).Encode(&buf, []*Todo{todo}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	for _, want := range []string{
+		"DTSTAMP:20230601T153015Z\r\n",
+		"CREATED:20230601T143015Z\r\n",
+		"LAST-MODIFIED:20230601T153015Z\r\n",
+		"SEQUENCE:1\r\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Encode() = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 100)
+	folded := foldICalLine(line)
+	for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > icalLineOctets {
+			t.Errorf("foldICalLine() line has %d octets, want at most %d", len(l), icalLineOctets)
+		}
+	}
+	lines, err := unfoldICalLines(strings.NewReader(folded))
+	if err != nil || len(lines) != 1 || lines[0] != line {
+		t.Errorf("unfoldICalLines() = %q, %v; want %q", lines, err, line)
+	}
+}
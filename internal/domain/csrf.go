@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+const (
+	// CSRFHeader carries the CSRF token of the session with requests that change
+	// something; the server also sets it to "invalid" on the requests it refuses
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFField carries the CSRF token in forms posted without htmx
+	CSRFField = "csrf_token"
+)
+
+type csrfKey struct{}
+
+// CSRFToken returns the CSRF token of the session with the token; it is derived from
+// the token, so pages of other sites, which can neither read the session cookie nor
+// responses from the server, cannot know it
+func CSRFToken(sessionToken string) string {
+	mac := hmac.New(sha256.New, []byte(sessionToken))
+	mac.Write([]byte("csrf"))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidCSRFToken reports whether the CSRF token belongs to the session with the token
+func ValidCSRFToken(sessionToken, csrfToken string) bool {
+	return csrfToken != "" && subtle.ConstantTimeCompare([]byte(CSRFToken(sessionToken)), []byte(csrfToken)) == 1
+}
+
+// ContextWithCSRFToken returns a context whose requests to the server carry the token
+func ContextWithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfKey{}, token)
+}
+
+// CSRFTokenFromContext returns the CSRF token of the request, or an empty string
+func CSRFTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey{}).(string)
+	return token
+}
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
 type ErrMarshaling struct {
 	Err error
 }
@@ -8,6 +14,10 @@ func (e ErrMarshaling) Error() string {
 	return "failed to marshal request: " + e.Err.Error()
 }
 
+func (e ErrMarshaling) Unwrap() error {
+	return e.Err
+}
+
 type ErrUnmarshaling struct {
 	Err error
 }
@@ -16,6 +26,10 @@ func (e ErrUnmarshaling) Error() string {
 	return "failed to unmarshal response: " + e.Err.Error()
 }
 
+func (e ErrUnmarshaling) Unwrap() error {
+	return e.Err
+}
+
 type ErrCreateRequest struct {
 	Err error
 }
@@ -24,6 +38,10 @@ func (e ErrCreateRequest) Error() string {
 	return "failed to create request: " + e.Err.Error()
 }
 
+func (e ErrCreateRequest) Unwrap() error {
+	return e.Err
+}
+
 type ErrMakeRequest struct {
 	Err error
 }
@@ -31,3 +49,112 @@ type ErrMakeRequest struct {
 func (e ErrMakeRequest) Error() string {
 	return "failed to make request: " + e.Err.Error()
 }
+
+func (e ErrMakeRequest) Unwrap() error {
+	return e.Err
+}
+
+type ErrResponse struct {
+	StatusCode int
+	Message    string
+}
+
+func (e ErrResponse) Error() string {
+	return "unexpected response status " + strconv.Itoa(e.StatusCode) + ": " + e.Message
+}
+
+// ErrValidation maps each invalid field to the reason it was rejected
+type ErrValidation struct {
+	Fields map[string]string `json:"fields"`
+}
+
+func (e ErrValidation) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	b.WriteString("validation failed")
+	for i, field := range fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(field + " " + e.Fields[field])
+	}
+	return b.String()
+}
+
+// Field returns the reason the named field was rejected or an empty string
+func (e ErrValidation) Field(name string) string {
+	return e.Fields[name]
+}
+
+type ErrUnsupportedFormat struct {
+	Format string
+}
+
+func (e ErrUnsupportedFormat) Error() string {
+	return "unsupported format: " + strconv.Quote(e.Format)
+}
+
+// ErrUnauthenticated is returned when a request carries no valid session
+type ErrUnauthenticated struct{}
+
+func (e ErrUnauthenticated) Error() string {
+	return "not signed in"
+}
+
+// ErrInvalidCredentials is returned when a username and password do not match a user
+type ErrInvalidCredentials struct{}
+
+func (e ErrInvalidCredentials) Error() string {
+	return "incorrect username or password"
+}
+
+// ErrInvalidCSRFToken is returned when a request made with a session lacks the CSRF
+// token of the session
+type ErrInvalidCSRFToken struct{}
+
+func (e ErrInvalidCSRFToken) Error() string {
+	return "missing or invalid CSRF token"
+}
+
+// ErrIncompatibleAPI is returned when the server does not speak a compatible version
+// of the API the client speaks
+type ErrIncompatibleAPI struct {
+	API string
+}
+
+func (e ErrIncompatibleAPI) Error() string {
+	return "the server speaks version " + e.API + " of the API, which is incompatible with version " + APIVersion
+}
+
+// ErrIdempotencyKeyReused is returned when an Idempotency-Key was already used for a
+// different request
+type ErrIdempotencyKeyReused struct{}
+
+func (e ErrIdempotencyKeyReused) Error() string {
+	return "the " + IdempotencyKeyHeader + " was used for a different request"
+}
+
+// ErrInsufficientScope is returned when an API token lacks the scope a request needs
+type ErrInsufficientScope struct {
+	Scope Scope
+}
+
+func (e ErrInsufficientScope) Error() string {
+	return "token lacks the " + string(e.Scope) + " scope"
+}
+
+// ErrPermissionDenied is returned when the user's role in a list does not allow a request
+type ErrPermissionDenied struct {
+	Permission Permission `json:"permission"`
+}
+
+func (e ErrPermissionDenied) Error() string {
+	return "not allowed to " + string(e.Permission) + " this list"
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	icalDateTime   = "20060102T150405Z"
+	icalLocalTime  = "20060102T150405"
+	icalDate       = "20060102"
+	icalLineOctets = 75
+	icalProductID  = "-//stackus//todos-htmx-wasm//EN"
+	icalStatusDone = "COMPLETED"
+	icalStatusTodo = "NEEDS-ACTION"
+	icalVTodo      = "VTODO"
+	// icalExactCreated and icalExactDue keep the times at the precision of the todo,
+	// as iCalendar times have whole seconds
+	icalExactCreated = "X-TODOS-CREATED"
+	icalExactDue     = "X-TODOS-DUE"
+)
+
+// icalCodec writes an iCalendar (RFC 5545) feed with one VTODO per todo
+//
+// DTSTAMP and LAST-MODIFIED are the time each todo last changed and SEQUENCE counts
+// its changes, so calendar clients pick up edits while the feed stays byte-for-byte
+// stable as long as the list is unchanged.
+type icalCodec struct{}
+
+func (icalCodec) ContentType() string {
+	return "text/calendar; charset=utf-8"
+}
+
+func (icalCodec) Extension() string {
+	return FormatICalendar
+}
+
+func (icalCodec) Encode(w io.Writer, todos []*Todo) error {
+	bw := bufio.NewWriter(w)
+	write := func(line string) {
+		_, _ = bw.WriteString(foldICalLine(line))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + icalProductID)
+	for _, todo := range todos {
+		status := icalStatusTodo
+		if todo.Completed {
+			status = icalStatusDone
+		}
+		modified := todo.ModifiedAt().UTC().Format(icalDateTime)
+		write("BEGIN:" + icalVTodo)
+		write("UID:" + todo.ID.String())
+		write("DTSTAMP:" + modified)
+		write("CREATED:" + todo.CreatedAt.UTC().Format(icalDateTime))
+		write("LAST-MODIFIED:" + modified)
+		write("SEQUENCE:" + strconv.Itoa(todo.Revision))
+		write("SUMMARY:" + escapeICalText(todo.Description))
+		write("STATUS:" + status)
+		write(icalExactCreated + ":" + todo.CreatedAt.Format(time.RFC3339Nano))
+		if todo.DueAt != nil {
+			write("DUE:" + todo.DueAt.UTC().Format(icalDateTime))
+			write(icalExactDue + ":" + todo.DueAt.Format(time.RFC3339Nano))
+		}
+		write("END:" + icalVTodo)
+	}
+	write("END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (icalCodec) Decode(r io.Reader) ([]*Todo, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	todos := make([]*Todo, 0)
+	var todo *Todo
+	// the exact times win over the standard properties, whichever comes first
+	var exactCreated, exactDue bool
+	for _, line := range lines {
+		name, params, value := parseICalLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, icalVTodo):
+			todo = &Todo{}
+			exactCreated, exactDue = false, false
+		case todo == nil:
+			// properties of the calendar and of other components are skipped
+		case name == "END" && strings.EqualFold(value, icalVTodo):
+			fillMissing(todo)
+			todos = append(todos, todo)
+			todo = nil
+		case name == "UID":
+			// UIDs written by other applications are mapped to a stable uuid
+			if todo.ID, err = uuid.Parse(value); err != nil {
+				todo.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(value))
+			}
+		case name == "SUMMARY":
+			todo.Description = unescapeICalText(value)
+		case name == "STATUS":
+			todo.Completed = strings.EqualFold(value, icalStatusDone)
+		case name == "COMPLETED":
+			todo.Completed = true
+		case name == "CREATED" && !exactCreated:
+			if todo.CreatedAt, err = parseICalTime(value, params); err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+		case name == "DUE" && !exactDue:
+			due, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, ErrUnmarshaling{Err: err}
+			}
+			todo.DueAt = &due
+		case name == icalExactCreated:
+			if created, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				todo.CreatedAt, exactCreated = created, true
+			}
+		case name == icalExactDue:
+			if due, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				todo.DueAt, exactDue = &due, true
+			}
+		}
+	}
+	return todos, nil
+}
+
+// foldICalLine terminates the content line with CRLF and folds it so that no
+// line is longer than 75 octets, without splitting a UTF-8 sequence
+func foldICalLine(line string) string {
+	var sb strings.Builder
+	limit := icalLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = icalLineOctets - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+	return sb.String()
+}
+
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseICalLine splits a content line into its upper-cased name, its parameters and its value
+func parseICalLine(line string) (string, map[string]string, string) {
+	var quoted bool
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		if key, value, found := strings.Cut(param, "="); found {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseICalTime parses DATE and DATE-TIME values; floating times and unknown
+// time zones are read as UTC
+func parseICalTime(value string, params map[string]string) (time.Time, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len(icalDate) {
+		return time.Parse(icalDate, value)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalDateTime, value)
+	}
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation(icalLocalTime, value, loc)
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
+
+func unescapeICalText(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
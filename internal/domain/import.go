@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportMode decides what happens to existing todos during an import
+type ImportMode string
+
+const (
+	// ImportMerge adds new todos and updates existing todos with matching ids
+	ImportMerge ImportMode = "merge"
+	// ImportReplace replaces the entire list with the imported todos
+	ImportReplace ImportMode = "replace"
+)
+
+// ParseImportMode parses an import mode, defaulting to ImportMerge
+func ParseImportMode(mode string) (ImportMode, error) {
+	switch ImportMode(mode) {
+	case "", ImportMerge:
+		return ImportMerge, nil
+	case ImportReplace:
+		return ImportReplace, nil
+	}
+	return "", ErrValidation{Fields: map[string]string{"mode": "must be merge or replace"}}
+}
+
+// ImportReport describes the changes an import made or, for a dry run, would make
+type ImportReport struct {
+	Mode      ImportMode
+	DryRun    bool
+	Added     []*Todo
+	Updated   []*Todo
+	Removed   []*Todo
+	Unchanged int
+}
+
+// PlanImport compares the imported todos with the existing list and returns
+// the report along with the list that results from applying the import
+func PlanImport(existing, imported []*Todo, mode ImportMode) (*ImportReport, []*Todo, error) {
+	report := &ImportReport{
+		Mode:    mode,
+		Added:   []*Todo{},
+		Updated: []*Todo{},
+		Removed: []*Todo{},
+	}
+
+	incoming := make(map[uuid.UUID]*Todo, len(imported))
+	for _, todo := range imported {
+		if _, exists := incoming[todo.ID]; exists {
+			return nil, nil, ErrValidation{Fields: map[string]string{"todos": "must not contain duplicate id " + todo.ID.String()}}
+		}
+		incoming[todo.ID] = todo
+	}
+
+	current := make(map[uuid.UUID]*Todo, len(existing))
+	for _, todo := range existing {
+		current[todo.ID] = todo
+		if _, exists := incoming[todo.ID]; !exists && mode == ImportReplace {
+			report.Removed = append(report.Removed, todo)
+		}
+	}
+
+	for _, todo := range imported {
+		switch previous, exists := current[todo.ID]; {
+		case !exists:
+			report.Added = append(report.Added, todo)
+		case !sameTodo(previous, todo):
+			report.Updated = append(report.Updated, todo)
+		default:
+			report.Unchanged++
+		}
+	}
+
+	now := time.Now()
+	var result []*Todo
+	switch mode {
+	case ImportReplace:
+		result = make([]*Todo, 0, len(imported))
+		for _, todo := range imported {
+			result = append(result, revised(current[todo.ID], todo, now))
+		}
+	default:
+		result = make([]*Todo, 0, len(existing)+len(report.Added))
+		for _, todo := range existing {
+			if updated, exists := incoming[todo.ID]; exists {
+				todo = revised(todo, updated, now)
+			}
+			result = append(result, todo)
+		}
+		for _, todo := range report.Added {
+			result = append(result, copyTodo(todo))
+		}
+	}
+
+	return report, result, nil
+}
+
+// sameTodo compares todos by value, treating equal instants in different locations as equal
+func sameTodo(a, b *Todo) bool {
+	return a.ID == b.ID &&
+		a.Description == b.Description &&
+		a.Completed == b.Completed &&
+		a.CreatedAt.Equal(b.CreatedAt) &&
+		sameDue(a.DueAt, b.DueAt)
+}
+
+func sameDue(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// revised copies the imported todo, carrying over the revision of the todo it takes the
+// place of and counting the import as a change when it differs
+func revised(previous, imported *Todo, now time.Time) *Todo {
+	todo := copyTodo(imported)
+	if previous == nil {
+		return todo
+	}
+	todo.UpdatedAt, todo.Revision = previous.UpdatedAt, previous.Revision
+	if !sameTodo(previous, imported) {
+		todo.UpdatedAt = now
+		todo.Revision++
+	}
+	return todo
+}
+
+func copyTodo(todo *Todo) *Todo {
+	c := *todo
+	return &c
+}
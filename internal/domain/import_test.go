@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPlanImport(t *testing.T) {
+	now := time.Now()
+	kept := &Todo{ID: uuid.New(), Description: "kept", CreatedAt: now}
+	changed := &Todo{ID: uuid.New(), Description: "changed", CreatedAt: now}
+	dropped := &Todo{ID: uuid.New(), Description: "dropped", CreatedAt: now}
+	existing := []*Todo{kept, changed, dropped}
+
+	changedUpdate := &Todo{ID: changed.ID, Description: "changed", Completed: true, CreatedAt: now.UTC()}
+	keptCopy := &Todo{ID: kept.ID, Description: "kept", CreatedAt: now.UTC()}
+	added := &Todo{ID: uuid.New(), Description: "added", CreatedAt: now}
+
+	type args struct {
+		imported []*Todo
+		mode     ImportMode
+	}
+	tests := map[string]struct {
+		args        args
+		wantAdded   int
+		wantUpdated int
+		wantRemoved int
+		wantSame    int
+		wantOrder   []uuid.UUID
+		// wantRevisions counts the changes of the resulting todos, in order
+		wantRevisions []int
+		wantErr       bool
+	}{
+		"Merge": {
+			args: args{
+				imported: []*Todo{added, changedUpdate, keptCopy},
+				mode:     ImportMerge,
+			},
+			wantAdded:     1,
+			wantUpdated:   1,
+			wantSame:      1,
+			wantOrder:     []uuid.UUID{kept.ID, changed.ID, dropped.ID, added.ID},
+			wantRevisions: []int{0, 1, 0, 0},
+		},
+		"Replace": {
+			args: args{
+				imported: []*Todo{added, changedUpdate, keptCopy},
+				mode:     ImportReplace,
+			},
+			wantAdded:     1,
+			wantUpdated:   1,
+			wantRemoved:   1,
+			wantSame:      1,
+			wantOrder:     []uuid.UUID{added.ID, changed.ID, kept.ID},
+			wantRevisions: []int{0, 1, 0},
+		},
+		"DuplicateIDs": {
+			args: args{
+				imported: []*Todo{added, added},
+				mode:     ImportMerge,
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			report, result, err := PlanImport(existing, tt.args.imported, tt.args.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(report.Added) != tt.wantAdded || len(report.Updated) != tt.wantUpdated ||
+				len(report.Removed) != tt.wantRemoved || report.Unchanged != tt.wantSame {
+				t.Errorf("PlanImport() report = %d added, %d updated, %d removed, %d unchanged; want %d, %d, %d, %d",
+					len(report.Added), len(report.Updated), len(report.Removed), report.Unchanged,
+					tt.wantAdded, tt.wantUpdated, tt.wantRemoved, tt.wantSame)
+			}
+			if len(result) != len(tt.wantOrder) {
+				t.Fatalf("PlanImport() result has %d todos, want %d", len(result), len(tt.wantOrder))
+			}
+			for i, id := range tt.wantOrder {
+				if result[i].ID != id {
+					t.Errorf("PlanImport() result[%d] = %v, want %v", i, result[i].ID, id)
+				}
+				if result[i].Revision != tt.wantRevisions[i] {
+					t.Errorf("PlanImport() result[%d] Revision = %v, want %v", i, result[i].Revision, tt.wantRevisions[i])
+				}
+			}
+			if changed.Completed {
+				t.Errorf("PlanImport() modified the existing list")
+			}
+		})
+	}
+}
+
+func TestTodos_Import(t *testing.T) {
+	list := NewTodos()
+	_, _ = list.Add(context.Background(), "first")
+	imported := []*Todo{NewTodo("second")}
+	original := *imported[0]
+
+	report, err := list.Import(ContextWithUser(context.Background(), &User{ID: uuid.New()}), imported, ImportMerge, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if *imported[0] != original {
+		t.Errorf("Import() dry run changed the imported todo to %v, want %v", *imported[0], original)
+	}
+
+	report, err = list.Import(context.Background(), imported, ImportMerge, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got, _ := list.Scan(context.Background()); !report.DryRun || len(report.Added) != 1 || len(got) != 1 {
+		t.Errorf("Import() dry run changed the list or reported %v", report)
+	}
+
+	if _, err = list.Import(context.Background(), imported, ImportMerge, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got, _ := list.Scan(context.Background()); len(got) != 2 || got[1].ID != imported[0].ID {
+		t.Errorf("Import() list = %v, want the imported todo appended", got)
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ListHeader names the request header that selects the list a request acts on;
+// without it requests act on the user's own list
+const ListHeader = "X-Todo-List"
+
+// Roles a user can be given in a list that is shared with them
+const (
+	// RoleViewer may read the todos of the list
+	RoleViewer Role = "viewer"
+	// RoleEditor may also add, change, remove, sort and import todos
+	RoleEditor Role = "editor"
+	// RoleOwner may also share the list with others; the user the list was created for
+	// is always its owner
+	RoleOwner Role = "owner"
+)
+
+// Permissions the roles grant; each role grants the permissions of the roles before it
+const (
+	PermissionView  Permission = "view"
+	PermissionEdit  Permission = "edit"
+	PermissionShare Permission = "share"
+)
+
+type (
+	// Role is what a user may do with a list
+	Role string
+
+	// Permission is something a role allows
+	Permission string
+
+	// Membership gives a user a role in a list
+	Membership struct {
+		ListID uuid.UUID
+		// ListName is the username of the user the list was created for
+		ListName string
+		UserID   uuid.UUID
+		Username string
+		Role     Role
+	}
+
+	listKey struct{}
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+var permissionRanks = map[Permission]int{
+	PermissionView:  1,
+	PermissionEdit:  2,
+	PermissionShare: 3,
+}
+
+// ParseRole returns the role with the name or an ErrValidation
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, known := roleRanks[role]; !known {
+		return "", fieldError(FieldRole, "must be "+string(RoleViewer)+", "+string(RoleEditor)+" or "+string(RoleOwner))
+	}
+	return role, nil
+}
+
+// Allows reports whether the role grants the permission; the empty role grants nothing
+func (r Role) Allows(permission Permission) bool {
+	rank, known := roleRanks[r]
+	return known && rank >= permissionRanks[permission]
+}
+
+// ContextWithList returns a context for requests that act on the list
+func ContextWithList(ctx context.Context, listID uuid.UUID) context.Context {
+	return context.WithValue(ctx, listKey{}, listID)
+}
+
+// ListFromContext returns the list selected for the request, if one was
+func ListFromContext(ctx context.Context) (uuid.UUID, bool) {
+	listID, ok := ctx.Value(listKey{}).(uuid.UUID)
+	return listID, ok
+}
+
+// RoleIn returns the role the user of the context has in the list of the context, or
+// the empty role when they have none. Users own the list created for them, and
+// requests without a user own the list shared by every such request.
+func RoleIn(ctx context.Context, members MemberRepository) (Role, error) {
+	user := UserFromContext(ctx)
+	selected, ok := ListFromContext(ctx)
+	switch {
+	case user == nil && ok:
+		return "", nil
+	case user == nil, !ok, selected == user.ID:
+		return RoleOwner, nil
+	}
+	membership, err := members.Get(ctx, selected, user.ID)
+	if err != nil || membership == nil {
+		return "", err
+	}
+	return membership.Role, nil
+}
+
+// listID returns the list the request acts on: the selected list, or the list of the
+// user making the request; requests without a user share the list uuid.Nil
+func listID(ctx context.Context) uuid.UUID {
+	if selected, ok := ListFromContext(ctx); ok {
+		return selected
+	}
+	if user := UserFromContext(ctx); user != nil {
+		return user.ID
+	}
+	return uuid.Nil
+}
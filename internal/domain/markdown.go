@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	markdownItem     = regexp.MustCompile(`^\s*[-*+] \[([ xX])\] (.*)$`)
+	markdownMetadata = regexp.MustCompile(`\s*<!--\s*(.*?)\s*-->\s*$`)
+)
+
+// markdownCodec writes a GitHub-style task list with one checklist item per todo
+//
+// The id, creation time and due time of each todo are kept in a trailing HTML comment,
+// which Markdown renderers do not display.
+type markdownCodec struct{}
+
+func (markdownCodec) ContentType() string {
+	return "text/markdown; charset=utf-8"
+}
+
+func (markdownCodec) Extension() string {
+	return "md"
+}
+
+func (markdownCodec) Encode(w io.Writer, todos []*Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, todo := range todos {
+		check := " "
+		if todo.Completed {
+			check = "x"
+		}
+		metadata := "id:" + todo.ID.String() + " created:" + todo.CreatedAt.Format(time.RFC3339Nano)
+		if todo.DueAt != nil {
+			metadata += " due:" + todo.DueAt.Format(time.RFC3339Nano)
+		}
+		line := "- [" + check + "] " + todo.Description + " <!-- " + metadata + " -->\n"
+		if _, err := bw.WriteString(line); err != nil {
+			return ErrMarshaling{Err: err}
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (markdownCodec) Decode(r io.Reader) ([]*Todo, error) {
+	todos := make([]*Todo, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// anything other than a checklist item, such as headings or prose, is skipped
+		item := markdownItem.FindStringSubmatch(scanner.Text())
+		if item == nil {
+			continue
+		}
+
+		todo := &Todo{
+			Description: item[2],
+			Completed:   item[1] != " ",
+		}
+		if loc := markdownMetadata.FindStringSubmatchIndex(todo.Description); loc != nil {
+			metadata := make(map[string]string)
+			for _, field := range strings.Fields(todo.Description[loc[2]:loc[3]]) {
+				if key, value, found := strings.Cut(field, ":"); found {
+					metadata[key] = value
+				}
+			}
+			var err error
+			var ours bool
+			if id, exists := metadata["id"]; exists {
+				ours = true
+				if todo.ID, err = uuid.Parse(id); err != nil {
+					return nil, ErrUnmarshaling{Err: err}
+				}
+			}
+			if created, exists := metadata["created"]; exists {
+				ours = true
+				if todo.CreatedAt, err = time.Parse(time.RFC3339Nano, created); err != nil {
+					return nil, ErrUnmarshaling{Err: err}
+				}
+			}
+			if due, exists := metadata["due"]; exists {
+				ours = true
+				dueAt, err := time.Parse(time.RFC3339Nano, due)
+				if err != nil {
+					return nil, ErrUnmarshaling{Err: err}
+				}
+				todo.DueAt = &dueAt
+			}
+			// comments that were not written by this codec are part of the description
+			if ours {
+				todo.Description = todo.Description[:loc[0]]
+			}
+		}
+		fillMissing(todo)
+		todos = append(todos, todo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	return todos, nil
+}
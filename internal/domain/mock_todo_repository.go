@@ -3,6 +3,8 @@
 package domain
 
 import (
+	context "context"
+
 	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -20,25 +22,25 @@ func (_m *MockTodoRepository) EXPECT() *MockTodoRepository_Expecter {
 	return &MockTodoRepository_Expecter{mock: &_m.Mock}
 }
 
-// Add provides a mock function with given fields: description
-func (_m *MockTodoRepository) Add(description string) (*Todo, error) {
-	ret := _m.Called(description)
+// Add provides a mock function with given fields: ctx, description
+func (_m *MockTodoRepository) Add(ctx context.Context, description string) (*Todo, error) {
+	ret := _m.Called(ctx, description)
 
 	var r0 *Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*Todo, error)); ok {
-		return rf(description)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*Todo, error)); ok {
+		return rf(ctx, description)
 	}
-	if rf, ok := ret.Get(0).(func(string) *Todo); ok {
-		r0 = rf(description)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *Todo); ok {
+		r0 = rf(ctx, description)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(description)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, description)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -52,14 +54,15 @@ type MockTodoRepository_Add_Call struct {
 }
 
 // Add is a helper method to define mock.On call
+//   - ctx context.Context
 //   - description string
-func (_e *MockTodoRepository_Expecter) Add(description interface{}) *MockTodoRepository_Add_Call {
-	return &MockTodoRepository_Add_Call{Call: _e.mock.On("Add", description)}
+func (_e *MockTodoRepository_Expecter) Add(ctx interface{}, description interface{}) *MockTodoRepository_Add_Call {
+	return &MockTodoRepository_Add_Call{Call: _e.mock.On("Add", ctx, description)}
 }
 
-func (_c *MockTodoRepository_Add_Call) Run(run func(description string)) *MockTodoRepository_Add_Call {
+func (_c *MockTodoRepository_Add_Call) Run(run func(ctx context.Context, description string)) *MockTodoRepository_Add_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -69,30 +72,30 @@ func (_c *MockTodoRepository_Add_Call) Return(_a0 *Todo, _a1 error) *MockTodoRep
 	return _c
 }
 
-func (_c *MockTodoRepository_Add_Call) RunAndReturn(run func(string) (*Todo, error)) *MockTodoRepository_Add_Call {
+func (_c *MockTodoRepository_Add_Call) RunAndReturn(run func(context.Context, string) (*Todo, error)) *MockTodoRepository_Add_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// All provides a mock function with given fields:
-func (_m *MockTodoRepository) All() ([]*Todo, error) {
-	ret := _m.Called()
+// All provides a mock function with given fields: ctx
+func (_m *MockTodoRepository) All(ctx context.Context) ([]*Todo, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []*Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*Todo, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*Todo, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []*Todo); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*Todo); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,13 +109,14 @@ type MockTodoRepository_All_Call struct {
 }
 
 // All is a helper method to define mock.On call
-func (_e *MockTodoRepository_Expecter) All() *MockTodoRepository_All_Call {
-	return &MockTodoRepository_All_Call{Call: _e.mock.On("All")}
+//   - ctx context.Context
+func (_e *MockTodoRepository_Expecter) All(ctx interface{}) *MockTodoRepository_All_Call {
+	return &MockTodoRepository_All_Call{Call: _e.mock.On("All", ctx)}
 }
 
-func (_c *MockTodoRepository_All_Call) Run(run func()) *MockTodoRepository_All_Call {
+func (_c *MockTodoRepository_All_Call) Run(run func(ctx context.Context)) *MockTodoRepository_All_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
@@ -122,30 +126,85 @@ func (_c *MockTodoRepository_All_Call) Return(_a0 []*Todo, _a1 error) *MockTodoR
 	return _c
 }
 
-func (_c *MockTodoRepository_All_Call) RunAndReturn(run func() ([]*Todo, error)) *MockTodoRepository_All_Call {
+func (_c *MockTodoRepository_All_Call) RunAndReturn(run func(context.Context) ([]*Todo, error)) *MockTodoRepository_All_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Export provides a mock function with given fields: ctx, format
+func (_m *MockTodoRepository) Export(ctx context.Context, format string) ([]byte, error) {
+	ret := _m.Called(ctx, format)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, format)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTodoRepository_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockTodoRepository_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - format string
+func (_e *MockTodoRepository_Expecter) Export(ctx interface{}, format interface{}) *MockTodoRepository_Export_Call {
+	return &MockTodoRepository_Export_Call{Call: _e.mock.On("Export", ctx, format)}
+}
+
+func (_c *MockTodoRepository_Export_Call) Run(run func(ctx context.Context, format string)) *MockTodoRepository_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTodoRepository_Export_Call) Return(_a0 []byte, _a1 error) *MockTodoRepository_Export_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTodoRepository_Export_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockTodoRepository_Export_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Get provides a mock function with given fields: id
-func (_m *MockTodoRepository) Get(id uuid.UUID) (*Todo, error) {
-	ret := _m.Called(id)
+// Get provides a mock function with given fields: ctx, id
+func (_m *MockTodoRepository) Get(ctx context.Context, id uuid.UUID) (*Todo, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uuid.UUID) (*Todo, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*Todo, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uuid.UUID) *Todo); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *Todo); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uuid.UUID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -159,14 +218,15 @@ type MockTodoRepository_Get_Call struct {
 }
 
 // Get is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockTodoRepository_Expecter) Get(id interface{}) *MockTodoRepository_Get_Call {
-	return &MockTodoRepository_Get_Call{Call: _e.mock.On("Get", id)}
+func (_e *MockTodoRepository_Expecter) Get(ctx interface{}, id interface{}) *MockTodoRepository_Get_Call {
+	return &MockTodoRepository_Get_Call{Call: _e.mock.On("Get", ctx, id)}
 }
 
-func (_c *MockTodoRepository_Get_Call) Run(run func(id uuid.UUID)) *MockTodoRepository_Get_Call {
+func (_c *MockTodoRepository_Get_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTodoRepository_Get_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
@@ -176,18 +236,75 @@ func (_c *MockTodoRepository_Get_Call) Return(_a0 *Todo, _a1 error) *MockTodoRep
 	return _c
 }
 
-func (_c *MockTodoRepository_Get_Call) RunAndReturn(run func(uuid.UUID) (*Todo, error)) *MockTodoRepository_Get_Call {
+func (_c *MockTodoRepository_Get_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*Todo, error)) *MockTodoRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function with given fields: ctx, todos, mode, dryRun
+func (_m *MockTodoRepository) Import(ctx context.Context, todos []*Todo, mode ImportMode, dryRun bool) (*ImportReport, error) {
+	ret := _m.Called(ctx, todos, mode, dryRun)
+
+	var r0 *ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*Todo, ImportMode, bool) (*ImportReport, error)); ok {
+		return rf(ctx, todos, mode, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*Todo, ImportMode, bool) *ImportReport); ok {
+		r0 = rf(ctx, todos, mode, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*Todo, ImportMode, bool) error); ok {
+		r1 = rf(ctx, todos, mode, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTodoRepository_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockTodoRepository_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - todos []*Todo
+//   - mode ImportMode
+//   - dryRun bool
+func (_e *MockTodoRepository_Expecter) Import(ctx interface{}, todos interface{}, mode interface{}, dryRun interface{}) *MockTodoRepository_Import_Call {
+	return &MockTodoRepository_Import_Call{Call: _e.mock.On("Import", ctx, todos, mode, dryRun)}
+}
+
+func (_c *MockTodoRepository_Import_Call) Run(run func(ctx context.Context, todos []*Todo, mode ImportMode, dryRun bool)) *MockTodoRepository_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*Todo), args[2].(ImportMode), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockTodoRepository_Import_Call) Return(_a0 *ImportReport, _a1 error) *MockTodoRepository_Import_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTodoRepository_Import_Call) RunAndReturn(run func(context.Context, []*Todo, ImportMode, bool) (*ImportReport, error)) *MockTodoRepository_Import_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Remove provides a mock function with given fields: id
-func (_m *MockTodoRepository) Remove(id uuid.UUID) error {
-	ret := _m.Called(id)
+// Remove provides a mock function with given fields: ctx, id
+func (_m *MockTodoRepository) Remove(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uuid.UUID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -201,14 +318,15 @@ type MockTodoRepository_Remove_Call struct {
 }
 
 // Remove is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockTodoRepository_Expecter) Remove(id interface{}) *MockTodoRepository_Remove_Call {
-	return &MockTodoRepository_Remove_Call{Call: _e.mock.On("Remove", id)}
+func (_e *MockTodoRepository_Expecter) Remove(ctx interface{}, id interface{}) *MockTodoRepository_Remove_Call {
+	return &MockTodoRepository_Remove_Call{Call: _e.mock.On("Remove", ctx, id)}
 }
 
-func (_c *MockTodoRepository_Remove_Call) Run(run func(id uuid.UUID)) *MockTodoRepository_Remove_Call {
+func (_c *MockTodoRepository_Remove_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTodoRepository_Remove_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
@@ -218,30 +336,30 @@ func (_c *MockTodoRepository_Remove_Call) Return(_a0 error) *MockTodoRepository_
 	return _c
 }
 
-func (_c *MockTodoRepository_Remove_Call) RunAndReturn(run func(uuid.UUID) error) *MockTodoRepository_Remove_Call {
+func (_c *MockTodoRepository_Remove_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockTodoRepository_Remove_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Reorder provides a mock function with given fields: ids
-func (_m *MockTodoRepository) Reorder(ids []uuid.UUID) ([]*Todo, error) {
-	ret := _m.Called(ids)
+// Reorder provides a mock function with given fields: ctx, ids
+func (_m *MockTodoRepository) Reorder(ctx context.Context, ids []uuid.UUID) ([]*Todo, error) {
+	ret := _m.Called(ctx, ids)
 
 	var r0 []*Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func([]uuid.UUID) ([]*Todo, error)); ok {
-		return rf(ids)
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) ([]*Todo, error)); ok {
+		return rf(ctx, ids)
 	}
-	if rf, ok := ret.Get(0).(func([]uuid.UUID) []*Todo); ok {
-		r0 = rf(ids)
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) []*Todo); ok {
+		r0 = rf(ctx, ids)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func([]uuid.UUID) error); ok {
-		r1 = rf(ids)
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ids)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -255,14 +373,15 @@ type MockTodoRepository_Reorder_Call struct {
 }
 
 // Reorder is a helper method to define mock.On call
+//   - ctx context.Context
 //   - ids []uuid.UUID
-func (_e *MockTodoRepository_Expecter) Reorder(ids interface{}) *MockTodoRepository_Reorder_Call {
-	return &MockTodoRepository_Reorder_Call{Call: _e.mock.On("Reorder", ids)}
+func (_e *MockTodoRepository_Expecter) Reorder(ctx interface{}, ids interface{}) *MockTodoRepository_Reorder_Call {
+	return &MockTodoRepository_Reorder_Call{Call: _e.mock.On("Reorder", ctx, ids)}
 }
 
-func (_c *MockTodoRepository_Reorder_Call) Run(run func(ids []uuid.UUID)) *MockTodoRepository_Reorder_Call {
+func (_c *MockTodoRepository_Reorder_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockTodoRepository_Reorder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].([]uuid.UUID))
+		run(args[0].(context.Context), args[1].([]uuid.UUID))
 	})
 	return _c
 }
@@ -272,30 +391,30 @@ func (_c *MockTodoRepository_Reorder_Call) Return(_a0 []*Todo, _a1 error) *MockT
 	return _c
 }
 
-func (_c *MockTodoRepository_Reorder_Call) RunAndReturn(run func([]uuid.UUID) ([]*Todo, error)) *MockTodoRepository_Reorder_Call {
+func (_c *MockTodoRepository_Reorder_Call) RunAndReturn(run func(context.Context, []uuid.UUID) ([]*Todo, error)) *MockTodoRepository_Reorder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Search provides a mock function with given fields: search
-func (_m *MockTodoRepository) Search(search string) ([]*Todo, error) {
-	ret := _m.Called(search)
+// Search provides a mock function with given fields: ctx, search
+func (_m *MockTodoRepository) Search(ctx context.Context, search string) ([]*Todo, error) {
+	ret := _m.Called(ctx, search)
 
 	var r0 []*Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]*Todo, error)); ok {
-		return rf(search)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*Todo, error)); ok {
+		return rf(ctx, search)
 	}
-	if rf, ok := ret.Get(0).(func(string) []*Todo); ok {
-		r0 = rf(search)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*Todo); ok {
+		r0 = rf(ctx, search)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(search)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, search)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -309,14 +428,15 @@ type MockTodoRepository_Search_Call struct {
 }
 
 // Search is a helper method to define mock.On call
+//   - ctx context.Context
 //   - search string
-func (_e *MockTodoRepository_Expecter) Search(search interface{}) *MockTodoRepository_Search_Call {
-	return &MockTodoRepository_Search_Call{Call: _e.mock.On("Search", search)}
+func (_e *MockTodoRepository_Expecter) Search(ctx interface{}, search interface{}) *MockTodoRepository_Search_Call {
+	return &MockTodoRepository_Search_Call{Call: _e.mock.On("Search", ctx, search)}
 }
 
-func (_c *MockTodoRepository_Search_Call) Run(run func(search string)) *MockTodoRepository_Search_Call {
+func (_c *MockTodoRepository_Search_Call) Run(run func(ctx context.Context, search string)) *MockTodoRepository_Search_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -326,30 +446,30 @@ func (_c *MockTodoRepository_Search_Call) Return(_a0 []*Todo, _a1 error) *MockTo
 	return _c
 }
 
-func (_c *MockTodoRepository_Search_Call) RunAndReturn(run func(string) ([]*Todo, error)) *MockTodoRepository_Search_Call {
+func (_c *MockTodoRepository_Search_Call) RunAndReturn(run func(context.Context, string) ([]*Todo, error)) *MockTodoRepository_Search_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function with given fields: id, completed, description
-func (_m *MockTodoRepository) Update(id uuid.UUID, completed bool, description string) (*Todo, error) {
-	ret := _m.Called(id, completed, description)
+// Update provides a mock function with given fields: ctx, id, completed, description
+func (_m *MockTodoRepository) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*Todo, error) {
+	ret := _m.Called(ctx, id, completed, description)
 
 	var r0 *Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uuid.UUID, bool, string) (*Todo, error)); ok {
-		return rf(id, completed, description)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, string) (*Todo, error)); ok {
+		return rf(ctx, id, completed, description)
 	}
-	if rf, ok := ret.Get(0).(func(uuid.UUID, bool, string) *Todo); ok {
-		r0 = rf(id, completed, description)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, string) *Todo); ok {
+		r0 = rf(ctx, id, completed, description)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uuid.UUID, bool, string) error); ok {
-		r1 = rf(id, completed, description)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool, string) error); ok {
+		r1 = rf(ctx, id, completed, description)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -363,16 +483,17 @@ type MockTodoRepository_Update_Call struct {
 }
 
 // Update is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id uuid.UUID
 //   - completed bool
 //   - description string
-func (_e *MockTodoRepository_Expecter) Update(id interface{}, completed interface{}, description interface{}) *MockTodoRepository_Update_Call {
-	return &MockTodoRepository_Update_Call{Call: _e.mock.On("Update", id, completed, description)}
+func (_e *MockTodoRepository_Expecter) Update(ctx interface{}, id interface{}, completed interface{}, description interface{}) *MockTodoRepository_Update_Call {
+	return &MockTodoRepository_Update_Call{Call: _e.mock.On("Update", ctx, id, completed, description)}
 }
 
-func (_c *MockTodoRepository_Update_Call) Run(run func(id uuid.UUID, completed bool, description string)) *MockTodoRepository_Update_Call {
+func (_c *MockTodoRepository_Update_Call) Run(run func(ctx context.Context, id uuid.UUID, completed bool, description string)) *MockTodoRepository_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uuid.UUID), args[1].(bool), args[2].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(bool), args[3].(string))
 	})
 	return _c
 }
@@ -382,7 +503,7 @@ func (_c *MockTodoRepository_Update_Call) Return(_a0 *Todo, _a1 error) *MockTodo
 	return _c
 }
 
-func (_c *MockTodoRepository_Update_Call) RunAndReturn(run func(uuid.UUID, bool, string) (*Todo, error)) *MockTodoRepository_Update_Call {
+func (_c *MockTodoRepository_Update_Call) RunAndReturn(run func(context.Context, uuid.UUID, bool, string) (*Todo, error)) *MockTodoRepository_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
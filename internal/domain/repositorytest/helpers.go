@@ -0,0 +1,58 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// ctx is passed to every repository call made by the suite
+var ctx = context.Background()
+
+func mustAdd(t *testing.T, repo domain.TodoRepository, description string) *domain.Todo {
+	t.Helper()
+	todo, err := repo.Add(ctx, description)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return todo
+}
+
+func mustAll(t *testing.T, repo domain.TodoRepository) []*domain.Todo {
+	t.Helper()
+	todos, err := repo.All(ctx)
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	return todos
+}
+
+// assertSame compares todos by value, treating equal instants in different locations as equal
+func assertSame(t *testing.T, call string, got, want *domain.Todo) {
+	t.Helper()
+	if got == nil || want == nil {
+		if got != want {
+			t.Errorf("%s = %v, want %v", call, got, want)
+		}
+		return
+	}
+	if got.ID != want.ID || got.Description != want.Description || got.Completed != want.Completed ||
+		!got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("%s = %v, want %v", call, got, want)
+	}
+}
+
+func assertIDs(t *testing.T, call string, got []*domain.Todo, want ...uuid.UUID) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s returned %d todos, want %d", call, len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("%s[%d] = %v, want %v", call, i, got[i].ID, id)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package repositorytest provides a conformance suite for domain.TodoRepository implementations
+package repositorytest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// Run checks that the repositories returned by newRepository behave like the
+// in-memory domain.Todos; every subtest gets a new, empty repository
+func Run(t *testing.T, newRepository func(t *testing.T) domain.TodoRepository) {
+	t.Helper()
+
+	tests := map[string]func(t *testing.T, repo domain.TodoRepository){
+		"AddAndGet":     testAddAndGet,
+		"GetMissing":    testGetMissing,
+		"Update":        testUpdate,
+		"UpdateMissing": testUpdateMissing,
+		"Remove":        testRemove,
+		"RemoveMissing": testRemoveMissing,
+		"All":           testAll,
+		"Search":        testSearch,
+		"Reorder":       testReorder,
+		"Export":        testExport,
+		"ImportMerge":   testImportMerge,
+		"ImportReplace": testImportReplace,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepository(t))
+		})
+	}
+}
+
+func testAddAndGet(t *testing.T, repo domain.TodoRepository) {
+	added, err := repo.Add(ctx, "Bake a cake")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if added == nil || added.ID == uuid.Nil || added.CreatedAt.IsZero() {
+		t.Fatalf("Add() = %v, want a todo with an id and creation time", added)
+	}
+	if added.Description != "Bake a cake" || added.Completed {
+		t.Errorf("Add() = %v, want an incomplete todo described as %q", added, "Bake a cake")
+	}
+
+	got, err := repo.Get(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	assertSame(t, "Get()", got, added)
+}
+
+func testGetMissing(t *testing.T, repo domain.TodoRepository) {
+	got, err := repo.Get(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Get() = %v, want nil", got)
+	}
+}
+
+func testUpdate(t *testing.T, repo domain.TodoRepository) {
+	added := mustAdd(t, repo, "Bake a cake")
+
+	updated, err := repo.Update(ctx, added.ID, true, "Bake two cakes")
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated == nil || updated.ID != added.ID || !updated.Completed || updated.Description != "Bake two cakes" {
+		t.Fatalf("Update() = %v, want the completed todo with its new description", updated)
+	}
+
+	got, err := repo.Get(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	assertSame(t, "Get()", got, updated)
+}
+
+func testUpdateMissing(t *testing.T, repo domain.TodoRepository) {
+	got, err := repo.Update(ctx, uuid.New(), true, "Bake a cake")
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Update() = %v, want nil", got)
+	}
+}
+
+func testRemove(t *testing.T, repo domain.TodoRepository) {
+	removed := mustAdd(t, repo, "Bake a cake")
+	kept := mustAdd(t, repo, "Feed the cat")
+
+	if err := repo.Remove(ctx, removed.ID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	got, err := repo.Get(ctx, removed.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Get() = %v, want nil after Remove()", got)
+	}
+	assertIDs(t, "All()", mustAll(t, repo), kept.ID)
+}
+
+func testRemoveMissing(t *testing.T, repo domain.TodoRepository) {
+	kept := mustAdd(t, repo, "Bake a cake")
+
+	if err := repo.Remove(ctx, uuid.New()); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertIDs(t, "All()", mustAll(t, repo), kept.ID)
+}
+
+func testAll(t *testing.T, repo domain.TodoRepository) {
+	if got := mustAll(t, repo); len(got) != 0 {
+		t.Fatalf("All() = %v, want an empty list", got)
+	}
+
+	first := mustAdd(t, repo, "Bake a cake")
+	second := mustAdd(t, repo, "Feed the cat")
+	third := mustAdd(t, repo, "Take out the trash")
+
+	assertIDs(t, "All()", mustAll(t, repo), first.ID, second.ID, third.ID)
+}
+
+func testSearch(t *testing.T, repo domain.TodoRepository) {
+	cake := mustAdd(t, repo, "Bake a cake")
+	_ = mustAdd(t, repo, "Feed the cat")
+	bread := mustAdd(t, repo, "Bake bread")
+
+	got, err := repo.Search(ctx, "Bake")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	assertIDs(t, "Search()", got, cake.ID, bread.ID)
+
+	got, err = repo.Search(ctx, "nothing matches")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	assertIDs(t, "Search()", got)
+}
+
+func testReorder(t *testing.T, repo domain.TodoRepository) {
+	first := mustAdd(t, repo, "Bake a cake")
+	second := mustAdd(t, repo, "Feed the cat")
+	third := mustAdd(t, repo, "Take out the trash")
+
+	got, err := repo.Reorder(ctx, []uuid.UUID{third.ID, first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	assertIDs(t, "Reorder()", got, third.ID, first.ID, second.ID)
+	assertIDs(t, "All()", mustAll(t, repo), third.ID, first.ID, second.ID)
+}
+
+func testExport(t *testing.T, repo domain.TodoRepository) {
+	first := mustAdd(t, repo, "Bake a cake")
+	second := mustAdd(t, repo, "Feed the cat")
+
+	data, err := repo.Export(ctx, domain.FormatCSV)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	codec, _ := domain.CodecFor(domain.FormatCSV)
+	exported, err := codec.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Export() wrote an unreadable list: %v", err)
+	}
+	assertIDs(t, "Export()", exported, first.ID, second.ID)
+
+	if _, err := repo.Export(ctx, "xml"); err == nil {
+		t.Errorf("Export() error = nil, want an error for an unsupported format")
+	}
+}
+
+func testImportMerge(t *testing.T, repo domain.TodoRepository) {
+	existing := mustAdd(t, repo, "Bake a cake")
+	imported := domain.NewTodo("Feed the cat")
+
+	report, err := repo.Import(ctx, []*domain.Todo{imported}, domain.ImportMerge, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !report.DryRun || report.Mode != domain.ImportMerge || len(report.Added) != 1 {
+		t.Errorf("Import() report = %v, want a merge dry run adding one todo", report)
+	}
+	assertIDs(t, "All() after a dry run", mustAll(t, repo), existing.ID)
+
+	if _, err = repo.Import(ctx, []*domain.Todo{imported}, domain.ImportMerge, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	assertIDs(t, "All()", mustAll(t, repo), existing.ID, imported.ID)
+}
+
+func testImportReplace(t *testing.T, repo domain.TodoRepository) {
+	existing := mustAdd(t, repo, "Bake a cake")
+	imported := domain.NewTodo("Feed the cat")
+
+	report, err := repo.Import(ctx, []*domain.Todo{imported}, domain.ImportReplace, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.DryRun || len(report.Added) != 1 || len(report.Removed) != 1 || report.Removed[0].ID != existing.ID {
+		t.Errorf("Import() report = %v, want one todo added and %v removed", report, existing.ID)
+	}
+	assertIDs(t, "All()", mustAll(t, repo), imported.ID)
+}
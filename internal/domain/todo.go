@@ -11,6 +11,15 @@ type Todo struct {
 	Description string
 	Completed   bool
 	CreatedAt   time.Time
+	// DueAt is when the todo should be done by, if it has a due date
+	DueAt *time.Time `json:"DueAt,omitempty"`
+	// ListID is the list the todo belongs to; it is kept by the repository and never
+	// sent to clients
+	ListID uuid.UUID `json:"-" cbor:"-"`
+	// UpdatedAt is when the todo last changed and Revision how often it has; both are
+	// kept by the repository for calendar clients and never sent to clients
+	UpdatedAt time.Time `json:"-" cbor:"-"`
+	Revision  int       `json:"-" cbor:"-"`
 }
 
 // NewTodo creates a new todo
@@ -23,8 +32,22 @@ func NewTodo(description string) *Todo {
 	}
 }
 
-// Update updates a todo
+// Update updates a todo, and its revision when anything changed
 func (t *Todo) Update(completed bool, description string) {
+	if t.Completed == completed && t.Description == description {
+		return
+	}
 	t.Completed = completed
 	t.Description = description
+	t.UpdatedAt = time.Now()
+	t.Revision++
+}
+
+// ModifiedAt returns when the todo last changed, which is when it was created until
+// it is updated
+func (t *Todo) ModifiedAt() time.Time {
+	if t.UpdatedAt.IsZero() {
+		return t.CreatedAt
+	}
+	return t.UpdatedAt
 }
@@ -2,41 +2,102 @@ package domain
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
 )
 
-type TodoApi struct {
-	client *http.Client
-	host   string
-}
+const (
+	// IdempotencyKeyHeader names the request header carrying an idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentRetries is how many times a request with an idempotency key is resent
+	idempotentRetries = 2
+)
+
+type (
+	TodoApi struct {
+		client *http.Client
+		host   string
+		wire   wireFormat
+		token  string
+
+		// csrfToken is the CSRF token of the session kept in the cookie jar
+		mu        sync.RWMutex
+		csrfToken string
+	}
 
-var _ TodoRepository = (*TodoApi)(nil)
+	// TodoApiOption configures a TodoApi
+	TodoApiOption func(*TodoApi)
+)
 
-func NewTodoApi(host string) *TodoApi {
-	return &TodoApi{
+var (
+	_ TodoRepository = (*TodoApi)(nil)
+	_ Accounts       = (*TodoApi)(nil)
+	_ Sharing        = (*TodoApi)(nil)
+)
+
+func NewTodoApi(host string, options ...TodoApiOption) *TodoApi {
+	t := &TodoApi{
 		client: &http.Client{
 			Timeout: time.Second * 10,
 		},
 		host: host,
+		wire: jsonWire,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// WithCookieJar keeps the session cookie set by Register and Login in jar and sends it,
+// along with the CSRF token of the session, with every later request. In the browser
+// the fetch API keeps the cookie instead, as the proxy and the server share an origin,
+// so the WASM proxy needs no jar; it passes on the CSRF token sent by the page, see
+// ContextWithCSRFToken.
+func WithCookieJar(jar http.CookieJar) TodoApiOption {
+	return func(t *TodoApi) {
+		t.client.Jar = jar
+	}
+}
+
+// WithToken sends the API token with every request, for scripts that use the REST API
+// without signing in
+func WithToken(token string) TodoApiOption {
+	return func(t *TodoApi) {
+		t.token = token
+	}
+}
+
+// WithCBOR exchanges request and response bodies with the server as CBOR rather than JSON
+func WithCBOR() TodoApiOption {
+	return func(t *TodoApi) {
+		t.wire = cborWire
 	}
 }
 
-func (t *TodoApi) Add(description string) (*Todo, error) {
+func (t *TodoApi) Add(ctx context.Context, description string) (*Todo, error) {
 	type addTodoRequest struct {
 		Description string `json:"description"`
 	}
 
-	data, err := json.Marshal(addTodoRequest{Description: description})
+	data, err := t.wire.marshal(addTodoRequest{Description: description})
 	if err != nil {
 		return nil, ErrMarshaling{Err: err}
 	}
 
-	resp, err := t.doRequest(http.MethodPost, "/todos", bytes.NewReader(data))
+	resp, err := t.doIdempotentRequest(ctx, http.MethodPost, "/todos", data)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +105,7 @@ func (t *TodoApi) Add(description string) (*Todo, error) {
 	type addTodoResponse *Todo
 
 	var addTodoResp addTodoResponse
-	err = json.NewDecoder(resp.Body).Decode(&addTodoResp)
+	err = t.wire.decode(resp.Body, &addTodoResp)
 	if err != nil {
 		return nil, ErrUnmarshaling{Err: err}
 	}
@@ -52,8 +113,8 @@ func (t *TodoApi) Add(description string) (*Todo, error) {
 	return addTodoResp, nil
 }
 
-func (t *TodoApi) Remove(id uuid.UUID) error {
-	_, err := t.doRequest(http.MethodDelete, "/todos/"+id.String(), nil)
+func (t *TodoApi) Remove(ctx context.Context, id uuid.UUID) error {
+	_, err := t.doRequest(ctx, http.MethodDelete, "/todos/"+id.String(), nil)
 	if err != nil {
 		return err
 	}
@@ -61,18 +122,18 @@ func (t *TodoApi) Remove(id uuid.UUID) error {
 	return nil
 }
 
-func (t *TodoApi) Update(id uuid.UUID, completed bool, description string) (*Todo, error) {
+func (t *TodoApi) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*Todo, error) {
 	type updateTodoRequest struct {
 		Completed   bool   `json:"completed"`
 		Description string `json:"description"`
 	}
 
-	data, err := json.Marshal(updateTodoRequest{Completed: completed, Description: description})
+	data, err := t.wire.marshal(updateTodoRequest{Completed: completed, Description: description})
 	if err != nil {
 		return nil, ErrMarshaling{Err: err}
 	}
 
-	resp, err := t.doRequest(http.MethodPatch, "/todos/"+id.String(), bytes.NewReader(data))
+	resp, err := t.doRequest(ctx, http.MethodPatch, "/todos/"+id.String(), bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +141,7 @@ func (t *TodoApi) Update(id uuid.UUID, completed bool, description string) (*Tod
 	type updateTodoResponse *Todo
 
 	var updateTodoResp updateTodoResponse
-	err = json.NewDecoder(resp.Body).Decode(&updateTodoResp)
+	err = t.wire.decode(resp.Body, &updateTodoResp)
 	if err != nil {
 		return nil, ErrUnmarshaling{Err: err}
 	}
@@ -88,8 +149,12 @@ func (t *TodoApi) Update(id uuid.UUID, completed bool, description string) (*Tod
 	return updateTodoResp, nil
 }
 
-func (t *TodoApi) Search(search string) ([]*Todo, error) {
-	resp, err := t.doRequest(http.MethodGet, "/todos", nil)
+func (t *TodoApi) Search(ctx context.Context, search string) ([]*Todo, error) {
+	path := "/todos"
+	if search != "" {
+		path += "?search=" + url.QueryEscape(search)
+	}
+	resp, err := t.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +162,7 @@ func (t *TodoApi) Search(search string) ([]*Todo, error) {
 	type searchTodoResponse []*Todo
 
 	var searchTodoResp searchTodoResponse
-	err = json.NewDecoder(resp.Body).Decode(&searchTodoResp)
+	err = t.wire.decode(resp.Body, &searchTodoResp)
 	if err != nil {
 		return nil, ErrUnmarshaling{Err: err}
 	}
@@ -105,12 +170,12 @@ func (t *TodoApi) Search(search string) ([]*Todo, error) {
 	return searchTodoResp, nil
 }
 
-func (t *TodoApi) All() ([]*Todo, error) {
-	return t.Search("")
+func (t *TodoApi) All(ctx context.Context) ([]*Todo, error) {
+	return t.Search(ctx, "")
 }
 
-func (t *TodoApi) Get(id uuid.UUID) (*Todo, error) {
-	resp, err := t.doRequest(http.MethodGet, "/todos/"+id.String(), nil)
+func (t *TodoApi) Get(ctx context.Context, id uuid.UUID) (*Todo, error) {
+	resp, err := t.doRequest(ctx, http.MethodGet, "/todos/"+id.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +183,7 @@ func (t *TodoApi) Get(id uuid.UUID) (*Todo, error) {
 	type getTodoResponse *Todo
 
 	var getTodoResp getTodoResponse
-	err = json.NewDecoder(resp.Body).Decode(&getTodoResp)
+	err = t.wire.decode(resp.Body, &getTodoResp)
 	if err != nil {
 		return nil, ErrUnmarshaling{Err: err}
 	}
@@ -126,17 +191,17 @@ func (t *TodoApi) Get(id uuid.UUID) (*Todo, error) {
 	return getTodoResp, nil
 }
 
-func (t *TodoApi) Reorder(ids []uuid.UUID) ([]*Todo, error) {
+func (t *TodoApi) Reorder(ctx context.Context, ids []uuid.UUID) ([]*Todo, error) {
 	type reorderTodoRequest struct {
 		IDs []uuid.UUID `json:"ids"`
 	}
 
-	data, err := json.Marshal(reorderTodoRequest{IDs: ids})
+	data, err := t.wire.marshal(reorderTodoRequest{IDs: ids})
 	if err != nil {
 		return nil, ErrMarshaling{Err: err}
 	}
 
-	resp, err := t.doRequest(http.MethodPost, "/todos/sort", bytes.NewReader(data))
+	resp, err := t.doIdempotentRequest(ctx, http.MethodPost, "/todos/sort", data)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +209,7 @@ func (t *TodoApi) Reorder(ids []uuid.UUID) ([]*Todo, error) {
 	type reorderTodoResponse []*Todo
 
 	var reorderTodoResp reorderTodoResponse
-	err = json.NewDecoder(resp.Body).Decode(&reorderTodoResp)
+	err = t.wire.decode(resp.Body, &reorderTodoResp)
 	if err != nil {
 		return nil, ErrUnmarshaling{Err: err}
 	}
@@ -152,15 +217,328 @@ func (t *TodoApi) Reorder(ids []uuid.UUID) ([]*Todo, error) {
 	return reorderTodoResp, nil
 }
 
-func (t *TodoApi) doRequest(method, path string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, t.host+path, body)
+func (t *TodoApi) Export(ctx context.Context, format string) ([]byte, error) {
+	resp, err := t.doRequest(ctx, http.MethodGet, "/todos/export?format="+url.QueryEscape(format), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	return data, nil
+}
+
+func (t *TodoApi) Import(ctx context.Context, todos []*Todo, mode ImportMode, dryRun bool) (*ImportReport, error) {
+	codec, err := CodecFor(t.wire.format)
+	if err != nil {
+		return nil, err
+	}
+	var data bytes.Buffer
+	if err := codec.Encode(&data, todos); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("format", t.wire.format)
+	query.Set("mode", string(mode))
+	query.Set("dry_run", strconv.FormatBool(dryRun))
+
+	resp, err := t.doRequest(ctx, http.MethodPost, "/todos/import?"+query.Encode(), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	type importTodoResponse *ImportReport
+
+	var importTodoResp importTodoResponse
+	err = t.wire.decode(resp.Body, &importTodoResp)
+	if err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	return importTodoResp, nil
+}
+
+// Register creates a user and signs them in
+func (t *TodoApi) Register(ctx context.Context, username, password string) (*User, error) {
+	return t.authenticate(ctx, "/auth/register", username, password)
+}
+
+// Login signs the user in; a wrong username or password is an ErrInvalidCredentials
+func (t *TodoApi) Login(ctx context.Context, username, password string) (*User, error) {
+	user, err := t.authenticate(ctx, "/auth/login", username, password)
+	var unauthenticatedErr ErrUnauthenticated
+	if errors.As(err, &unauthenticatedErr) {
+		return nil, ErrInvalidCredentials{}
+	}
+	return user, err
+}
+
+// Logout ends the session
+func (t *TodoApi) Logout(ctx context.Context) error {
+	resp, err := t.doRequest(ctx, http.MethodPost, "/auth/logout", nil)
+	if err != nil {
+		return err
+	}
+	t.setCSRFToken("")
+	return resp.Body.Close()
+}
+
+// CSRFToken returns the CSRF token of the session, which pages send back with every
+// request that changes something
+func (t *TodoApi) CSRFToken(ctx context.Context) (string, error) {
+	type csrfResponse struct {
+		Token string `json:"token"`
+	}
+
+	resp, err := t.doRequest(ctx, http.MethodGet, "/auth/csrf", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var csrfResp csrfResponse
+	if err := t.wire.decode(resp.Body, &csrfResp); err != nil {
+		return "", ErrUnmarshaling{Err: err}
+	}
+
+	return csrfResp.Token, nil
+}
+
+// setCSRFToken remembers the CSRF token of a session kept in the cookie jar; without
+// a jar the token must come from the context of each request, so that the proxy never
+// vouches for a request the page did not make
+func (t *TodoApi) setCSRFToken(token string) {
+	if t.client.Jar == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.csrfToken = token
+}
+
+func (t *TodoApi) authenticate(ctx context.Context, path, username, password string) (*User, error) {
+	type credentialsRequest struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	data, err := t.wire.marshal(credentialsRequest{Username: username, Password: password})
+	if err != nil {
+		return nil, ErrMarshaling{Err: err}
+	}
+
+	resp, err := t.doRequest(ctx, http.MethodPost, path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var user User
+	if err := t.wire.decode(resp.Body, &user); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	t.setCSRFToken(resp.Header.Get(CSRFHeader))
+
+	return &user, nil
+}
+
+// Lists returns the memberships of the signed in user
+func (t *TodoApi) Lists(ctx context.Context) ([]*Membership, error) {
+	return t.memberships(ctx, "/lists")
+}
+
+// Members returns the memberships of the list of the context
+func (t *TodoApi) Members(ctx context.Context) ([]*Membership, error) {
+	return t.memberships(ctx, "/lists/members")
+}
+
+// Invite shares the list of the context with the user with the username
+func (t *TodoApi) Invite(ctx context.Context, username string, role Role) (*Membership, error) {
+	type inviteRequest struct {
+		Username string `json:"username"`
+		Role     Role   `json:"role"`
+	}
+
+	data, err := t.wire.marshal(inviteRequest{Username: username, Role: role})
+	if err != nil {
+		return nil, ErrMarshaling{Err: err}
+	}
+
+	resp, err := t.doRequest(ctx, http.MethodPost, "/lists/members", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var membership Membership
+	if err := t.wire.decode(resp.Body, &membership); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	return &membership, nil
+}
+
+// RemoveMember takes the user out of the list of the context
+func (t *TodoApi) RemoveMember(ctx context.Context, userID uuid.UUID) error {
+	resp, err := t.doRequest(ctx, http.MethodDelete, "/lists/members/"+userID.String(), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *TodoApi) memberships(ctx context.Context, path string) ([]*Membership, error) {
+	resp, err := t.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var memberships []*Membership
+	if err := t.wire.decode(resp.Body, &memberships); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	return memberships, nil
+}
+
+// Version returns the version of the server, which is not part of the TodoRepository
+func (t *TodoApi) Version(ctx context.Context) (*Version, error) {
+	resp, err := t.doRequest(ctx, http.MethodGet, "/version", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var version Version
+	if err := jsonWire.decode(resp.Body, &version); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+
+	return &version, nil
+}
+
+func (t *TodoApi) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.host+path, body)
 	if err != nil {
 		return nil, ErrCreateRequest{Err: err}
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := t.client.Do(req)
+	return t.send(req)
+}
+
+// doIdempotentRequest sends a request under a new Idempotency-Key, resending it
+// with the same key up to idempotentRetries times when it fails to reach the
+// server, so that the server applies it at most once; once the context is done
+// the request is given up
+func (t *TodoApi) doIdempotentRequest(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	key := uuid.NewString()
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, t.host+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, ErrCreateRequest{Err: err}
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+
+		resp, err := t.send(req)
+		var requestErr ErrMakeRequest
+		// a canceled or timed out request is given up rather than resent
+		if err == nil || !errors.As(err, &requestErr) || attempt == idempotentRetries || ctx.Err() != nil {
+			return resp, err
+		}
+	}
+}
+
+// send runs the request in a client span whose traceparent, along with the request ID,
+// is passed on to the server
+func (t *TodoApi) send(req *http.Request) (resp *http.Response, err error) {
+	ctx, span := trace.Start(req.Context(), "TodoApi "+req.Method+" "+req.URL.Path, trace.WithKind(trace.KindClient))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+	req = req.WithContext(ctx)
+	trace.Inject(ctx, req.Header)
+	if id := log.RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(log.RequestIDHeader, id)
+	}
+
+	req.Header.Set("Content-Type", t.wire.mediaType)
+	req.Header.Set("Accept", t.wire.mediaType)
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	if listID, ok := ListFromContext(ctx); ok {
+		req.Header.Set(ListHeader, listID.String())
+	}
+	if token := t.csrfTokenFor(ctx); token != "" {
+		req.Header.Set(CSRFHeader, token)
+	}
+	req.Header.Set(APIVersionHeader, APIVersion)
+	resp, err = t.client.Do(req)
 	if err != nil {
 		return nil, ErrMakeRequest{Err: err}
 	}
+	span.SetAttributes("http.status_code", strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, t.responseError(resp)
+	}
 	return resp, nil
 }
+
+// csrfTokenFor returns the CSRF token of the context, or the one of the session in the
+// cookie jar
+func (t *TodoApi) csrfTokenFor(ctx context.Context) string {
+	if token := CSRFTokenFromContext(ctx); token != "" {
+		return token
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.csrfToken
+}
+
+// responseError converts an unsuccessful response into an error
+func (t *TodoApi) responseError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthenticated{}
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		if resp.Header.Get(CSRFHeader) == "invalid" {
+			return ErrInvalidCSRFToken{}
+		}
+		// the scope the token lacks is named by WWW-Authenticate: Bearer scope="..."
+		if _, scope, found := strings.Cut(resp.Header.Get("WWW-Authenticate"), `scope="`); found {
+			scope, _, _ = strings.Cut(scope, `"`)
+			return ErrInsufficientScope{Scope: Scope(scope)}
+		}
+		var permissionErr ErrPermissionDenied
+		if err := t.wire.decode(resp.Body, &permissionErr); err != nil {
+			return ErrUnmarshaling{Err: err}
+		}
+		return permissionErr
+	}
+	if resp.StatusCode == http.StatusPreconditionFailed && resp.Header.Get(APIVersionHeader) != "" {
+		return ErrIncompatibleAPI{API: resp.Header.Get(APIVersionHeader)}
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return ErrIdempotencyKeyReused{}
+	}
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		var validationErr ErrValidation
+		if err := t.wire.decode(resp.Body, &validationErr); err != nil {
+			return ErrUnmarshaling{Err: err}
+		}
+		return validationErr
+	}
+
+	message, _ := io.ReadAll(resp.Body)
+	return ErrResponse{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(message)),
+	}
+}
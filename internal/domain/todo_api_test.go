@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+func TestTodoApi_AddResendsWithSameIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+
+		// drop the first attempt as a network failure would
+		if attempt == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(NewTodo("Bake a cake"))
+	}))
+	defer server.Close()
+
+	todo, err := NewTodoApi(server.URL).Add(context.Background(), "Bake a cake")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if todo == nil || todo.Description != "Bake a cake" {
+		t.Errorf("Add() = %v, want the created todo", todo)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Add() sent Idempotency-Keys %q, want the same key twice", keys)
+	}
+}
+
+func TestTodoApi_AddGivesUpWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var attempts int
+	api := NewTodoApi("http://localhost")
+	api.client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		// the caller gives up while the request is in flight
+		cancel()
+		return nil, r.Context().Err()
+	})
+
+	if _, err := api.Add(ctx, "Bake a cake"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Add() error = %v, want %v", err, context.Canceled)
+	}
+	if attempts != 1 {
+		t.Errorf("Add() sent %d requests, want 1", attempts)
+	}
+}
+
+// roundTripFunc lets a function stand in for the transport of a client
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTodoApi_ForwardsRequestID(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(log.RequestIDHeader)
+		_ = json.NewEncoder(w).Encode([]*Todo{})
+	}))
+	defer server.Close()
+
+	var want string
+	handler := log.RequestID(zerolog.Nop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want = log.RequestIDFromContext(r.Context())
+		if _, err := NewTodoApi(server.URL).All(r.Context()); err != nil {
+			t.Errorf("All() error = %v", err)
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if want == "" || got != want {
+		t.Errorf("server received %s %q, want %q", log.RequestIDHeader, got, want)
+	}
+}
+
+func TestTodoApi_Version(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Version{Module: "todos", Revision: "abc123", API: "2.1", Client: "0123456789ab"})
+	}))
+	defer server.Close()
+
+	for name, options := range map[string][]TodoApiOption{"JSON": nil, "CBOR": {WithCBOR()}} {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewTodoApi(server.URL, options...).Version(context.Background())
+			if err != nil {
+				t.Fatalf("Version() error = %v", err)
+			}
+			if got.Module != "todos" || got.Revision != "abc123" || got.API != "2.1" || got.Client != "0123456789ab" {
+				t.Errorf("Version() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestTodoApi_WithToken(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="todos:write"`)
+		http.Error(w, "token lacks the todos:write scope", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewTodoApi(server.URL, WithToken("todo_secret")).Add(context.Background(), "Bake a cake")
+	if got != "Bearer todo_secret" {
+		t.Errorf("server received Authorization %q, want %q", got, "Bearer todo_secret")
+	}
+	var scopeErr ErrInsufficientScope
+	if !errors.As(err, &scopeErr) || scopeErr.Scope != ScopeWrite {
+		t.Errorf("Add() error = %v, want ErrInsufficientScope for %s", err, ScopeWrite)
+	}
+}
+
+func TestTodoApi_IncompatibleAPI(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(APIVersionHeader)
+		w.Header().Set(APIVersionHeader, "2.0")
+		http.Error(w, "incompatible", http.StatusPreconditionFailed)
+	}))
+	defer server.Close()
+
+	_, err := NewTodoApi(server.URL).All(context.Background())
+	if got != APIVersion {
+		t.Errorf("server received %s %q, want %q", APIVersionHeader, got, APIVersion)
+	}
+	var apiErr ErrIncompatibleAPI
+	if !errors.As(err, &apiErr) || apiErr.API != "2.0" {
+		t.Errorf("All() error = %v, want ErrIncompatibleAPI for 2.0", err)
+	}
+}
+
+func TestTodoApi_IdempotencyKeyReused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, ErrIdempotencyKeyReused{}.Error(), http.StatusConflict)
+	}))
+	defer server.Close()
+
+	_, err := NewTodoApi(server.URL).Add(context.Background(), "Bake a cake")
+	if !errors.As(err, &ErrIdempotencyKeyReused{}) {
+		t.Errorf("Add() error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
@@ -1,15 +1,37 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 )
 
 type TodoRepository interface {
-	Add(description string) (*Todo, error)
-	Remove(id uuid.UUID) error
-	Update(id uuid.UUID, completed bool, description string) (*Todo, error)
-	Search(search string) ([]*Todo, error)
-	All() ([]*Todo, error)
-	Get(id uuid.UUID) (*Todo, error)
-	Reorder(ids []uuid.UUID) ([]*Todo, error)
+	Add(ctx context.Context, description string) (*Todo, error)
+	Remove(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*Todo, error)
+	Search(ctx context.Context, search string) ([]*Todo, error)
+	All(ctx context.Context) ([]*Todo, error)
+	Get(ctx context.Context, id uuid.UUID) (*Todo, error)
+	Reorder(ctx context.Context, ids []uuid.UUID) ([]*Todo, error)
+	Export(ctx context.Context, format string) ([]byte, error)
+	Import(ctx context.Context, todos []*Todo, mode ImportMode, dryRun bool) (*ImportReport, error)
+}
+
+// WritableChecker is implemented by persistent repositories that can verify that
+// they are able to store changes
+type WritableChecker interface {
+	CheckWritable(ctx context.Context) error
+}
+
+// Flusher is implemented by repositories that buffer changes and must write them
+// out before the process exits
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Scanner is implemented by repositories that can list the todos of every owner, for
+// in-process uses such as metrics; it must never be reachable by users
+type Scanner interface {
+	Scan(ctx context.Context) ([]*Todo, error)
 }
@@ -54,8 +54,9 @@ func TestTodo_Update(t *testing.T) {
 		description string
 	}
 	tests := map[string]struct {
-		fields fields
-		args   args
+		fields       fields
+		args         args
+		wantRevision int
 	}{
 		"Update": {
 			fields: fields{
@@ -68,6 +69,7 @@ func TestTodo_Update(t *testing.T) {
 				completed:   true,
 				description: "test2",
 			},
+			wantRevision: 1,
 		},
 		"SameValues:": {
 			fields: fields{
@@ -99,6 +101,12 @@ func TestTodo_Update(t *testing.T) {
 			if todo.Description != tt.args.description {
 				t1.Errorf("Update() = %v, want %v", todo.Description, tt.args.description)
 			}
+			if todo.Revision != tt.wantRevision {
+				t1.Errorf("Update() Revision = %v, want %v", todo.Revision, tt.wantRevision)
+			}
+			if changed := todo.ModifiedAt() != tt.fields.CreatedAt; changed != (tt.wantRevision > 0) {
+				t1.Errorf("Update() ModifiedAt = %v, created at %v", todo.ModifiedAt(), tt.fields.CreatedAt)
+			}
 		})
 	}
 }
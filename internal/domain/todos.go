@@ -1,16 +1,29 @@
 package domain
 
 import (
+	"bytes"
+	"context"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
 )
 
-// Todos is a list of Todo
-type Todos []*Todo
+// Todos holds the todos of every list; every method only sees the todos of the list
+// of the context, see ContextWithList; it is safe for concurrent use and hands out
+// copies, so callers never share a todo with the list
+type Todos struct {
+	mu    sync.RWMutex
+	todos []*Todo
+}
 
-// Verify that Todos implements the TodoRepository interface
-var _ TodoRepository = (*Todos)(nil)
+// Verify that Todos implements the TodoRepository and Scanner interfaces
+var (
+	_ TodoRepository = (*Todos)(nil)
+	_ Scanner        = (*Todos)(nil)
+)
 
 // NewTodos creates a new list of todos
 func NewTodos() *Todos {
@@ -18,77 +31,236 @@ func NewTodos() *Todos {
 }
 
 // Add adds a todo to the list
-func (l *Todos) Add(description string) (*Todo, error) {
+func (l *Todos) Add(ctx context.Context, description string) (*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Add")
+	defer span.End()
+
 	todo := NewTodo(description)
-	*l = append(*l, todo)
-	return todo, nil
+	todo.ListID = listID(ctx)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.todos = append(l.todos, todo)
+	return copyTodo(todo), nil
 }
 
 // Remove removes a todo from the list
-func (l *Todos) Remove(id uuid.UUID) error {
-	index := l.indexOf(id)
+func (l *Todos) Remove(ctx context.Context, id uuid.UUID) error {
+	_, span := trace.Start(ctx, "Todos.Remove")
+	defer span.End()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	index := l.indexOf(ctx, id)
 	if index == -1 {
 		return nil
 	}
-	*l = append((*l)[:index], (*l)[index+1:]...)
+	l.todos = append(l.todos[:index], l.todos[index+1:]...)
 	return nil
 }
 
 // Update updates a todo in the list
-func (l *Todos) Update(id uuid.UUID, completed bool, description string) (*Todo, error) {
-	index := l.indexOf(id)
+func (l *Todos) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Update")
+	defer span.End()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	index := l.indexOf(ctx, id)
 	if index == -1 {
 		return nil, nil
 	}
-	todo := (*l)[index]
+	todo := l.todos[index]
 	todo.Update(completed, description)
 
-	return todo, nil
+	return copyTodo(todo), nil
 }
 
 // Search returns a list of todos that match the search string
-func (l *Todos) Search(search string) ([]*Todo, error) {
+func (l *Todos) Search(ctx context.Context, search string) ([]*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Search")
+	defer span.End()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	list := make([]*Todo, 0)
-	for _, todo := range *l {
+	for _, todo := range l.listed(ctx) {
 		if strings.Contains(todo.Description, search) {
 			list = append(list, todo)
 		}
 	}
-	return list, nil
+	return copies(list), nil
 }
 
 // All returns a copy of the todos list
-func (l *Todos) All() ([]*Todo, error) {
-	list := make([]*Todo, len(*l))
-	copy(list, *l)
-	return list, nil
+func (l *Todos) All(ctx context.Context) ([]*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.All")
+	defer span.End()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return copies(l.listed(ctx)), nil
+}
+
+// Scan returns a copy of the todos of every list
+func (l *Todos) Scan(ctx context.Context) ([]*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Scan")
+	defer span.End()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return copies(l.todos), nil
 }
 
 // Get returns a todo by id
-func (l *Todos) Get(id uuid.UUID) (*Todo, error) {
-	index := l.indexOf(id)
+func (l *Todos) Get(ctx context.Context, id uuid.UUID) (*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Get")
+	defer span.End()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	index := l.indexOf(ctx, id)
 	if index == -1 {
 		return nil, nil
 	}
-	return (*l)[index], nil
+	return copyTodo(l.todos[index]), nil
 }
 
 // Reorder reorders the list of todos
-func (l *Todos) Reorder(ids []uuid.UUID) ([]*Todo, error) {
-	newTodos := make([]*Todo, len(ids))
-	for i, id := range ids {
-		newTodos[i] = (*l)[l.indexOf(id)]
+func (l *Todos) Reorder(ctx context.Context, ids []uuid.UUID) ([]*Todo, error) {
+	_, span := trace.Start(ctx, "Todos.Reorder")
+	defer span.End()
+
+	// the list's todos are moved among the positions they already take up; ids
+	// that are not in the list are ignored and todos left out keep their order after
+	// the others
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	positions := l.positions(ctx)
+	newTodos := make([]*Todo, 0, len(positions))
+	seen := make(map[uuid.UUID]bool, len(positions))
+	for _, id := range ids {
+		if index := l.indexOf(ctx, id); index != -1 && !seen[id] {
+			seen[id] = true
+			newTodos = append(newTodos, l.todos[index])
+		}
+	}
+	for _, position := range positions {
+		if todo := l.todos[position]; !seen[todo.ID] {
+			newTodos = append(newTodos, todo)
+		}
+	}
+	for i, todo := range newTodos {
+		l.todos[positions[i]] = todo
+	}
+	return copies(newTodos), nil
+}
+
+// Export encodes the list of todos in the given format
+func (l *Todos) Export(ctx context.Context, format string) ([]byte, error) {
+	_, span := trace.Start(ctx, "Todos.Export")
+	defer span.End()
+
+	codec, err := CodecFor(format)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var buf bytes.Buffer
+	if err := codec.Encode(&buf, l.listed(ctx)); err != nil {
+		return nil, err
 	}
-	copy(*l, newTodos)
-	return newTodos, nil
+	return buf.Bytes(), nil
 }
 
-// indexOf returns the index of the todo with the given id or -1 if not found
-func (l *Todos) indexOf(id uuid.UUID) int {
-	for i, todo := range *l {
+// Import merges the todos into the list or replaces the list with them
+func (l *Todos) Import(ctx context.Context, todos []*Todo, mode ImportMode, dryRun bool) (*ImportReport, error) {
+	_, span := trace.Start(ctx, "Todos.Import")
+	defer span.End()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current := listID(ctx)
+	imported := make([]*Todo, len(todos))
+	for i, todo := range todos {
+		todo = copyTodo(todo)
+		// an ID that another list already uses is replaced rather than shared
+		if index := l.indexOfAny(todo.ID); index != -1 && l.todos[index].ListID != current {
+			todo.ID = uuid.New()
+		}
+		todo.ListID = current
+		imported[i] = todo
+	}
+
+	report, result, err := PlanImport(l.listed(ctx), imported, mode)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = dryRun
+	report.Removed = copies(report.Removed)
+	if !dryRun {
+		others := make([]*Todo, 0, len(l.todos))
+		for _, todo := range l.todos {
+			if todo.ListID != current {
+				others = append(others, todo)
+			}
+		}
+		l.todos = append(others, result...)
+	}
+	return report, nil
+}
+
+// indexOf returns the index of the todo with the given id in the list of the context,
+// or -1 if not found
+func (l *Todos) indexOf(ctx context.Context, id uuid.UUID) int {
+	current := listID(ctx)
+	for i, todo := range l.todos {
+		if todo.ID == id && todo.ListID == current {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexOfAny returns the index of the todo with the given id in any list, or -1
+func (l *Todos) indexOfAny(id uuid.UUID) int {
+	for i, todo := range l.todos {
 		if todo.ID == id {
 			return i
 		}
 	}
 	return -1
 }
+
+// listed returns the todos in the list of the context, in order
+func (l *Todos) listed(ctx context.Context) []*Todo {
+	current := listID(ctx)
+	list := make([]*Todo, 0, len(l.todos))
+	for _, todo := range l.todos {
+		if todo.ListID == current {
+			list = append(list, todo)
+		}
+	}
+	return list
+}
+
+// positions returns the indexes of the todos in the list of the context
+func (l *Todos) positions(ctx context.Context) []int {
+	current := listID(ctx)
+	var positions []int
+	for i, todo := range l.todos {
+		if todo.ListID == current {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// copies returns copies of the todos
+func copies(todos []*Todo) []*Todo {
+	list := make([]*Todo, len(todos))
+	for i, todo := range todos {
+		list[i] = copyTodo(todo)
+	}
+	return list
+}
@@ -0,0 +1,14 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/domain/repositorytest"
+)
+
+func TestTodos_Conformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) domain.TodoRepository {
+		return domain.NewTodos()
+	})
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTodos_Owners(t *testing.T) {
+	alice := ContextWithUser(context.Background(), &User{ID: uuid.New(), Username: "alice"})
+	bob := ContextWithUser(context.Background(), &User{ID: uuid.New(), Username: "bob"})
+
+	tests := map[string]struct {
+		// act is run by bob against alice's todo
+		act func(t *testing.T, list *Todos, todo *Todo)
+	}{
+		"Get": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				if got, _ := list.Get(bob, todo.ID); got != nil {
+					t.Errorf("Get() = %v, want nil", got)
+				}
+			},
+		},
+		"Update": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				if got, _ := list.Update(bob, todo.ID, true, "stolen"); got != nil {
+					t.Errorf("Update() = %v, want nil", got)
+				}
+			},
+		},
+		"Remove": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				_ = list.Remove(bob, todo.ID)
+			},
+		},
+		"Search": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				if got, _ := list.Search(bob, ""); len(got) != 1 || got[0].ListID == todo.ListID {
+					t.Errorf("Search() = %v, want only bob's todo", got)
+				}
+			},
+		},
+		"Reorder": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				if got, _ := list.Reorder(bob, []uuid.UUID{todo.ID}); len(got) != 1 || got[0].ID == todo.ID {
+					t.Errorf("Reorder() = %v, want only bob's todo", got)
+				}
+			},
+		},
+		"Export": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				data, _ := list.Export(bob, FormatJSON)
+				if got, _ := (jsonCodec{}).Decode(bytes.NewReader(data)); len(got) != 1 || got[0].ID == todo.ID {
+					t.Errorf("Export() = %s, want only bob's todo", data)
+				}
+			},
+		},
+		"ImportReplace": {
+			act: func(t *testing.T, list *Todos, todo *Todo) {
+				imported := &Todo{ID: todo.ID, Description: "stolen"}
+				if _, err := list.Import(bob, []*Todo{imported}, ImportReplace, false); err != nil {
+					t.Fatalf("Import() error = %v", err)
+				}
+				if got, _ := list.All(bob); len(got) != 1 || got[0].ID == todo.ID {
+					t.Errorf("Import() kept the ID of alice's todo")
+				}
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			list := NewTodos()
+			todo, _ := list.Add(alice, "alice's todo")
+			_, _ = list.Add(bob, "bob's todo")
+
+			tt.act(t, list, todo)
+
+			got, _ := list.All(alice)
+			if len(got) != 1 || *got[0] != *todo {
+				t.Errorf("alice's list = %v, want her todo unchanged", got)
+			}
+		})
+	}
+}
+
+func TestTodos_ConcurrentUsers(t *testing.T) {
+	const users, rounds = 8, 50
+
+	list := NewTodos()
+	var wg sync.WaitGroup
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := ContextWithUser(context.Background(), &User{ID: uuid.New(), Username: "user" + strconv.Itoa(i)})
+			for j := 0; j < rounds; j++ {
+				todo, _ := list.Add(ctx, "todo "+strconv.Itoa(j))
+				_, _ = list.Update(ctx, todo.ID, true, "done "+strconv.Itoa(j))
+				all, _ := list.All(ctx)
+				ids := make([]uuid.UUID, 0, len(all))
+				for k := len(all) - 1; k >= 0; k-- {
+					ids = append(ids, all[k].ID)
+				}
+				_, _ = list.Reorder(ctx, ids)
+				_, _ = list.Search(ctx, "done")
+				_, _ = list.Export(ctx, FormatJSON)
+				_, _ = list.Scan(ctx)
+				if j%10 == 0 {
+					_ = list.Remove(ctx, todo.ID)
+					_, _ = list.Import(ctx, []*Todo{NewTodo("imported")}, ImportMerge, false)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	all, _ := list.Scan(context.Background())
+	if len(all) != users*rounds {
+		t.Errorf("Scan() = %d todos, want %d", len(all), users*rounds)
+	}
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const todoTxtDate = "2006-01-02"
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\) `)
+	// todoTxtTag matches a key:value word along with the space before it
+	todoTxtTag = regexp.MustCompile(`(?:^|\s+)([^\s:]*):\S+`)
+)
+
+// TodoTxtTask is a single line of a todo.txt file; see https://github.com/todotxt/todo.txt
+type TodoTxtTask struct {
+	Completed      bool
+	Priority       string
+	CompletionDate time.Time
+	CreationDate   time.Time
+	// Description is the remainder of the line including any contexts, projects and tags
+	Description string
+}
+
+// ParseTodoTxt parses one line of a todo.txt file
+func ParseTodoTxt(line string) TodoTxtTask {
+	var task TodoTxtTask
+
+	rest := strings.TrimSpace(line)
+	if strings.HasPrefix(rest, "x ") {
+		task.Completed = true
+		rest = strings.TrimLeft(rest[2:], " ")
+	} else if m := todoTxtPriority.FindStringSubmatch(rest); m != nil {
+		task.Priority = m[1]
+		rest = strings.TrimLeft(rest[len(m[0]):], " ")
+	}
+
+	// a completed task may carry a completion date which must be followed by the creation date
+	first, rest := parseTodoTxtDate(rest)
+	if !first.IsZero() {
+		second, remaining := parseTodoTxtDate(rest)
+		switch {
+		case task.Completed && !second.IsZero():
+			task.CompletionDate, task.CreationDate, rest = first, second, remaining
+		case task.Completed:
+			task.CompletionDate = first
+		default:
+			task.CreationDate = first
+		}
+	}
+	task.Description = rest
+
+	return task
+}
+
+func parseTodoTxtDate(s string) (time.Time, string) {
+	if len(s) < len(todoTxtDate) || (len(s) > len(todoTxtDate) && s[len(todoTxtDate)] != ' ') {
+		return time.Time{}, s
+	}
+	date, err := time.Parse(todoTxtDate, s[:len(todoTxtDate)])
+	if err != nil {
+		return time.Time{}, s
+	}
+	return date, strings.TrimLeft(s[len(todoTxtDate):], " ")
+}
+
+// String formats the task as a todo.txt line
+func (t TodoTxtTask) String() string {
+	var parts []string
+	if t.Completed {
+		parts = append(parts, "x")
+		if !t.CompletionDate.IsZero() {
+			parts = append(parts, t.CompletionDate.Format(todoTxtDate))
+		}
+	} else if t.Priority != "" {
+		parts = append(parts, "("+t.Priority+")")
+	}
+	if !t.CreationDate.IsZero() {
+		parts = append(parts, t.CreationDate.Format(todoTxtDate))
+	}
+	if t.Description != "" {
+		parts = append(parts, t.Description)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Contexts returns the @context words in the description
+func (t TodoTxtTask) Contexts() []string {
+	return todoTxtWords(t.Description, "@")
+}
+
+// Projects returns the +project words in the description
+func (t TodoTxtTask) Projects() []string {
+	return todoTxtWords(t.Description, "+")
+}
+
+// Tags returns the key:value words in the description
+func (t TodoTxtTask) Tags() map[string]string {
+	tags := make(map[string]string)
+	for _, word := range strings.Fields(t.Description) {
+		if key, value, found := strings.Cut(word, ":"); found && isTodoTxtTag(key, value) {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// withoutTag returns the description with the last key:value word for the key removed
+func (t TodoTxtTask) withoutTag(key string) string {
+	var last []int
+	for _, match := range todoTxtTag.FindAllStringSubmatchIndex(t.Description, -1) {
+		if t.Description[match[2]:match[3]] == key {
+			last = match
+		}
+	}
+	if last == nil {
+		return t.Description
+	}
+	return strings.TrimSpace(t.Description[:last[0]] + t.Description[last[1]:])
+}
+
+func isTodoTxtTag(key, value string) bool {
+	return key != "" && value != "" && !strings.ContainsAny(key, "@+") && !strings.HasPrefix(value, "//")
+}
+
+func todoTxtWords(description, prefix string) []string {
+	var words []string
+	for _, word := range strings.Fields(description) {
+		if len(word) > len(prefix) && strings.HasPrefix(word, prefix) {
+			words = append(words, word[len(prefix):])
+		}
+	}
+	return words
+}
+
+// todoTxtCodec writes one todo.txt line per todo
+//
+// The todo id is kept in a trailing id: tag, the due date in a due: tag, and a
+// priority is kept in the description as a pri: tag. Todos do not record when they were completed, so
+// the creation date doubles as the completion date of completed todos. Dates
+// in todo.txt have day precision, so the exact creation time is also kept in a
+// created: tag, and due times other than midnight UTC are written in full.
+type todoTxtCodec struct{}
+
+func (todoTxtCodec) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (todoTxtCodec) Extension() string {
+	return "txt"
+}
+
+func (todoTxtCodec) Encode(w io.Writer, todos []*Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, todo := range todos {
+		task := TodoTxtTask{
+			Completed:    todo.Completed,
+			CreationDate: todo.CreatedAt,
+			Description:  todo.Description,
+		}
+		if todo.Completed {
+			task.CompletionDate = todo.CreatedAt
+		} else if priority := task.Tags()["pri"]; len(priority) == 1 && priority[0] >= 'A' && priority[0] <= 'Z' {
+			task.Priority = priority
+			task.Description = task.withoutTag("pri")
+		}
+		if todo.DueAt != nil {
+			task.Description += " due:" + formatTodoTxtDue(*todo.DueAt)
+		}
+		task.Description += " created:" + todo.CreatedAt.Format(time.RFC3339Nano)
+		task.Description = strings.TrimSpace(task.Description + " id:" + todo.ID.String())
+
+		if _, err := bw.WriteString(task.String() + "\n"); err != nil {
+			return ErrMarshaling{Err: err}
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return ErrMarshaling{Err: err}
+	}
+	return nil
+}
+
+func (todoTxtCodec) Decode(r io.Reader) ([]*Todo, error) {
+	todos := make([]*Todo, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		task := ParseTodoTxt(scanner.Text())
+
+		todo := &Todo{
+			Description: task.Description,
+			Completed:   task.Completed,
+			CreatedAt:   task.CreationDate,
+		}
+		// other todo.txt tools use id: tags of their own; those stay in the description
+		if id, err := uuid.Parse(task.Tags()["id"]); err == nil {
+			todo.ID = id
+			todo.Description = task.withoutTag("id")
+		}
+		if created, err := time.Parse(time.RFC3339Nano, task.Tags()["created"]); err == nil {
+			todo.CreatedAt = created
+			todo.Description = TodoTxtTask{Description: todo.Description}.withoutTag("created")
+		}
+		if due, err := parseTodoTxtDue(task.Tags()["due"]); err == nil {
+			todo.DueAt = &due
+			todo.Description = TodoTxtTask{Description: todo.Description}.withoutTag("due")
+		}
+		if task.Priority != "" {
+			todo.Description = strings.TrimSpace(todo.Description + " pri:" + task.Priority)
+		}
+		fillMissing(todo)
+		todos = append(todos, todo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrUnmarshaling{Err: err}
+	}
+	return todos, nil
+}
+
+// formatTodoTxtDue writes due dates as other todo.txt tools expect them, and any
+// other due time in full
+func formatTodoTxtDue(due time.Time) string {
+	if due.Equal(due.UTC().Truncate(24*time.Hour)) && due.Location() == time.UTC {
+		return due.Format(todoTxtDate)
+	}
+	return due.Format(time.RFC3339Nano)
+}
+
+func parseTodoTxtDue(value string) (time.Time, error) {
+	if due, err := time.Parse(todoTxtDate, value); err == nil {
+		return due, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(todoTxtDate, s)
+		return d
+	}
+	tests := map[string]struct {
+		line         string
+		want         TodoTxtTask
+		wantContexts []string
+		wantProjects []string
+		wantTags     map[string]string
+	}{
+		"Plain": {
+			line:     "Bake a cake",
+			want:     TodoTxtTask{Description: "Bake a cake"},
+			wantTags: map[string]string{},
+		},
+		"Priority": {
+			line:         "(A) 2023-06-01 Call mom @phone +family due:2023-06-05",
+			want:         TodoTxtTask{Priority: "A", CreationDate: date("2023-06-01"), Description: "Call mom @phone +family due:2023-06-05"},
+			wantContexts: []string{"phone"},
+			wantProjects: []string{"family"},
+			wantTags:     map[string]string{"due": "2023-06-05"},
+		},
+		"Completed": {
+			line:     "x 2023-06-03 2023-06-01 Bake a cake pri:B",
+			want:     TodoTxtTask{Completed: true, CompletionDate: date("2023-06-03"), CreationDate: date("2023-06-01"), Description: "Bake a cake pri:B"},
+			wantTags: map[string]string{"pri": "B"},
+		},
+		"CompletedWithoutCreationDate": {
+			line:     "x 2023-06-03 Bake a cake",
+			want:     TodoTxtTask{Completed: true, CompletionDate: date("2023-06-03"), Description: "Bake a cake"},
+			wantTags: map[string]string{},
+		},
+		"NotAPriority": {
+			line:     "(a) lowercase is not a priority see http://example.com",
+			want:     TodoTxtTask{Description: "(a) lowercase is not a priority see http://example.com"},
+			wantTags: map[string]string{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ParseTodoTxt(tt.line)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTodoTxt() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got.Contexts(), tt.wantContexts) {
+				t.Errorf("Contexts() = %v, want %v", got.Contexts(), tt.wantContexts)
+			}
+			if !reflect.DeepEqual(got.Projects(), tt.wantProjects) {
+				t.Errorf("Projects() = %v, want %v", got.Projects(), tt.wantProjects)
+			}
+			if !reflect.DeepEqual(got.Tags(), tt.wantTags) {
+				t.Errorf("Tags() = %v, want %v", got.Tags(), tt.wantTags)
+			}
+			if got.String() != tt.line {
+				t.Errorf("String() = %q, want %q", got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestTodoTxtCodec_Decode(t *testing.T) {
+	id := uuid.New()
+	tests := map[string]struct {
+		input string
+		want  []*Todo
+	}{
+		"PriorityBecomesTag": {
+			input: "(C) 2023-06-01 Call mom @phone id:" + id.String() + "\n",
+			want:  []*Todo{{ID: id, Description: "Call mom @phone pri:C"}},
+		},
+		"ForeignIDKept": {
+			input: "\nWater plants id:7 dep:3\n\n",
+			want:  []*Todo{{Description: "Water plants id:7 dep:3"}},
+		},
+		"Completed": {
+			input: "x 2023-06-03 2023-06-01 Bake a cake\n",
+			want:  []*Todo{{Description: "Bake a cake", Completed: true}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := todoTxtCodec{}.Decode(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Decode() got %d todos, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if want.ID != uuid.Nil && got[i].ID != want.ID {
+					t.Errorf("Decode() ID = %v, want %v", got[i].ID, want.ID)
+				}
+				if got[i].Description != want.Description || got[i].Completed != want.Completed {
+					t.Errorf("Decode() todo = %+v, want %+v", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownCodec_Decode(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []*Todo
+	}{
+		"Checklist": {
+			input: "# Todos\n\nSome prose.\n\n- [ ] Bake a cake\n* [X] Feed the cat\n  + [x] Nested item\n- not a task\n",
+			want: []*Todo{
+				{Description: "Bake a cake"},
+				{Description: "Feed the cat", Completed: true},
+				{Description: "Nested item", Completed: true},
+			},
+		},
+		"ForeignComment": {
+			input: "- [ ] Bake a cake <!-- for: the party -->\n",
+			want:  []*Todo{{Description: "Bake a cake <!-- for: the party -->"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := markdownCodec{}.Decode(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Decode() got %d todos, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].Description != want.Description || got[i].Completed != want.Completed {
+					t.Errorf("Decode() todo = %+v, want %+v", got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Scopes limit what an API token may do; each scope includes the ones before it
+const (
+	// ScopeRead allows a token to list, get, export and subscribe to todos
+	ScopeRead Scope = "todos:read"
+	// ScopeWrite allows a token to add, update, remove and sort todos
+	ScopeWrite Scope = "todos:write"
+	// ScopeAdmin allows a token to import todos, which can replace the whole list
+	ScopeAdmin Scope = "todos:admin"
+)
+
+const (
+	// APITokenPrefix starts every API token, so leaked tokens are easy to recognize
+	APITokenPrefix = "todo_"
+	// MaxTokenNameLength is the most characters allowed in the name of an API token
+	MaxTokenNameLength = 64
+)
+
+type (
+	// Scope names something an API token is allowed to do
+	Scope string
+
+	// APIToken lets a script act as a user with limited scopes until it expires or is revoked
+	APIToken struct {
+		ID     uuid.UUID
+		UserID uuid.UUID `json:"-" cbor:"-"`
+		Name   string
+		// Hash is the hash of the token, which itself is only shown when it is created
+		Hash      string `json:"-" cbor:"-"`
+		Scopes    []Scope
+		CreatedAt time.Time
+		ExpiresAt *time.Time `json:"ExpiresAt,omitempty"`
+		RevokedAt *time.Time `json:"RevokedAt,omitempty"`
+	}
+
+	tokenKey struct{}
+)
+
+var scopeRanks = map[Scope]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// NewAPIToken validates the name, scopes and expiry and creates a token for the user;
+// it returns the token with its secret, of which only the hash is kept
+func NewAPIToken(userID uuid.UUID, name string, scopes []Scope, expiresAt *time.Time) (*APIToken, string, error) {
+	name, nameErr := validateTokenName(name)
+	scopesErr := validateScopes(scopes)
+	var expiresErr error
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		expiresErr = fieldError(FieldExpiresAt, "must be in the future")
+	}
+	if err := joinValidation(nameErr, scopesErr, expiresErr); err != nil {
+		return nil, "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return &APIToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Hash:      HashToken(token),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}, token, nil
+}
+
+// Active reports whether the token can still be used
+func (t *APIToken) Active() bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || time.Now().Before(*t.ExpiresAt))
+}
+
+// Allows reports whether the token has the scope, or one that includes it
+func (t *APIToken) Allows(scope Scope) bool {
+	for _, granted := range t.Scopes {
+		if scopeRanks[granted] >= scopeRanks[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextWithToken returns a context for requests made with the API token
+func ContextWithToken(ctx context.Context, token *APIToken) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext returns the API token a request was made with, or nil when it was
+// made with a session
+func TokenFromContext(ctx context.Context) *APIToken {
+	token, _ := ctx.Value(tokenKey{}).(*APIToken)
+	return token
+}
+
+func validateTokenName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch length := utf8.RuneCountInString(name); {
+	case length == 0:
+		return "", fieldError(FieldName, "must not be empty")
+	case length > MaxTokenNameLength:
+		return "", fieldError(FieldName, "must be at most "+strconv.Itoa(MaxTokenNameLength)+" characters")
+	}
+	return name, nil
+}
+
+func validateScopes(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return fieldError(FieldScopes, "must not be empty")
+	}
+	for _, scope := range scopes {
+		if _, known := scopeRanks[scope]; !known {
+			return fieldError(FieldScopes, "must be "+string(ScopeRead)+", "+string(ScopeWrite)+" or "+string(ScopeAdmin))
+		}
+	}
+	return nil
+}
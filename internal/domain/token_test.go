@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAPIToken(t *testing.T) {
+	var past = time.Now().Add(-time.Hour)
+	var future = time.Now().Add(time.Hour)
+	type args struct {
+		name      string
+		scopes    []Scope
+		expiresAt *time.Time
+	}
+	tests := map[string]struct {
+		args       args
+		wantFields map[string]string
+	}{
+		"Valid": {
+			args: args{name: " deploy ", scopes: []Scope{ScopeRead}, expiresAt: &future},
+		},
+		"NeverExpires": {
+			args: args{name: "deploy", scopes: []Scope{ScopeAdmin}},
+		},
+		"EmptyName": {
+			args:       args{name: " ", scopes: []Scope{ScopeRead}},
+			wantFields: map[string]string{FieldName: "must not be empty"},
+		},
+		"NoScopes": {
+			args:       args{name: "deploy"},
+			wantFields: map[string]string{FieldScopes: "must not be empty"},
+		},
+		"UnknownScope": {
+			args:       args{name: "deploy", scopes: []Scope{ScopeRead, "todos:everything"}},
+			wantFields: map[string]string{FieldScopes: "must be todos:read, todos:write or todos:admin"},
+		},
+		"Expired": {
+			args:       args{name: "deploy", scopes: []Scope{ScopeRead}, expiresAt: &past},
+			wantFields: map[string]string{FieldExpiresAt: "must be in the future"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, secret, err := NewAPIToken(uuid.New(), tt.args.name, tt.args.scopes, tt.args.expiresAt)
+			if tt.wantFields != nil {
+				var validationErr ErrValidation
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("NewAPIToken() error = %v, want ErrValidation", err)
+				}
+				for field, reason := range tt.wantFields {
+					if validationErr.Field(field) != reason {
+						t.Errorf("NewAPIToken() %s error = %q, want %q", field, validationErr.Field(field), reason)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewAPIToken() error = %v", err)
+			}
+			if got.Name != strings.TrimSpace(tt.args.name) {
+				t.Errorf("NewAPIToken() Name = %q, want %q", got.Name, strings.TrimSpace(tt.args.name))
+			}
+			if !strings.HasPrefix(secret, APITokenPrefix) || got.Hash != HashToken(secret) {
+				t.Errorf("NewAPIToken() secret = %q, Hash = %q, want a prefixed secret and its hash", secret, got.Hash)
+			}
+			if !got.Active() {
+				t.Errorf("NewAPIToken() is not active")
+			}
+		})
+	}
+}
+
+func TestAPIToken_Allows(t *testing.T) {
+	tests := map[string]struct {
+		scopes []Scope
+		want   map[Scope]bool
+	}{
+		"Read": {
+			scopes: []Scope{ScopeRead},
+			want:   map[Scope]bool{ScopeRead: true, ScopeWrite: false, ScopeAdmin: false},
+		},
+		"Write": {
+			scopes: []Scope{ScopeWrite},
+			want:   map[Scope]bool{ScopeRead: true, ScopeWrite: true, ScopeAdmin: false},
+		},
+		"Admin": {
+			scopes: []Scope{ScopeAdmin},
+			want:   map[Scope]bool{ScopeRead: true, ScopeWrite: true, ScopeAdmin: true},
+		},
+		"Unknown": {
+			scopes: []Scope{"todos:everything"},
+			want:   map[Scope]bool{ScopeRead: false, ScopeWrite: false, ScopeAdmin: false},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			token := &APIToken{Scopes: tt.scopes}
+			for scope, want := range tt.want {
+				if got := token.Allows(scope); got != want {
+					t.Errorf("Allows(%s) = %v, want %v", scope, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTokens_Revoke(t *testing.T) {
+	ctx := context.Background()
+	tokens := NewTokens()
+	alice, bob := uuid.New(), uuid.New()
+	token, secret, _ := NewAPIToken(alice, "deploy", []Scope{ScopeRead}, nil)
+	_ = tokens.Create(ctx, token)
+
+	if got, _ := tokens.Revoke(ctx, bob, token.ID); got != nil {
+		t.Errorf("Revoke() by another user = %v, want nil", got)
+	}
+	if got, _ := tokens.ByHash(ctx, HashToken(secret)); got == nil || !got.Active() {
+		t.Errorf("ByHash() = %v, want the active token", got)
+	}
+
+	if got, _ := tokens.Revoke(ctx, alice, token.ID); got == nil || got.Active() {
+		t.Errorf("Revoke() = %v, want the revoked token", got)
+	}
+	if got, _ := tokens.ByHash(ctx, HashToken(secret)); got == nil || got.Active() {
+		t.Errorf("ByHash() after Revoke() = %v, want the revoked token", got)
+	}
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordCost is the bcrypt cost of new password hashes
+var PasswordCost = bcrypt.DefaultCost
+
+type (
+	// User owns a list of todos
+	User struct {
+		ID       uuid.UUID
+		Username string
+		// PasswordHash is the bcrypt hash of the password; it never leaves the server
+		PasswordHash []byte `json:"-" cbor:"-"`
+		CreatedAt    time.Time
+	}
+
+	// Session lets the holder of its token act as a user until it expires
+	Session struct {
+		// ID is the hash of the token, so a stored session cannot be used to sign in
+		ID        string
+		UserID    uuid.UUID
+		ExpiresAt time.Time
+	}
+
+	userKey struct{}
+)
+
+// dummyHash is compared against when a username is unknown, so a failed login takes
+// as long whether or not the user exists; it is made on first use, at the cost of the
+// password hashes of that time
+var (
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+// NewUser validates the username and password and creates a user with the password hashed
+func NewUser(username, password string) (*User, error) {
+	username, usernameErr := ValidateUsername(username)
+	passwordErr := ValidatePassword(password)
+	if err := joinValidation(usernameErr, passwordErr); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost)
+	if err != nil {
+		return nil, err
+	}
+	return &User{
+		ID:           uuid.New(),
+		Username:     username,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// CheckPassword reports whether the password is the user's; a nil user never matches
+func (u *User) CheckPassword(password string) bool {
+	if u == nil {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), PasswordCost)
+		})
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)) == nil
+}
+
+// NewSession creates a session for the user and returns it with the token that
+// identifies it; only the hash of the token is kept in the session
+func NewSession(userID uuid.UUID, ttl time.Duration) (*Session, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	return &Session{
+		ID:        HashToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl),
+	}, token, nil
+}
+
+// HashToken returns the ID of the session a token belongs to
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired reports whether the session can no longer be used
+func (s *Session) Expired() bool {
+	return !time.Now().Before(s.ExpiresAt)
+}
+
+// ContextWithUser returns a context for requests made by the user
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user making the request, or nil
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userKey{}).(*User)
+	return user
+}
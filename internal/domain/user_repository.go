@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository interface {
+	// Create stores a new user; it returns an ErrValidation when the username is taken
+	Create(ctx context.Context, user *User) error
+	Get(ctx context.Context, id uuid.UUID) (*User, error)
+	ByUsername(ctx context.Context, username string) (*User, error)
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	Get(ctx context.Context, id string) (*Session, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type TokenRepository interface {
+	Create(ctx context.Context, token *APIToken) error
+	// ByHash returns the token whose secret has the hash, or nil
+	ByHash(ctx context.Context, hash string) (*APIToken, error)
+	// List returns the tokens of the user, oldest first
+	List(ctx context.Context, userID uuid.UUID) ([]*APIToken, error)
+	// Revoke marks the user's token as revoked and returns it, or nil when the user
+	// has no token with the id
+	Revoke(ctx context.Context, userID, id uuid.UUID) (*APIToken, error)
+}
+
+type MemberRepository interface {
+	// Add gives the user of the membership its role in the list, replacing any role
+	// they had
+	Add(ctx context.Context, membership *Membership) error
+	// Get returns the membership of the user in the list, or nil
+	Get(ctx context.Context, listID, userID uuid.UUID) (*Membership, error)
+	// ByList returns the memberships of the list, in the order they were added
+	ByList(ctx context.Context, listID uuid.UUID) ([]*Membership, error)
+	// ByUser returns the memberships of the user, in the order they were added
+	ByUser(ctx context.Context, userID uuid.UUID) ([]*Membership, error)
+	Remove(ctx context.Context, listID, userID uuid.UUID) error
+}
+
+// Accounts registers users and signs them in and out from a client of the server;
+// the session it starts is carried by the credentials of later requests
+type Accounts interface {
+	Register(ctx context.Context, username, password string) (*User, error)
+	Login(ctx context.Context, username, password string) (*User, error)
+	Logout(ctx context.Context) error
+	// CSRFToken returns the CSRF token of the session
+	CSRFToken(ctx context.Context) (string, error)
+}
+
+// Sharing lists the todo lists a user can reach and shares the list of the context,
+// see ContextWithList, with other users
+type Sharing interface {
+	// Lists returns the memberships of the user, starting with their own list
+	Lists(ctx context.Context) ([]*Membership, error)
+	// Members returns the memberships of the list, starting with the user it was
+	// created for
+	Members(ctx context.Context) ([]*Membership, error)
+	// Invite gives the user with the username the role in the list
+	Invite(ctx context.Context, username string, role Role) (*Membership, error)
+	// RemoveMember takes the user out of the list; members may always leave a list
+	RemoveMember(ctx context.Context, userID uuid.UUID) error
+}
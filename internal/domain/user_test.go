@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMain(m *testing.M) {
+	// keep hashing passwords cheap in tests
+	PasswordCost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+func TestNewUser(t *testing.T) {
+	type args struct {
+		username string
+		password string
+	}
+	tests := map[string]struct {
+		args       args
+		wantName   string
+		wantFields map[string]string
+	}{
+		"Valid": {
+			args:     args{username: " Alice ", password: "correct horse"},
+			wantName: "alice",
+		},
+		"InvalidUsername": {
+			args:       args{username: "al", password: "correct horse"},
+			wantFields: map[string]string{FieldUsername: "must be at least 3 characters"},
+		},
+		"InvalidPassword": {
+			args:       args{username: "alice", password: "short"},
+			wantFields: map[string]string{FieldPassword: "must be at least 8 characters"},
+		},
+		"BothInvalid": {
+			args: args{username: "alice!", password: "short"},
+			wantFields: map[string]string{
+				FieldUsername: "may only contain letters, digits, '.', '-' and '_'",
+				FieldPassword: "must be at least 8 characters",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewUser(tt.args.username, tt.args.password)
+			if tt.wantFields != nil {
+				var validationErr ErrValidation
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("NewUser() error = %v, want ErrValidation", err)
+				}
+				for field, reason := range tt.wantFields {
+					if validationErr.Field(field) != reason {
+						t.Errorf("NewUser() %s error = %q, want %q", field, validationErr.Field(field), reason)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewUser() error = %v", err)
+			}
+			if got.Username != tt.wantName {
+				t.Errorf("NewUser() Username = %q, want %q", got.Username, tt.wantName)
+			}
+			if string(got.PasswordHash) == tt.args.password {
+				t.Errorf("NewUser() stored the password unhashed")
+			}
+			if !got.CheckPassword(tt.args.password) || got.CheckPassword("wrong password") {
+				t.Errorf("NewUser() hash does not match only the password")
+			}
+		})
+	}
+}
+
+func TestUser_CheckPassword_NilUser(t *testing.T) {
+	var user *User
+	if user.CheckPassword("correct horse") {
+		t.Errorf("CheckPassword() = true for a nil user")
+	}
+	if cost, err := bcrypt.Cost(dummyHash); err != nil || cost != PasswordCost {
+		t.Errorf("dummy hash cost = %d, %v, want %d", cost, err, PasswordCost)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	user, err := NewUser("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	sessions := NewSessions()
+
+	session, token, err := NewSession(user.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	if session.ID == token || session.ID != HashToken(token) {
+		t.Errorf("NewSession() ID = %q, want the hash of the token", session.ID)
+	}
+	_ = sessions.Create(ctx, session)
+	if got, _ := sessions.Get(ctx, HashToken(token)); got != session {
+		t.Errorf("Sessions.Get() = %v, want %v", got, session)
+	}
+
+	expired, expiredToken, _ := NewSession(user.ID, -time.Second)
+	_ = sessions.Create(ctx, expired)
+	if got, _ := sessions.Get(ctx, HashToken(expiredToken)); got != nil {
+		t.Errorf("Sessions.Get() = %v for an expired session, want nil", got)
+	}
+
+	_ = sessions.Delete(ctx, session.ID)
+	if got, _ := sessions.Get(ctx, HashToken(token)); got != nil {
+		t.Errorf("Sessions.Get() = %v after Delete, want nil", got)
+	}
+}
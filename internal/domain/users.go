@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Users is an in-memory UserRepository
+	Users struct {
+		mu         sync.RWMutex
+		byID       map[uuid.UUID]*User
+		byUsername map[string]*User
+	}
+
+	// Sessions is an in-memory SessionRepository
+	Sessions struct {
+		mu       sync.Mutex
+		sessions map[string]*Session
+	}
+
+	// Members is an in-memory MemberRepository
+	Members struct {
+		mu          sync.RWMutex
+		memberships []*Membership
+	}
+
+	// Tokens is an in-memory TokenRepository
+	Tokens struct {
+		mu     sync.RWMutex
+		tokens []*APIToken
+	}
+)
+
+var (
+	_ UserRepository    = (*Users)(nil)
+	_ SessionRepository = (*Sessions)(nil)
+	_ TokenRepository   = (*Tokens)(nil)
+	_ MemberRepository  = (*Members)(nil)
+)
+
+// NewUsers creates an empty list of users
+func NewUsers() *Users {
+	return &Users{
+		byID:       make(map[uuid.UUID]*User),
+		byUsername: make(map[string]*User),
+	}
+}
+
+// Create adds the user unless the username is taken
+func (u *Users) Create(_ context.Context, user *User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, exists := u.byUsername[user.Username]; exists {
+		return fieldError(FieldUsername, "is already taken")
+	}
+	u.byID[user.ID] = user
+	u.byUsername[user.Username] = user
+	return nil
+}
+
+// Get returns a user by id
+func (u *Users) Get(_ context.Context, id uuid.UUID) (*User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.byID[id], nil
+}
+
+// ByUsername returns a user by username
+func (u *Users) ByUsername(_ context.Context, username string) (*User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.byUsername[username], nil
+}
+
+// NewSessions creates an empty list of sessions
+func NewSessions() *Sessions {
+	return &Sessions{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Create adds the session
+func (s *Sessions) Create(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	return nil
+}
+
+// Get returns a session by id; expired sessions are removed and not returned
+func (s *Sessions) Get(_ context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, nil
+	}
+	if session.Expired() {
+		delete(s.sessions, id)
+		return nil, nil
+	}
+	return session, nil
+}
+
+// Delete removes a session by id
+func (s *Sessions) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// NewTokens creates an empty list of API tokens
+func NewTokens() *Tokens {
+	return &Tokens{}
+}
+
+// Create adds the token
+func (t *Tokens) Create(_ context.Context, token *APIToken) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens = append(t.tokens, token)
+	return nil
+}
+
+// ByHash returns a token by the hash of its secret
+func (t *Tokens) ByHash(_ context.Context, hash string) (*APIToken, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, token := range t.tokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the tokens of the user
+func (t *Tokens) List(_ context.Context, userID uuid.UUID) ([]*APIToken, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	list := make([]*APIToken, 0)
+	for _, token := range t.tokens {
+		if token.UserID == userID {
+			list = append(list, token)
+		}
+	}
+	return list, nil
+}
+
+// Revoke marks a token of the user as revoked
+func (t *Tokens) Revoke(_ context.Context, userID, id uuid.UUID) (*APIToken, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, token := range t.tokens {
+		if token.ID == id && token.UserID == userID {
+			if token.RevokedAt == nil {
+				// the token is replaced rather than changed, as callers may still hold it
+				revoked := *token
+				now := time.Now()
+				revoked.RevokedAt = &now
+				t.tokens[i] = &revoked
+			}
+			return t.tokens[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// NewMembers creates an empty list of memberships
+func NewMembers() *Members {
+	return &Members{}
+}
+
+// Add adds the membership, or replaces the role of an existing one
+func (m *Members) Add(_ context.Context, membership *Membership) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.memberships {
+		if existing.ListID == membership.ListID && existing.UserID == membership.UserID {
+			m.memberships[i] = membership
+			return nil
+		}
+	}
+	m.memberships = append(m.memberships, membership)
+	return nil
+}
+
+// Get returns the membership of a user in a list
+func (m *Members) Get(_ context.Context, listID, userID uuid.UUID) (*Membership, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, membership := range m.memberships {
+		if membership.ListID == listID && membership.UserID == userID {
+			return membership, nil
+		}
+	}
+	return nil, nil
+}
+
+// ByList returns the memberships of a list
+func (m *Members) ByList(_ context.Context, listID uuid.UUID) ([]*Membership, error) {
+	return m.filter(func(membership *Membership) bool { return membership.ListID == listID }), nil
+}
+
+// ByUser returns the memberships of a user
+func (m *Members) ByUser(_ context.Context, userID uuid.UUID) ([]*Membership, error) {
+	return m.filter(func(membership *Membership) bool { return membership.UserID == userID }), nil
+}
+
+// Remove removes the membership of a user in a list
+func (m *Members) Remove(_ context.Context, listID, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, membership := range m.memberships {
+		if membership.ListID == listID && membership.UserID == userID {
+			m.memberships = append(m.memberships[:i], m.memberships[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Members) filter(keep func(membership *Membership) bool) []*Membership {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]*Membership, 0)
+	for _, membership := range m.memberships {
+		if keep(membership) {
+			list = append(list, membership)
+		}
+	}
+	return list
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Names used to report validation failures
+const (
+	FieldDescription = "description"
+	FieldUsername    = "username"
+	FieldPassword    = "password"
+	FieldName        = "name"
+	FieldScopes      = "scopes"
+	FieldExpiresAt   = "expires_at"
+	FieldRole        = "role"
+)
+
+const (
+	// MinDescriptionLength is the fewest characters allowed in a description
+	MinDescriptionLength = 1
+	// MaxDescriptionLength is the most characters allowed in a description
+	MaxDescriptionLength = 200
+	// MinUsernameLength is the fewest characters allowed in a username
+	MinUsernameLength = 3
+	// MaxUsernameLength is the most characters allowed in a username
+	MaxUsernameLength = 32
+	// MinPasswordLength is the fewest bytes allowed in a password
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bytes allowed in a password, which bcrypt limits
+	MaxPasswordLength = 72
+)
+
+// ValidateDescription returns the trimmed and NFC normalized description or an ErrValidation
+func ValidateDescription(description string) (string, error) {
+	if !utf8.ValidString(description) {
+		return "", descriptionError("must be valid UTF-8")
+	}
+
+	description = strings.TrimSpace(norm.NFC.String(description))
+
+	switch length := utf8.RuneCountInString(description); {
+	case length < MinDescriptionLength:
+		return "", descriptionError("must not be empty")
+	case length > MaxDescriptionLength:
+		return "", descriptionError("must be at most " + strconv.Itoa(MaxDescriptionLength) + " characters")
+	}
+
+	if strings.IndexFunc(description, unicode.IsControl) != -1 {
+		return "", descriptionError("must not contain control characters")
+	}
+
+	return description, nil
+}
+
+func descriptionError(reason string) ErrValidation {
+	return fieldError(FieldDescription, reason)
+}
+
+// ValidateUsername returns the trimmed and lowercased username or an ErrValidation;
+// usernames are made of ASCII letters, digits, '.', '-' and '_'
+func ValidateUsername(username string) (string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+
+	switch length := len(username); {
+	case length < MinUsernameLength:
+		return "", fieldError(FieldUsername, "must be at least "+strconv.Itoa(MinUsernameLength)+" characters")
+	case length > MaxUsernameLength:
+		return "", fieldError(FieldUsername, "must be at most "+strconv.Itoa(MaxUsernameLength)+" characters")
+	}
+
+	for _, r := range username {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' && r != '_' {
+			return "", fieldError(FieldUsername, "may only contain letters, digits, '.', '-' and '_'")
+		}
+	}
+
+	return username, nil
+}
+
+// ValidatePassword returns an ErrValidation when the password is too short or too long
+func ValidatePassword(password string) error {
+	switch length := len(password); {
+	case length < MinPasswordLength:
+		return fieldError(FieldPassword, "must be at least "+strconv.Itoa(MinPasswordLength)+" characters")
+	case length > MaxPasswordLength:
+		return fieldError(FieldPassword, "must be at most "+strconv.Itoa(MaxPasswordLength)+" bytes")
+	}
+	return nil
+}
+
+func fieldError(field, reason string) ErrValidation {
+	return ErrValidation{Fields: map[string]string{field: reason}}
+}
+
+// joinValidation merges the fields of the validation errors, ignoring nil ones
+func joinValidation(errs ...error) error {
+	var joined = ErrValidation{Fields: map[string]string{}}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		for field, reason := range err.(ErrValidation).Fields {
+			joined.Fields[field] = reason
+		}
+	}
+	if len(joined.Fields) == 0 {
+		return nil
+	}
+	return joined
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateDescription(t *testing.T) {
+	type args struct {
+		description string
+	}
+	tests := map[string]struct {
+		args      args
+		want      string
+		wantField string
+	}{
+		"Valid": {
+			args: args{
+				description: "Bake a cake",
+			},
+			want: "Bake a cake",
+		},
+		"Trimmed": {
+			args: args{
+				description: " \t Bake a cake \n",
+			},
+			want: "Bake a cake",
+		},
+		"Normalized": {
+			args: args{
+				description: "Cafe\u0301",
+			},
+			want: "Caf\u00e9",
+		},
+		"Empty": {
+			args: args{
+				description: "",
+			},
+			wantField: "must not be empty",
+		},
+		"WhitespaceOnly": {
+			args: args{
+				description: " \t\n ",
+			},
+			wantField: "must not be empty",
+		},
+		"MaxLength": {
+			args: args{
+				description: strings.Repeat("é", MaxDescriptionLength),
+			},
+			want: strings.Repeat("é", MaxDescriptionLength),
+		},
+		"TooLong": {
+			args: args{
+				description: strings.Repeat("a", MaxDescriptionLength+1),
+			},
+			wantField: "must be at most 200 characters",
+		},
+		"ControlCharacter": {
+			args: args{
+				description: "Bake\x07a cake",
+			},
+			wantField: "must not contain control characters",
+		},
+		"InnerNewline": {
+			args: args{
+				description: "Bake\na cake",
+			},
+			wantField: "must not contain control characters",
+		},
+		"InvalidUTF8": {
+			args: args{
+				description: "Bake \xff cake",
+			},
+			wantField: "must be valid UTF-8",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ValidateDescription(tt.args.description)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateDescription() error = %v, want nil", err)
+				}
+				if got != tt.want {
+					t.Errorf("ValidateDescription() got = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			var validationErr ErrValidation
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateDescription() error = %v, want ErrValidation", err)
+			}
+			if validationErr.Field(FieldDescription) != tt.wantField {
+				t.Errorf("ValidateDescription() field error = %q, want %q", validationErr.Field(FieldDescription), tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	tests := map[string]struct {
+		username  string
+		want      string
+		wantField string
+	}{
+		"Valid":        {username: "alice.b-c_1", want: "alice.b-c_1"},
+		"Lowercased":   {username: " Alice ", want: "alice"},
+		"TooShort":     {username: "al", wantField: "must be at least 3 characters"},
+		"TooLong":      {username: strings.Repeat("a", MaxUsernameLength+1), wantField: "must be at most 32 characters"},
+		"InvalidRunes": {username: "alice smith", wantField: "may only contain letters, digits, '.', '-' and '_'"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ValidateUsername(tt.username)
+			var validationErr ErrValidation
+			if errors.As(err, &validationErr) != (tt.wantField != "") || validationErr.Field(FieldUsername) != tt.wantField {
+				t.Fatalf("ValidateUsername() error = %v, want %q", err, tt.wantField)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateUsername() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := map[string]struct {
+		password  string
+		wantField string
+	}{
+		"Valid":    {password: "correct horse"},
+		"TooShort": {password: "short", wantField: "must be at least 8 characters"},
+		"TooLong":  {password: strings.Repeat("a", MaxPasswordLength+1), wantField: "must be at most 72 bytes"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePassword(tt.password)
+			var validationErr ErrValidation
+			if errors.As(err, &validationErr) != (tt.wantField != "") || validationErr.Field(FieldPassword) != tt.wantField {
+				t.Errorf("ValidatePassword() error = %v, want %q", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// APIVersion is the version of the API the server exposes and the client expects;
+// the major version changes whenever a change would break an older client
+const APIVersion = "1.0"
+
+// APIVersionHeader carries the API version a client speaks on its requests, and the
+// one the server speaks on its refusals of incompatible clients
+const APIVersionHeader = "X-API-Version"
+
+// Version describes a build of the server
+type Version struct {
+	Module   string
+	Version  string
+	Revision string
+	Time     *time.Time `json:",omitempty"`
+	Modified bool
+	API      string
+	// Client is the content hash of the client.wasm the server serves, which tells the
+	// proxies of older builds that a new one is available
+	Client string `json:",omitempty"`
+}
+
+// ReadVersion returns the version of the running binary from its embedded build information
+func ReadVersion() Version {
+	version := Version{API: APIVersion}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	version.Module = info.Main.Path
+	version.Version = info.Main.Version
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version.Revision = setting.Value
+		case "vcs.time":
+			if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+				version.Time = &t
+			}
+		case "vcs.modified":
+			version.Modified = setting.Value == "true"
+		}
+	}
+	return version
+}
+
+// CompatibleAPI reports whether a client built for APIVersion can talk to a server
+// exposing the api version; versions are compatible when their major versions match
+func CompatibleAPI(api string) bool {
+	major, _, _ := strings.Cut(api, ".")
+	want, _, _ := strings.Cut(APIVersion, ".")
+	return major == want
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestCompatibleAPI(t *testing.T) {
+	tests := map[string]struct {
+		api  string
+		want bool
+	}{
+		"Same":        {api: APIVersion, want: true},
+		"NewerMinor":  {api: "1.9", want: true},
+		"NewerMajor":  {api: "2.0", want: false},
+		"MajorOnly":   {api: "1", want: true},
+		"Missing":     {api: "", want: false},
+		"NotAVersion": {api: "latest", want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := CompatibleAPI(tt.api); got != tt.want {
+				t.Errorf("CompatibleAPI(%q) = %v, want %v", tt.api, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVersion(t *testing.T) {
+	if got := ReadVersion(); got.API != APIVersion {
+		t.Errorf("ReadVersion().API = %q, want %q", got.API, APIVersion)
+	}
+}
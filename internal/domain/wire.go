@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// wireFormat encodes the request and response bodies exchanged with the REST API
+type wireFormat struct {
+	// format names the TodoCodec used for lists of todos
+	format    string
+	mediaType string
+	marshal   func(v any) ([]byte, error)
+	decode    func(r io.Reader, v any) error
+}
+
+var (
+	jsonWire = wireFormat{
+		format:    FormatJSON,
+		mediaType: "application/json",
+		marshal:   json.Marshal,
+		decode: func(r io.Reader, v any) error {
+			return json.NewDecoder(r).Decode(v)
+		},
+	}
+	cborWire = wireFormat{
+		format:    FormatCBOR,
+		mediaType: MediaTypeCBOR,
+		marshal:   cbor.Marshal,
+		decode: func(r io.Reader, v any) error {
+			return cbor.NewDecoder(r).Decode(v)
+		},
+	}
+)
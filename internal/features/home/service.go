@@ -23,6 +23,6 @@ func NewService(todos domain.TodoRepository) Service {
 	}
 }
 
-func (s service) List(context.Context) ([]*domain.Todo, error) {
-	return s.todos.All()
+func (s service) List(ctx context.Context) ([]*domain.Todo, error) {
+	return s.todos.All(ctx)
 }
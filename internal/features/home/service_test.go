@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
 )
@@ -47,7 +48,7 @@ func Test_service_List(t *testing.T) {
 				ctx: context.Background(),
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().All().Return([]*domain.Todo{}, nil)
+				f.todos.EXPECT().All(mock.Anything).Return([]*domain.Todo{}, nil)
 			},
 			want:    []*domain.Todo{},
 			wantErr: false,
@@ -57,7 +58,7 @@ func Test_service_List(t *testing.T) {
 				ctx: context.Background(),
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().All().Return([]*domain.Todo{firstTodo, secondTodo, thirdTodo}, nil)
+				f.todos.EXPECT().All(mock.Anything).Return([]*domain.Todo{firstTodo, secondTodo, thirdTodo}, nil)
 			},
 			want:    []*domain.Todo{firstTodo, secondTodo, thirdTodo},
 			wantErr: false,
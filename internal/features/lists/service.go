@@ -0,0 +1,162 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+type (
+	// Service shares todo lists between users
+	Service interface {
+		// Lists returns the memberships of the user of the context, starting with their
+		// own list
+		Lists(ctx context.Context) ([]*domain.Membership, error)
+		// Members returns the memberships of the list of the context, starting with the
+		// user it was created for
+		Members(ctx context.Context) ([]*domain.Membership, error)
+		// Invite gives the user with the username the role in the list of the context
+		Invite(ctx context.Context, username string, role domain.Role) (*domain.Membership, error)
+		// RemoveMember takes the user out of the list of the context; members may
+		// always leave a list
+		RemoveMember(ctx context.Context, userID uuid.UUID) error
+	}
+
+	service struct {
+		members domain.MemberRepository
+		users   domain.UserRepository
+	}
+)
+
+var _ domain.Sharing = (Service)(nil)
+
+func NewService(members domain.MemberRepository, users domain.UserRepository) Service {
+	return &service{
+		members: members,
+		users:   users,
+	}
+}
+
+func (s service) Lists(ctx context.Context) ([]*domain.Membership, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil {
+		return nil, domain.ErrUnauthenticated{}
+	}
+	shared, err := s.members.ByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*domain.Membership{ownMembership(user)}, shared...), nil
+}
+
+func (s service) Members(ctx context.Context) ([]*domain.Membership, error) {
+	listID, err := s.authorize(ctx, domain.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := s.users.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.members.ByList(ctx, listID)
+	if err != nil || creator == nil {
+		return members, err
+	}
+	return append([]*domain.Membership{ownMembership(creator)}, members...), nil
+}
+
+func (s service) Invite(ctx context.Context, username string, role domain.Role) (*domain.Membership, error) {
+	listID, err := s.authorize(ctx, domain.PermissionShare)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = domain.ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	username, _ = domain.ValidateUsername(username)
+	invited, err := s.users.ByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case invited == nil:
+		return nil, domain.ErrValidation{Fields: map[string]string{domain.FieldUsername: "is not a user"}}
+	case invited.ID == listID:
+		return nil, domain.ErrValidation{Fields: map[string]string{domain.FieldUsername: "already owns this list"}}
+	case invited.ID == domain.UserFromContext(ctx).ID:
+		return nil, domain.ErrValidation{Fields: map[string]string{domain.FieldUsername: "cannot change their own role"}}
+	}
+	creator, err := s.users.Get(ctx, listID)
+	if err != nil || creator == nil {
+		return nil, err
+	}
+
+	membership := &domain.Membership{
+		ListID:   listID,
+		ListName: creator.Username,
+		UserID:   invited.ID,
+		Username: invited.Username,
+		Role:     role,
+	}
+	if err = s.members.Add(ctx, membership); err != nil {
+		return nil, err
+	}
+	log.Ctx(ctx).Info().Stringer("ListID", listID).Stringer("UserID", invited.ID).Str("Role", string(role)).Msg("shared list")
+	return membership, nil
+}
+
+func (s service) RemoveMember(ctx context.Context, userID uuid.UUID) error {
+	listID, err := s.authorize(ctx, domain.PermissionView)
+	if err != nil {
+		return err
+	}
+	// anyone may leave, but only those who may share the list may remove others
+	if user := domain.UserFromContext(ctx); user.ID != userID {
+		if _, err = s.authorize(ctx, domain.PermissionShare); err != nil {
+			return err
+		}
+	}
+	if userID == listID {
+		return domain.ErrValidation{Fields: map[string]string{domain.FieldUsername: "owns this list"}}
+	}
+	if err = s.members.Remove(ctx, listID, userID); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().Stringer("ListID", listID).Stringer("UserID", userID).Msg("removed list member")
+	return nil
+}
+
+// authorize returns the list of the context, or an ErrPermissionDenied when the role
+// of the user in it does not grant the permission
+func (s service) authorize(ctx context.Context, permission domain.Permission) (uuid.UUID, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil {
+		return uuid.Nil, domain.ErrUnauthenticated{}
+	}
+	listID := user.ID
+	if selected, ok := domain.ListFromContext(ctx); ok {
+		listID = selected
+	}
+	role, err := domain.RoleIn(ctx, s.members)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !role.Allows(permission) {
+		return uuid.Nil, domain.ErrPermissionDenied{Permission: permission}
+	}
+	return listID, nil
+}
+
+// ownMembership is the membership a user has in the list created for them
+func ownMembership(user *domain.User) *domain.Membership {
+	return &domain.Membership{
+		ListID:   user.ID,
+		ListName: user.Username,
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     domain.RoleOwner,
+	}
+}
@@ -0,0 +1,275 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+var (
+	alice = &domain.User{ID: uuid.New(), Username: "alice"}
+	bob   = &domain.User{ID: uuid.New(), Username: "bob"}
+	carol = &domain.User{ID: uuid.New(), Username: "carol"}
+)
+
+// inList returns a context for the user acting on alice's list
+func inList(user *domain.User) context.Context {
+	return domain.ContextWithList(domain.ContextWithUser(context.Background(), user), alice.ID)
+}
+
+func Test_service_Lists(t *testing.T) {
+	type fields struct {
+		members *domain.MockMemberRepository
+		users   *domain.MockUserRepository
+	}
+	shared := &domain.Membership{ListID: alice.ID, ListName: "alice", UserID: bob.ID, Username: "bob", Role: domain.RoleViewer}
+	tests := map[string]struct {
+		ctx     context.Context
+		mock    func(f fields)
+		want    []uuid.UUID
+		wantErr error
+	}{
+		"Own and shared": {
+			ctx: domain.ContextWithUser(context.Background(), bob),
+			mock: func(f fields) {
+				f.members.EXPECT().ByUser(mock.Anything, bob.ID).Return([]*domain.Membership{shared}, nil)
+			},
+			want: []uuid.UUID{bob.ID, alice.ID},
+		},
+		"Anonymous": {
+			ctx:     context.Background(),
+			wantErr: domain.ErrUnauthenticated{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				members: domain.NewMockMemberRepository(t),
+				users:   domain.NewMockUserRepository(t),
+			}
+			s := NewService(f.members, f.users)
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			got, err := s.Lists(tt.ctx)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Lists() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lists() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lists() = %d lists, want %d", len(got), len(tt.want))
+			}
+			for i, listID := range tt.want {
+				if got[i].ListID != listID {
+					t.Errorf("Lists()[%d].ListID = %s, want %s", i, got[i].ListID, listID)
+				}
+			}
+			if got[0].Role != domain.RoleOwner {
+				t.Errorf("Lists()[0].Role = %s, want %s", got[0].Role, domain.RoleOwner)
+			}
+		})
+	}
+}
+
+func Test_service_Invite(t *testing.T) {
+	type fields struct {
+		members *domain.MockMemberRepository
+		users   *domain.MockUserRepository
+	}
+	tests := map[string]struct {
+		ctx      context.Context
+		username string
+		role     domain.Role
+		mock     func(f fields)
+		wantErr  error
+	}{
+		"Creator": {
+			ctx:      inList(alice),
+			username: "Bob",
+			role:     domain.RoleEditor,
+			mock: func(f fields) {
+				f.users.EXPECT().ByUsername(mock.Anything, "bob").Return(bob, nil)
+				f.users.EXPECT().Get(mock.Anything, alice.ID).Return(alice, nil)
+				f.members.EXPECT().Add(mock.Anything, &domain.Membership{
+					ListID:   alice.ID,
+					ListName: "alice",
+					UserID:   bob.ID,
+					Username: "bob",
+					Role:     domain.RoleEditor,
+				}).Return(nil)
+			},
+		},
+		"Owner": {
+			ctx:      inList(carol),
+			username: "bob",
+			role:     domain.RoleViewer,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(&domain.Membership{Role: domain.RoleOwner}, nil)
+				f.users.EXPECT().ByUsername(mock.Anything, "bob").Return(bob, nil)
+				f.users.EXPECT().Get(mock.Anything, alice.ID).Return(alice, nil)
+				f.members.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		"Editor": {
+			ctx:      inList(carol),
+			username: "bob",
+			role:     domain.RoleViewer,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+			},
+			wantErr: domain.ErrPermissionDenied{Permission: domain.PermissionShare},
+		},
+		"Unknown user": {
+			ctx:      inList(alice),
+			username: "dave",
+			role:     domain.RoleViewer,
+			mock: func(f fields) {
+				f.users.EXPECT().ByUsername(mock.Anything, "dave").Return(nil, nil)
+			},
+			wantErr: domain.ErrValidation{},
+		},
+		"Creator invited": {
+			ctx:      inList(carol),
+			username: "alice",
+			role:     domain.RoleViewer,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(&domain.Membership{Role: domain.RoleOwner}, nil)
+				f.users.EXPECT().ByUsername(mock.Anything, "alice").Return(alice, nil)
+			},
+			wantErr: domain.ErrValidation{},
+		},
+		"Self invited": {
+			ctx:      inList(carol),
+			username: "carol",
+			role:     domain.RoleViewer,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(&domain.Membership{Role: domain.RoleOwner}, nil)
+				f.users.EXPECT().ByUsername(mock.Anything, "carol").Return(carol, nil)
+			},
+			wantErr: domain.ErrValidation{},
+		},
+		"Unknown role": {
+			ctx:      inList(alice),
+			username: "bob",
+			role:     "admin",
+			wantErr:  domain.ErrValidation{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				members: domain.NewMockMemberRepository(t),
+				users:   domain.NewMockUserRepository(t),
+			}
+			s := NewService(f.members, f.users)
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			_, err := s.Invite(tt.ctx, tt.username, tt.role)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("Invite() error = %v", err)
+				}
+			case domain.ErrValidation:
+				if !errors.As(err, &want) {
+					t.Errorf("Invite() error = %v, want %T", err, tt.wantErr)
+				}
+			default:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Invite() error = %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_service_RemoveMember(t *testing.T) {
+	type fields struct {
+		members *domain.MockMemberRepository
+		users   *domain.MockUserRepository
+	}
+	tests := map[string]struct {
+		ctx     context.Context
+		userID  uuid.UUID
+		mock    func(f fields)
+		wantErr error
+	}{
+		"Creator removes": {
+			ctx:    inList(alice),
+			userID: bob.ID,
+			mock: func(f fields) {
+				f.members.EXPECT().Remove(mock.Anything, alice.ID, bob.ID).Return(nil)
+			},
+		},
+		"Viewer leaves": {
+			ctx:    inList(bob),
+			userID: bob.ID,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, bob.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+				f.members.EXPECT().Remove(mock.Anything, alice.ID, bob.ID).Return(nil)
+			},
+		},
+		"Editor removes": {
+			ctx:    inList(bob),
+			userID: carol.ID,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, bob.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+			},
+			wantErr: domain.ErrPermissionDenied{Permission: domain.PermissionShare},
+		},
+		"Creator removed": {
+			ctx:    inList(carol),
+			userID: alice.ID,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(&domain.Membership{Role: domain.RoleOwner}, nil)
+			},
+			wantErr: domain.ErrValidation{},
+		},
+		"Not a member": {
+			ctx:    inList(carol),
+			userID: carol.ID,
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, alice.ID, carol.ID).Return(nil, nil)
+			},
+			wantErr: domain.ErrPermissionDenied{Permission: domain.PermissionView},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				members: domain.NewMockMemberRepository(t),
+				users:   domain.NewMockUserRepository(t),
+			}
+			s := NewService(f.members, f.users)
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			err := s.RemoveMember(tt.ctx, tt.userID)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("RemoveMember() error = %v", err)
+				}
+			case domain.ErrValidation:
+				if !errors.As(err, &want) {
+					t.Errorf("RemoveMember() error = %v, want %T", err, tt.wantErr)
+				}
+			default:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("RemoveMember() error = %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -4,11 +4,11 @@ package todos
 
 import (
 	context "context"
+	io "io"
 
+	uuid "github.com/google/uuid"
 	domain "github.com/stackus/todos-htmx-wasm/internal/domain"
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -79,6 +79,61 @@ func (_c *MockService_Add_Call) RunAndReturn(run func(context.Context, string) (
 	return _c
 }
 
+// Export provides a mock function with given fields: ctx, format
+func (_m *MockService) Export(ctx context.Context, format string) ([]byte, error) {
+	ret := _m.Called(ctx, format)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, format)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockService_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - format string
+func (_e *MockService_Expecter) Export(ctx interface{}, format interface{}) *MockService_Export_Call {
+	return &MockService_Export_Call{Call: _e.mock.On("Export", ctx, format)}
+}
+
+func (_c *MockService_Export_Call) Run(run func(ctx context.Context, format string)) *MockService_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_Export_Call) Return(_a0 []byte, _a1 error) *MockService_Export_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_Export_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockService_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *MockService) Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error) {
 	ret := _m.Called(ctx, id)
@@ -134,6 +189,64 @@ func (_c *MockService_Get_Call) RunAndReturn(run func(context.Context, uuid.UUID
 	return _c
 }
 
+// Import provides a mock function with given fields: ctx, format, data, mode, dryRun
+func (_m *MockService) Import(ctx context.Context, format string, data io.Reader, mode domain.ImportMode, dryRun bool) (*domain.ImportReport, error) {
+	ret := _m.Called(ctx, format, data, mode, dryRun)
+
+	var r0 *domain.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, domain.ImportMode, bool) (*domain.ImportReport, error)); ok {
+		return rf(ctx, format, data, mode, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, domain.ImportMode, bool) *domain.ImportReport); ok {
+		r0 = rf(ctx, format, data, mode, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, domain.ImportMode, bool) error); ok {
+		r1 = rf(ctx, format, data, mode, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockService_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - format string
+//   - data io.Reader
+//   - mode domain.ImportMode
+//   - dryRun bool
+func (_e *MockService_Expecter) Import(ctx interface{}, format interface{}, data interface{}, mode interface{}, dryRun interface{}) *MockService_Import_Call {
+	return &MockService_Import_Call{Call: _e.mock.On("Import", ctx, format, data, mode, dryRun)}
+}
+
+func (_c *MockService_Import_Call) Run(run func(ctx context.Context, format string, data io.Reader, mode domain.ImportMode, dryRun bool)) *MockService_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(io.Reader), args[3].(domain.ImportMode), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *MockService_Import_Call) Return(_a0 *domain.ImportReport, _a1 error) *MockService_Import_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_Import_Call) RunAndReturn(run func(context.Context, string, io.Reader, domain.ImportMode, bool) (*domain.ImportReport, error)) *MockService_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Remove provides a mock function with given fields: ctx, id
 func (_m *MockService) Remove(ctx context.Context, id uuid.UUID) error {
 	ret := _m.Called(ctx, id)
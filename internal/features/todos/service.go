@@ -2,10 +2,13 @@ package todos
 
 import (
 	"context"
+	"io"
+	"strconv"
 
 	"github.com/google/uuid"
 
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
 )
 
 type (
@@ -23,39 +26,166 @@ type (
 		Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error)
 		// Sort sorts the todos by the given ids
 		Sort(ctx context.Context, ids []uuid.UUID) ([]*domain.Todo, error)
+		// Export encodes the todos list in the given format
+		Export(ctx context.Context, format string) ([]byte, error)
+		// Import decodes todos in the given format and merges them into, or replaces, the list
+		Import(ctx context.Context, format string, data io.Reader, mode domain.ImportMode, dryRun bool) (*domain.ImportReport, error)
 	}
 
+	// Option configures the Service
+	Option func(*service)
+
 	service struct {
-		todos domain.TodoRepository
+		todos   domain.TodoRepository
+		members domain.MemberRepository
 	}
 )
 
-func NewService(todos domain.TodoRepository) Service {
-	return &service{
+func NewService(todos domain.TodoRepository, options ...Option) Service {
+	s := &service{
 		todos: todos,
 	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// WithMembers checks the role of the user in the list of the context before every
+// call; without it every call is let through, as a client of the server, which
+// checks them itself, does
+func WithMembers(members domain.MemberRepository) Option {
+	return func(s *service) {
+		s.members = members
+	}
+}
+
+func (s service) Add(ctx context.Context, description string) (*domain.Todo, error) {
+	if err := s.authorize(ctx, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	description, err := domain.ValidateDescription(description)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("Description", log.Redact(description)).Msg("rejected new todo")
+		return nil, err
+	}
+	todo, err := s.todos.Add(ctx, description)
+	if err == nil {
+		log.Ctx(ctx).Debug().Stringer("TodoID", todo.ID).Str("Description", log.Redact(description)).Msg("added todo")
+	}
+	return todo, err
+}
+
+func (s service) Remove(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, domain.PermissionEdit); err != nil {
+		return err
+	}
+	err := s.todos.Remove(ctx, id)
+	if err == nil {
+		log.Ctx(ctx).Debug().Stringer("TodoID", id).Msg("removed todo")
+	}
+	return err
+}
+
+func (s service) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*domain.Todo, error) {
+	if err := s.authorize(ctx, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	description, err := domain.ValidateDescription(description)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Stringer("TodoID", id).Str("Description", log.Redact(description)).Msg("rejected todo update")
+		return nil, err
+	}
+	todo, err := s.todos.Update(ctx, id, completed, description)
+	if err == nil {
+		log.Ctx(ctx).Debug().Stringer("TodoID", id).Bool("Found", todo != nil).Msg("updated todo")
+	}
+	return todo, err
 }
 
-func (s service) Add(_ context.Context, description string) (*domain.Todo, error) {
-	return s.todos.Add(description)
+func (s service) Search(ctx context.Context, search string) ([]*domain.Todo, error) {
+	if err := s.authorize(ctx, domain.PermissionView); err != nil {
+		return nil, err
+	}
+	return s.todos.Search(ctx, search)
 }
 
-func (s service) Remove(_ context.Context, id uuid.UUID) error {
-	return s.todos.Remove(id)
+func (s service) Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error) {
+	if err := s.authorize(ctx, domain.PermissionView); err != nil {
+		return nil, err
+	}
+	return s.todos.Get(ctx, id)
 }
 
-func (s service) Update(_ context.Context, id uuid.UUID, completed bool, description string) (*domain.Todo, error) {
-	return s.todos.Update(id, completed, description)
+func (s service) Sort(ctx context.Context, ids []uuid.UUID) ([]*domain.Todo, error) {
+	if err := s.authorize(ctx, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	todos, err := s.todos.Reorder(ctx, ids)
+	if err == nil {
+		log.Ctx(ctx).Debug().Int("Count", len(ids)).Msg("sorted todos")
+	}
+	return todos, err
 }
 
-func (s service) Search(_ context.Context, search string) ([]*domain.Todo, error) {
-	return s.todos.Search(search)
+func (s service) Export(ctx context.Context, format string) ([]byte, error) {
+	if err := s.authorize(ctx, domain.PermissionView); err != nil {
+		return nil, err
+	}
+	if _, err := domain.CodecFor(format); err != nil {
+		return nil, err
+	}
+	return s.todos.Export(ctx, format)
 }
 
-func (s service) Get(_ context.Context, id uuid.UUID) (*domain.Todo, error) {
-	return s.todos.Get(id)
+func (s service) Import(ctx context.Context, format string, data io.Reader, mode domain.ImportMode, dryRun bool) (*domain.ImportReport, error) {
+	if err := s.authorize(ctx, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	codec, err := domain.CodecFor(format)
+	if err != nil {
+		return nil, err
+	}
+	list, err := codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErr = domain.ErrValidation{Fields: map[string]string{}}
+	for i, todo := range list {
+		description, err := domain.ValidateDescription(todo.Description)
+		if err != nil {
+			field := "todos[" + strconv.Itoa(i) + "]." + domain.FieldDescription
+			validationErr.Fields[field] = err.(domain.ErrValidation).Field(domain.FieldDescription)
+			continue
+		}
+		todo.Description = description
+	}
+	if len(validationErr.Fields) != 0 {
+		log.Ctx(ctx).Debug().Int("Invalid", len(validationErr.Fields)).Msg("rejected import")
+		return nil, validationErr
+	}
+
+	report, err := s.todos.Import(ctx, list, mode, dryRun)
+	if err == nil {
+		log.Ctx(ctx).Debug().Str("Format", format).Str("Mode", string(mode)).Bool("DryRun", dryRun).Msg("imported todos")
+	}
+	return report, err
 }
 
-func (s service) Sort(_ context.Context, ids []uuid.UUID) ([]*domain.Todo, error) {
-	return s.todos.Reorder(ids)
+// authorize returns an ErrPermissionDenied when the role of the user in the list of
+// the context does not grant the permission
+func (s service) authorize(ctx context.Context, permission domain.Permission) error {
+	if s.members == nil {
+		return nil
+	}
+	role, err := domain.RoleIn(ctx, s.members)
+	if err != nil {
+		return err
+	}
+	if !role.Allows(permission) {
+		log.Ctx(ctx).Debug().Str("Role", string(role)).Str("Permission", string(permission)).Msg("denied todos request")
+		return domain.ErrPermissionDenied{Permission: permission}
+	}
+	return nil
 }
@@ -3,14 +3,24 @@ package todos
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
 )
 
+// sharedList is the context of member acting on the list of owner, with the role the
+// members mock gives them
+var (
+	owner      = &domain.User{ID: uuid.New(), Username: "alice"}
+	member     = &domain.User{ID: uuid.New(), Username: "bob"}
+	sharedList = domain.ContextWithList(domain.ContextWithUser(context.Background(), member), owner.ID)
+)
+
 func Test_service_Add(t *testing.T) {
 	var todo = &domain.Todo{
 		ID:          uuid.New(),
@@ -19,7 +29,8 @@ func Test_service_Add(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx         context.Context
@@ -37,19 +48,74 @@ func Test_service_Add(t *testing.T) {
 				description: "first",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Add("first").Return(todo, nil)
+				f.todos.EXPECT().Add(mock.Anything, "first").Return(todo, nil)
+			},
+			want:    todo,
+			wantErr: false,
+		},
+		"AddTrimmed": {
+			args: args{
+				ctx:         context.Background(),
+				description: "  first\n",
+			},
+			mock: func(f fields) {
+				f.todos.EXPECT().Add(mock.Anything, "first").Return(todo, nil)
 			},
 			want:    todo,
 			wantErr: false,
 		},
+		"AddEmpty": {
+			args: args{
+				ctx:         context.Background(),
+				description: "   ",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"AddViewerDenied": {
+			args: args{
+				ctx:         sharedList,
+				description: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"AddEditor": {
+			args: args{
+				ctx:         sharedList,
+				description: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Add(mock.Anything, "first").Return(todo, nil)
+			},
+			want:    todo,
+			wantErr: false,
+		},
+		"AddNonMemberDenied": {
+			args: args{
+				ctx:         sharedList,
+				description: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -74,7 +140,8 @@ func Test_service_Get(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx context.Context
@@ -92,7 +159,7 @@ func Test_service_Get(t *testing.T) {
 				id:  todo.ID,
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Get(todo.ID).Return(nil, nil)
+				f.todos.EXPECT().Get(mock.Anything, todo.ID).Return(nil, nil)
 			},
 			want:    nil,
 			wantErr: false,
@@ -103,19 +170,56 @@ func Test_service_Get(t *testing.T) {
 				id:  todo.ID,
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Get(todo.ID).Return(todo, nil)
+				f.todos.EXPECT().Get(mock.Anything, todo.ID).Return(todo, nil)
+			},
+			want:    todo,
+			wantErr: false,
+		},
+		"GetViewer": {
+			args: args{
+				ctx: sharedList,
+				id:  todo.ID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+				f.todos.EXPECT().Get(mock.Anything, todo.ID).Return(todo, nil)
+			},
+			want:    todo,
+			wantErr: false,
+		},
+		"GetEditor": {
+			args: args{
+				ctx: sharedList,
+				id:  todo.ID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Get(mock.Anything, todo.ID).Return(todo, nil)
 			},
 			want:    todo,
 			wantErr: false,
 		},
+		"GetNonMemberDenied": {
+			args: args{
+				ctx: sharedList,
+				id:  todo.ID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -135,7 +239,8 @@ func Test_service_Get(t *testing.T) {
 func Test_service_Remove(t *testing.T) {
 	var todoID = uuid.New()
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx context.Context
@@ -152,18 +257,51 @@ func Test_service_Remove(t *testing.T) {
 				id:  todoID,
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Remove(todoID).Return(nil)
+				f.todos.EXPECT().Remove(mock.Anything, todoID).Return(nil)
 			},
 			wantErr: false,
 		},
+		"RemoveViewerDenied": {
+			args: args{
+				ctx: sharedList,
+				id:  todoID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+			},
+			wantErr: true,
+		},
+		"RemoveEditor": {
+			args: args{
+				ctx: sharedList,
+				id:  todoID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Remove(mock.Anything, todoID).Return(nil)
+			},
+			wantErr: false,
+		},
+		"RemoveNonMemberDenied": {
+			args: args{
+				ctx: sharedList,
+				id:  todoID,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -189,7 +327,8 @@ func Test_service_Search(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx    context.Context
@@ -207,7 +346,7 @@ func Test_service_Search(t *testing.T) {
 				search: "fourth",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Search("fourth").Return([]*domain.Todo{}, nil)
+				f.todos.EXPECT().Search(mock.Anything, "fourth").Return([]*domain.Todo{}, nil)
 			},
 			want:    []*domain.Todo{},
 			wantErr: false,
@@ -218,7 +357,7 @@ func Test_service_Search(t *testing.T) {
 				search: "first",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Search("first").Return([]*domain.Todo{first}, nil)
+				f.todos.EXPECT().Search(mock.Anything, "first").Return([]*domain.Todo{first}, nil)
 			},
 			want:    []*domain.Todo{first},
 			wantErr: false,
@@ -229,19 +368,56 @@ func Test_service_Search(t *testing.T) {
 				search: "ir",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Search("ir").Return([]*domain.Todo{first, third}, nil)
+				f.todos.EXPECT().Search(mock.Anything, "ir").Return([]*domain.Todo{first, third}, nil)
 			},
 			want:    []*domain.Todo{first, third},
 			wantErr: false,
 		},
+		"SearchViewer": {
+			args: args{
+				ctx:    sharedList,
+				search: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+				f.todos.EXPECT().Search(mock.Anything, "first").Return([]*domain.Todo{first}, nil)
+			},
+			want:    []*domain.Todo{first},
+			wantErr: false,
+		},
+		"SearchEditor": {
+			args: args{
+				ctx:    sharedList,
+				search: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Search(mock.Anything, "first").Return([]*domain.Todo{first}, nil)
+			},
+			want:    []*domain.Todo{first},
+			wantErr: false,
+		},
+		"SearchNonMemberDenied": {
+			args: args{
+				ctx:    sharedList,
+				search: "first",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -278,7 +454,8 @@ func Test_service_Sort(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx context.Context
@@ -295,7 +472,7 @@ func Test_service_Sort(t *testing.T) {
 				ids: []uuid.UUID{},
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Reorder([]uuid.UUID{}).Return([]*domain.Todo{}, nil)
+				f.todos.EXPECT().Reorder(mock.Anything, []uuid.UUID{}).Return([]*domain.Todo{}, nil)
 			},
 			wantErr: false,
 		},
@@ -305,18 +482,51 @@ func Test_service_Sort(t *testing.T) {
 				ids: []uuid.UUID{third.ID, second.ID, first.ID},
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Reorder([]uuid.UUID{third.ID, second.ID, first.ID}).Return([]*domain.Todo{third, second, first}, nil)
+				f.todos.EXPECT().Reorder(mock.Anything, []uuid.UUID{third.ID, second.ID, first.ID}).Return([]*domain.Todo{third, second, first}, nil)
 			},
 			wantErr: false,
 		},
+		"SortViewerDenied": {
+			args: args{
+				ctx: sharedList,
+				ids: []uuid.UUID{first.ID},
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+			},
+			wantErr: true,
+		},
+		"SortEditor": {
+			args: args{
+				ctx: sharedList,
+				ids: []uuid.UUID{first.ID},
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Reorder(mock.Anything, []uuid.UUID{first.ID}).Return([]*domain.Todo{first}, nil)
+			},
+			wantErr: false,
+		},
+		"SortNonMemberDenied": {
+			args: args{
+				ctx: sharedList,
+				ids: []uuid.UUID{first.ID},
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -337,7 +547,8 @@ func Test_service_Update(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 	type fields struct {
-		todos *domain.MockTodoRepository
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
 	}
 	type args struct {
 		ctx         context.Context
@@ -359,7 +570,7 @@ func Test_service_Update(t *testing.T) {
 				description: "updated",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Update(todoID, true, "updated").Return(nil, nil)
+				f.todos.EXPECT().Update(mock.Anything, todoID, true, "updated").Return(nil, nil)
 			},
 			want:    nil,
 			wantErr: false,
@@ -372,19 +583,71 @@ func Test_service_Update(t *testing.T) {
 				description: "updated",
 			},
 			mock: func(f fields) {
-				f.todos.EXPECT().Update(todoID, true, "updated").Return(updated, nil)
+				f.todos.EXPECT().Update(mock.Anything, todoID, true, "updated").Return(updated, nil)
+			},
+			want:    updated,
+			wantErr: false,
+		},
+		"UpdateInvalid": {
+			args: args{
+				ctx:         context.Background(),
+				id:          todoID,
+				completed:   true,
+				description: "up\x00dated",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"UpdateViewerDenied": {
+			args: args{
+				ctx:         sharedList,
+				id:          todoID,
+				completed:   true,
+				description: "updated",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"UpdateEditor": {
+			args: args{
+				ctx:         sharedList,
+				id:          todoID,
+				completed:   true,
+				description: "updated",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Update(mock.Anything, todoID, true, "updated").Return(updated, nil)
 			},
 			want:    updated,
 			wantErr: false,
 		},
+		"UpdateNonMemberDenied": {
+			args: args{
+				ctx:         sharedList,
+				id:          todoID,
+				completed:   true,
+				description: "updated",
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			f := fields{
-				todos: domain.NewMockTodoRepository(t),
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
 			}
 			s := service{
-				todos: f.todos,
+				todos:   f.todos,
+				members: f.members,
 			}
 			if tt.mock != nil {
 				tt.mock(f)
@@ -400,3 +663,223 @@ func Test_service_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_service_Export(t *testing.T) {
+	type fields struct {
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
+	}
+	type args struct {
+		ctx    context.Context
+		format string
+	}
+	tests := map[string]struct {
+		args    args
+		mock    func(f fields)
+		want    []byte
+		wantErr bool
+	}{
+		"Export": {
+			args: args{
+				ctx:    context.Background(),
+				format: domain.FormatCSV,
+			},
+			mock: func(f fields) {
+				f.todos.EXPECT().Export(mock.Anything, domain.FormatCSV).Return([]byte("id,description,completed,created_at\n"), nil)
+			},
+			want:    []byte("id,description,completed,created_at\n"),
+			wantErr: false,
+		},
+		"ExportUnsupported": {
+			args: args{
+				ctx:    context.Background(),
+				format: "xml",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"ExportViewer": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+				f.todos.EXPECT().Export(mock.Anything, domain.FormatCSV).Return([]byte("id,description,completed,created_at\n"), nil)
+			},
+			want:    []byte("id,description,completed,created_at\n"),
+			wantErr: false,
+		},
+		"ExportEditor": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Export(mock.Anything, domain.FormatCSV).Return([]byte("id,description,completed,created_at\n"), nil)
+			},
+			want:    []byte("id,description,completed,created_at\n"),
+			wantErr: false,
+		},
+		"ExportNonMemberDenied": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
+			}
+			s := service{
+				todos:   f.todos,
+				members: f.members,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			got, err := s.Export(tt.args.ctx, tt.args.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Export() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Import(t *testing.T) {
+	var todoID = uuid.New()
+	var report = &domain.ImportReport{
+		Mode:   domain.ImportReplace,
+		DryRun: true,
+	}
+	type fields struct {
+		todos   *domain.MockTodoRepository
+		members *domain.MockMemberRepository
+	}
+	type args struct {
+		ctx    context.Context
+		format string
+		data   string
+		mode   domain.ImportMode
+		dryRun bool
+	}
+	tests := map[string]struct {
+		args    args
+		mock    func(f fields)
+		want    *domain.ImportReport
+		wantErr bool
+	}{
+		"Import": {
+			args: args{
+				ctx:    context.Background(),
+				format: domain.FormatCSV,
+				data:   "id,description\n" + todoID.String() + ",  first \n",
+				mode:   domain.ImportReplace,
+				dryRun: true,
+			},
+			mock: func(f fields) {
+				f.todos.EXPECT().Import(mock.Anything, mock.MatchedBy(func(todos []*domain.Todo) bool {
+					return len(todos) == 1 && todos[0].ID == todoID && todos[0].Description == "first"
+				}), domain.ImportReplace, true).Return(report, nil)
+			},
+			want:    report,
+			wantErr: false,
+		},
+		"ImportInvalid": {
+			args: args{
+				ctx:    context.Background(),
+				format: domain.FormatCSV,
+				data:   "description\nfirst\n\"\"\n",
+				mode:   domain.ImportMerge,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"ImportUnsupported": {
+			args: args{
+				ctx:    context.Background(),
+				format: "xml",
+				data:   "<todos/>",
+				mode:   domain.ImportMerge,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"ImportViewerDenied": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+				data:   "id,description\n" + todoID.String() + ",first\n",
+				mode:   domain.ImportMerge,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleViewer}, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		"ImportEditor": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+				data:   "id,description\n" + todoID.String() + ",first\n",
+				mode:   domain.ImportMerge,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(&domain.Membership{Role: domain.RoleEditor}, nil)
+				f.todos.EXPECT().Import(mock.Anything, mock.Anything, domain.ImportMerge, false).Return(report, nil)
+			},
+			want:    report,
+			wantErr: false,
+		},
+		"ImportNonMemberDenied": {
+			args: args{
+				ctx:    sharedList,
+				format: domain.FormatCSV,
+				data:   "id,description\n" + todoID.String() + ",first\n",
+				mode:   domain.ImportMerge,
+			},
+			mock: func(f fields) {
+				f.members.EXPECT().Get(mock.Anything, owner.ID, member.ID).Return(nil, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				todos:   domain.NewMockTodoRepository(t),
+				members: domain.NewMockMemberRepository(t),
+			}
+			s := service{
+				todos:   f.todos,
+				members: f.members,
+			}
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			got, err := s.Import(tt.args.ctx, tt.args.format, strings.NewReader(tt.args.data), tt.args.mode, tt.args.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Import() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
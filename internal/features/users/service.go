@@ -0,0 +1,205 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+// DefaultSessionTTL is how long a session lasts unless WithSessionTTL says otherwise
+const DefaultSessionTTL = 7 * 24 * time.Hour
+
+type (
+	// Service registers users and manages their sessions
+	Service interface {
+		// Register creates a user and starts a session for them
+		Register(ctx context.Context, username, password string) (*domain.User, string, error)
+		// Login starts a session for the user with the username and password
+		Login(ctx context.Context, username, password string) (*domain.User, string, error)
+		// Logout ends the session the token belongs to
+		Logout(ctx context.Context, token string) error
+		// Authenticate returns the user the token belongs to, or nil
+		Authenticate(ctx context.Context, token string) (*domain.User, error)
+		// SessionTTL returns how long new sessions last
+		SessionTTL() time.Duration
+		// CreateToken creates an API token for the user of the context and returns it
+		// with its secret, which cannot be read again
+		CreateToken(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIToken, string, error)
+		// Tokens lists the API tokens of the user of the context
+		Tokens(ctx context.Context) ([]*domain.APIToken, error)
+		// RevokeToken revokes an API token of the user of the context, or returns nil
+		// when they have none with the id
+		RevokeToken(ctx context.Context, id uuid.UUID) (*domain.APIToken, error)
+		// AuthenticateToken returns the user an active API token belongs to along with
+		// the token, or nils
+		AuthenticateToken(ctx context.Context, token string) (*domain.User, *domain.APIToken, error)
+	}
+
+	// Option configures the Service
+	Option func(*service)
+
+	service struct {
+		users      domain.UserRepository
+		sessions   domain.SessionRepository
+		tokens     domain.TokenRepository
+		sessionTTL time.Duration
+		todos      domain.TodoRepository
+		seed       []string
+	}
+)
+
+func NewService(users domain.UserRepository, sessions domain.SessionRepository, tokens domain.TokenRepository, options ...Option) Service {
+	s := &service{
+		users:      users,
+		sessions:   sessions,
+		tokens:     tokens,
+		sessionTTL: DefaultSessionTTL,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// WithSessionTTL sets how long sessions last
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(s *service) {
+		s.sessionTTL = ttl
+	}
+}
+
+// WithSeed adds the descriptions to the list of every new user
+func WithSeed(todos domain.TodoRepository, descriptions []string) Option {
+	return func(s *service) {
+		s.todos = todos
+		s.seed = descriptions
+	}
+}
+
+func (s service) Register(ctx context.Context, username, password string) (*domain.User, string, error) {
+	user, err := domain.NewUser(username, password)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("rejected registration")
+		return nil, "", err
+	}
+	if err = s.users.Create(ctx, user); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("rejected registration")
+		return nil, "", err
+	}
+	log.Ctx(ctx).Info().Stringer("UserID", user.ID).Msg("registered user")
+
+	userCtx := domain.ContextWithUser(ctx, user)
+	for _, description := range s.seed {
+		if _, err = s.todos.Add(userCtx, description); err != nil {
+			return nil, "", err
+		}
+	}
+
+	token, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+func (s service) Login(ctx context.Context, username, password string) (*domain.User, string, error) {
+	username, _ = domain.ValidateUsername(username)
+	user, err := s.users.ByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+	// a nil user is checked too, so unknown usernames take as long to reject
+	if !user.CheckPassword(password) {
+		log.Ctx(ctx).Debug().Msg("rejected login")
+		return nil, "", domain.ErrInvalidCredentials{}
+	}
+
+	token, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+func (s service) Logout(ctx context.Context, token string) error {
+	return s.sessions.Delete(ctx, domain.HashToken(token))
+}
+
+func (s service) Authenticate(ctx context.Context, token string) (*domain.User, error) {
+	if token == "" {
+		return nil, nil
+	}
+	session, err := s.sessions.Get(ctx, domain.HashToken(token))
+	if err != nil || session == nil || session.Expired() {
+		return nil, err
+	}
+	return s.users.Get(ctx, session.UserID)
+}
+
+func (s service) SessionTTL() time.Duration {
+	return s.sessionTTL
+}
+
+func (s service) CreateToken(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIToken, string, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil {
+		return nil, "", domain.ErrUnauthenticated{}
+	}
+	token, secret, err := domain.NewAPIToken(user.ID, name, scopes, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	if err = s.tokens.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+	log.Ctx(ctx).Info().Stringer("UserID", user.ID).Stringer("TokenID", token.ID).Msg("created API token")
+	return token, secret, nil
+}
+
+func (s service) Tokens(ctx context.Context) ([]*domain.APIToken, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil {
+		return nil, domain.ErrUnauthenticated{}
+	}
+	return s.tokens.List(ctx, user.ID)
+}
+
+func (s service) RevokeToken(ctx context.Context, id uuid.UUID) (*domain.APIToken, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil {
+		return nil, domain.ErrUnauthenticated{}
+	}
+	token, err := s.tokens.Revoke(ctx, user.ID, id)
+	if err == nil && token != nil {
+		log.Ctx(ctx).Info().Stringer("UserID", user.ID).Stringer("TokenID", token.ID).Msg("revoked API token")
+	}
+	return token, err
+}
+
+func (s service) AuthenticateToken(ctx context.Context, secret string) (*domain.User, *domain.APIToken, error) {
+	token, err := s.tokens.ByHash(ctx, domain.HashToken(secret))
+	if err != nil || token == nil || !token.Active() {
+		return nil, nil, err
+	}
+	user, err := s.users.Get(ctx, token.UserID)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	return user, token, nil
+}
+
+func (s service) startSession(ctx context.Context, user *domain.User) (string, error) {
+	session, token, err := domain.NewSession(user.ID, s.sessionTTL)
+	if err != nil {
+		return "", err
+	}
+	if err = s.sessions.Create(ctx, session); err != nil {
+		return "", err
+	}
+	log.Ctx(ctx).Debug().Stringer("UserID", user.ID).Msg("started session")
+	return token, nil
+}
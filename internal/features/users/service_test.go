@@ -0,0 +1,267 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func TestMain(m *testing.M) {
+	domain.PasswordCost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+func Test_service_Register(t *testing.T) {
+	type fields struct {
+		users    *domain.MockUserRepository
+		sessions *domain.MockSessionRepository
+		todos    *domain.MockTodoRepository
+	}
+	tests := map[string]struct {
+		username string
+		password string
+		mock     func(f fields)
+		wantErr  error
+	}{
+		"Register": {
+			username: "Alice",
+			password: "correct horse",
+			mock: func(f fields) {
+				f.users.EXPECT().Create(mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+					return u.Username == "alice"
+				})).Return(nil)
+				f.todos.EXPECT().Add(mock.MatchedBy(func(ctx context.Context) bool {
+					return domain.UserFromContext(ctx).Username == "alice"
+				}), "Bake a cake").Return(&domain.Todo{}, nil)
+				f.sessions.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		"Invalid": {
+			username: "al",
+			password: "short",
+			wantErr:  domain.ErrValidation{},
+		},
+		"Taken": {
+			username: "alice",
+			password: "correct horse",
+			mock: func(f fields) {
+				f.users.EXPECT().Create(mock.Anything, mock.Anything).Return(domain.ErrValidation{
+					Fields: map[string]string{domain.FieldUsername: "is already taken"},
+				})
+			},
+			wantErr: domain.ErrValidation{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				users:    domain.NewMockUserRepository(t),
+				sessions: domain.NewMockSessionRepository(t),
+				todos:    domain.NewMockTodoRepository(t),
+			}
+			s := NewService(f.users, f.sessions, nil, WithSeed(f.todos, []string{"Bake a cake"}))
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			user, token, err := s.Register(context.Background(), tt.username, tt.password)
+			if tt.wantErr != nil {
+				if !errors.As(err, &domain.ErrValidation{}) {
+					t.Errorf("Register() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if user == nil || token == "" {
+				t.Errorf("Register() = %v, %q, want a user and a token", user, token)
+			}
+		})
+	}
+}
+
+func Test_service_Login(t *testing.T) {
+	user, _ := domain.NewUser("alice", "correct horse")
+	type fields struct {
+		users    *domain.MockUserRepository
+		sessions *domain.MockSessionRepository
+	}
+	tests := map[string]struct {
+		username string
+		password string
+		mock     func(f fields)
+		wantErr  bool
+	}{
+		"Login": {
+			username: " Alice",
+			password: "correct horse",
+			mock: func(f fields) {
+				f.users.EXPECT().ByUsername(mock.Anything, "alice").Return(user, nil)
+				f.sessions.EXPECT().Create(mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+					return s.UserID == user.ID
+				})).Return(nil)
+			},
+		},
+		"WrongPassword": {
+			username: "alice",
+			password: "wrong password",
+			mock: func(f fields) {
+				f.users.EXPECT().ByUsername(mock.Anything, "alice").Return(user, nil)
+			},
+			wantErr: true,
+		},
+		"UnknownUser": {
+			username: "mallory",
+			password: "correct horse",
+			mock: func(f fields) {
+				f.users.EXPECT().ByUsername(mock.Anything, "mallory").Return(nil, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				users:    domain.NewMockUserRepository(t),
+				sessions: domain.NewMockSessionRepository(t),
+			}
+			s := NewService(f.users, f.sessions, nil)
+			tt.mock(f)
+			got, token, err := s.Login(context.Background(), tt.username, tt.password)
+			if tt.wantErr {
+				if !errors.As(err, &domain.ErrInvalidCredentials{}) {
+					t.Errorf("Login() error = %v, want ErrInvalidCredentials", err)
+				}
+				return
+			}
+			if err != nil || got != user || token == "" {
+				t.Errorf("Login() = %v, %q, %v, want the user and a token", got, token, err)
+			}
+		})
+	}
+}
+
+func Test_service_Authenticate(t *testing.T) {
+	user, _ := domain.NewUser("alice", "correct horse")
+	session, token, _ := domain.NewSession(user.ID, time.Hour)
+	expired, expiredToken, _ := domain.NewSession(user.ID, -time.Second)
+	type fields struct {
+		users    *domain.MockUserRepository
+		sessions *domain.MockSessionRepository
+	}
+	tests := map[string]struct {
+		token string
+		mock  func(f fields)
+		want  *domain.User
+	}{
+		"Valid": {
+			token: token,
+			mock: func(f fields) {
+				f.sessions.EXPECT().Get(mock.Anything, session.ID).Return(session, nil)
+				f.users.EXPECT().Get(mock.Anything, user.ID).Return(user, nil)
+			},
+			want: user,
+		},
+		"NoToken": {
+			token: "",
+		},
+		"UnknownToken": {
+			token: "unknown",
+			mock: func(f fields) {
+				f.sessions.EXPECT().Get(mock.Anything, domain.HashToken("unknown")).Return(nil, nil)
+			},
+		},
+		"Expired": {
+			token: expiredToken,
+			mock: func(f fields) {
+				f.sessions.EXPECT().Get(mock.Anything, expired.ID).Return(expired, nil)
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				users:    domain.NewMockUserRepository(t),
+				sessions: domain.NewMockSessionRepository(t),
+			}
+			s := NewService(f.users, f.sessions, nil)
+			if tt.mock != nil {
+				tt.mock(f)
+			}
+			got, err := s.Authenticate(context.Background(), tt.token)
+			if err != nil || got != tt.want {
+				t.Errorf("Authenticate() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_AuthenticateToken(t *testing.T) {
+	user, _ := domain.NewUser("alice", "correct horse")
+	active, activeSecret, _ := domain.NewAPIToken(user.ID, "deploy", []domain.Scope{domain.ScopeRead}, nil)
+	revoked, revokedSecret, _ := domain.NewAPIToken(user.ID, "old", []domain.Scope{domain.ScopeRead}, nil)
+	revokedAt := time.Now()
+	revoked.RevokedAt = &revokedAt
+	expired, expiredSecret, _ := domain.NewAPIToken(user.ID, "expired", []domain.Scope{domain.ScopeRead}, nil)
+	expiresAt := time.Now().Add(-time.Second)
+	expired.ExpiresAt = &expiresAt
+	type fields struct {
+		users  *domain.MockUserRepository
+		tokens *domain.MockTokenRepository
+	}
+	tests := map[string]struct {
+		secret    string
+		mock      func(f fields)
+		wantUser  *domain.User
+		wantToken *domain.APIToken
+	}{
+		"Active": {
+			secret: activeSecret,
+			mock: func(f fields) {
+				f.tokens.EXPECT().ByHash(mock.Anything, active.Hash).Return(active, nil)
+				f.users.EXPECT().Get(mock.Anything, user.ID).Return(user, nil)
+			},
+			wantUser:  user,
+			wantToken: active,
+		},
+		"Revoked": {
+			secret: revokedSecret,
+			mock: func(f fields) {
+				f.tokens.EXPECT().ByHash(mock.Anything, revoked.Hash).Return(revoked, nil)
+			},
+		},
+		"Expired": {
+			secret: expiredSecret,
+			mock: func(f fields) {
+				f.tokens.EXPECT().ByHash(mock.Anything, expired.Hash).Return(expired, nil)
+			},
+		},
+		"Unknown": {
+			secret: "todo_unknown",
+			mock: func(f fields) {
+				f.tokens.EXPECT().ByHash(mock.Anything, domain.HashToken("todo_unknown")).Return(nil, nil)
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := fields{
+				users:  domain.NewMockUserRepository(t),
+				tokens: domain.NewMockTokenRepository(t),
+			}
+			s := NewService(f.users, nil, f.tokens)
+			tt.mock(f)
+			gotUser, gotToken, err := s.AuthenticateToken(context.Background(), tt.secret)
+			if err != nil || gotUser != tt.wantUser || gotToken != tt.wantToken {
+				t.Errorf("AuthenticateToken() = %v, %v, %v, want %v, %v", gotUser, gotToken, err, tt.wantUser, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// ErrShuttingDown is reported by Ready once shutdown has begun
+var ErrShuttingDown = errors.New("shutting down")
+
+type (
+	// Manager runs HTTP servers until its context is canceled, then drains them and
+	// runs its closers before logging a summary
+	Manager struct {
+		logger  zerolog.Logger
+		timeout time.Duration
+
+		servers []server
+		closers []closer
+
+		shuttingDown atomic.Bool
+		inFlight     atomic.Int64
+	}
+
+	server struct {
+		name     string
+		server   *http.Server
+		listener net.Listener
+	}
+
+	closer struct {
+		name string
+		fn   func(ctx context.Context) error
+	}
+)
+
+// NewManager creates a manager that gives shutdown at most timeout to complete
+func NewManager(logger zerolog.Logger, timeout time.Duration) *Manager {
+	return &Manager{
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+// AddServer runs the server on its Addr; servers with a TLSConfig serve HTTPS using
+// the certificates it provides
+func (m *Manager) AddServer(name string, srv *http.Server) {
+	m.servers = append(m.servers, server{name: name, server: srv})
+}
+
+// AddListener runs the server on an existing listener
+func (m *Manager) AddListener(name string, srv *http.Server, listener net.Listener) {
+	m.servers = append(m.servers, server{name: name, server: srv, listener: listener})
+}
+
+// AddCloser runs fn once the servers have stopped; closers run in the reverse of the
+// order they were added
+func (m *Manager) AddCloser(name string, fn func(ctx context.Context) error) {
+	m.closers = append(m.closers, closer{name: name, fn: fn})
+}
+
+// Middleware is a http.Handler middleware that counts the requests in flight, which
+// are reported when shutdown begins
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Ready fails once shutdown has begun so load balancers stop sending requests; it
+// can be used as a health.Check
+func (m *Manager) Ready(context.Context) error {
+	if m.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+// Run serves until ctx is canceled or a server fails, then shuts everything down
+func (m *Manager) Run(ctx context.Context) error {
+	failed := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		s := s
+		go func() {
+			err := s.serve(m.logger)
+			if !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("%s server: %w", s.name, err)
+			}
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		m.logger.Info().Msg("shutting down")
+	case runErr = <-failed:
+		m.logger.Error().Err(runErr).Msg("server failed; shutting down")
+	}
+
+	return errors.Join(runErr, m.shutdown())
+}
+
+func (m *Manager) shutdown() error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	m.shuttingDown.Store(true)
+	inFlight := m.inFlight.Load()
+
+	var mu sync.Mutex
+	var errs []error
+	record := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range m.servers {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.server.Shutdown(ctx); err != nil {
+				record(fmt.Errorf("%s server: %w", s.name, err))
+				_ = s.server.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.fn(ctx); err != nil {
+			record(fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+	event := m.logger.Info()
+	if err != nil {
+		event = m.logger.Error().Err(err)
+	}
+	event.
+		Int64("InFlight", inFlight).
+		Int("Servers", len(m.servers)).
+		Int("Closers", len(m.closers)).
+		Dur("Elapsed", time.Since(start)).
+		Msg("shutdown complete")
+	return err
+}
+
+func (s server) serve(logger zerolog.Logger) error {
+	addr := s.server.Addr
+	if s.listener != nil {
+		addr = s.listener.Addr().String()
+	}
+	logger.Info().Str("Server", s.name).Str("Addr", addr).Bool("TLS", s.server.TLSConfig != nil).Msg("listening")
+
+	switch {
+	case s.listener != nil && s.server.TLSConfig != nil:
+		return s.server.ServeTLS(s.listener, "", "")
+	case s.listener != nil:
+		return s.server.Serve(s.listener)
+	case s.server.TLSConfig != nil:
+		return s.server.ListenAndServeTLS("", "")
+	default:
+		return s.server.ListenAndServe()
+	}
+}
+
+// RepositoryCloser flushes and then closes the repository, for repositories that
+// implement domain.Flusher or io.Closer
+func RepositoryCloser(repo domain.TodoRepository) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if flusher, ok := repo.(domain.Flusher); ok {
+			if err := flusher.Flush(ctx); err != nil {
+				return err
+			}
+		}
+		if c, ok := repo.(io.Closer); ok {
+			return c.Close()
+		}
+		return nil
+	}
+}
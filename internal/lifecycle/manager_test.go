@@ -0,0 +1,225 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/certs"
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func TestManager_Run(t *testing.T) {
+	tests := map[string]struct {
+		timeout     time.Duration
+		ignoreStop  bool
+		closerErr   error
+		wantErr     bool
+		wantSummary string
+	}{
+		"Graceful": {
+			timeout:     5 * time.Second,
+			wantSummary: `"message":"shutdown complete"`,
+		},
+		"Timeout": {
+			timeout:     100 * time.Millisecond,
+			ignoreStop:  true,
+			wantErr:     true,
+			wantSummary: `"level":"error"`,
+		},
+		"CloserError": {
+			timeout:     5 * time.Second,
+			closerErr:   errors.New("disk full"),
+			wantErr:     true,
+			wantSummary: `disk full`,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			m := NewManager(zerolog.New(&logs), tt.timeout)
+
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+			mux := http.NewServeMux()
+			mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+				started <- struct{}{}
+				<-release
+				_, _ = io.WriteString(w, "done")
+			})
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.AddListener("http", &http.Server{Handler: m.Middleware(mux)}, listener)
+			var closed bool
+			m.AddCloser("repository", func(ctx context.Context) error {
+				closed = true
+				return tt.closerErr
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := make(chan error, 1)
+			go func() { result <- m.Run(ctx) }()
+
+			base := "http://" + listener.Addr().String()
+			slow := make(chan string, 1)
+			go func() {
+				resp, err := http.Get(base + "/slow")
+				if err != nil {
+					slow <- err.Error()
+					return
+				}
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				slow <- string(body)
+			}()
+			<-started
+
+			if err := m.Ready(ctx); err != nil {
+				t.Errorf("Ready() before shutdown = %v", err)
+			}
+			cancel()
+			// the server stops accepting requests before in-flight requests finish
+			time.Sleep(20 * time.Millisecond)
+			if err := m.Ready(ctx); !errors.Is(err, ErrShuttingDown) {
+				t.Errorf("Ready() during shutdown = %v, want %v", err, ErrShuttingDown)
+			}
+			// a request that ignores the stop holds up shutdown past its timeout
+			if !tt.ignoreStop {
+				close(release)
+			}
+
+			var runErr error
+			select {
+			case runErr = <-result:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run() did not return")
+			}
+			if tt.ignoreStop {
+				close(release)
+			}
+			if (runErr != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", runErr, tt.wantErr)
+			}
+			if !tt.ignoreStop {
+				if got := <-slow; got != "done" {
+					t.Errorf("in-flight request = %q, want %q", got, "done")
+				}
+			}
+			if !closed {
+				t.Errorf("closer was not called")
+			}
+			if !strings.Contains(logs.String(), tt.wantSummary) {
+				t.Errorf("logs = %s, want %s", logs.String(), tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestManager_RunTLS(t *testing.T) {
+	certFile, keyFile, _, err := certs.DevCertificate(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader, err := certs.NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pem, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.AppendCertsFromPEM(pem)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(zerolog.Nop(), time.Second)
+	m.AddListener("https", &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}),
+		TLSConfig: reloader.TLSConfig(),
+	}, listener)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- m.Run(ctx) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if got := string(body); got != "HTTP/2.0" {
+		t.Errorf("protocol = %s, want HTTP/2.0", got)
+	}
+
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestRepositoryCloser(t *testing.T) {
+	type flushingRepo struct {
+		*domain.MockTodoRepository
+		*domain.MockFlusher
+	}
+	flushErr := errors.New("flush failed")
+	tests := map[string]struct {
+		repo    func(t *testing.T) domain.TodoRepository
+		wantErr error
+	}{
+		"NotFlushable": {
+			repo: func(t *testing.T) domain.TodoRepository {
+				return domain.NewMockTodoRepository(t)
+			},
+		},
+		"Flushed": {
+			repo: func(t *testing.T) domain.TodoRepository {
+				flusher := domain.NewMockFlusher(t)
+				flusher.On("Flush", mock.Anything).Return(nil)
+				return flushingRepo{domain.NewMockTodoRepository(t), flusher}
+			},
+		},
+		"FlushFailed": {
+			repo: func(t *testing.T) domain.TodoRepository {
+				flusher := domain.NewMockFlusher(t)
+				flusher.On("Flush", mock.Anything).Return(flushErr)
+				return flushingRepo{domain.NewMockTodoRepository(t), flusher}
+			},
+			wantErr: flushErr,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := RepositoryCloser(tt.repo(t))(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RepositoryCloser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
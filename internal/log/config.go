@@ -0,0 +1,198 @@
+package log
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Log formats
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+const (
+	// redacted replaces text that is kept out of the logs
+	redacted = "[REDACTED]"
+	// accessTokenParam is the query parameter API tokens may be sent in
+	accessTokenParam = "access_token"
+)
+
+// Config describes how and where logs are written
+type Config struct {
+	// Format is either "console" or "json"
+	Format string `yaml:"format" toml:"format"`
+	// Level is the minimum level written, e.g. "debug" or "warn"
+	Level string `yaml:"level" toml:"level"`
+	// Caller adds the file and line of each log call
+	Caller bool `yaml:"caller" toml:"caller"`
+	// SampleSuccess logs only one in every SampleSuccess 2xx requests in WebLogger; 0 and 1 log all of them
+	SampleSuccess uint `yaml:"sample_success" toml:"sample_success"`
+	// Redact keeps todo descriptions and query strings out of the logs
+	Redact bool `yaml:"redact" toml:"redact"`
+	// File, when set, writes logs to this file instead of stdout
+	File string `yaml:"file" toml:"file"`
+	// FileMaxSize is the size in megabytes at which the log file is rotated
+	FileMaxSize int `yaml:"file_max_size" toml:"file_max_size"`
+	// FileMaxBackups is the number of rotated log files kept
+	FileMaxBackups int `yaml:"file_max_backups" toml:"file_max_backups"`
+}
+
+// redact is read by Redact and set by Configure
+var redact atomic.Bool
+
+// DefaultConfig returns the configuration of the DefaultLogger
+func DefaultConfig() Config {
+	return Config{
+		Format:         FormatConsole,
+		Level:          zerolog.DebugLevel.String(),
+		Caller:         true,
+		FileMaxSize:    100,
+		FileMaxBackups: 3,
+	}
+}
+
+// LoadEnv reads LOG_* variables over the configuration using lookup, usually os.LookupEnv
+func (c *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	var err error
+	str := func(key string, v *string) {
+		if value, ok := lookup(key); ok {
+			*v = value
+		}
+	}
+	parse := func(key string, set func(string) error) {
+		if value, ok := lookup(key); ok && err == nil {
+			if parseErr := set(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		}
+	}
+	boolean := func(v *bool) func(string) (err error) {
+		return func(s string) (err error) { *v, err = strconv.ParseBool(s); return }
+	}
+	integer := func(v *int) func(string) (err error) {
+		return func(s string) (err error) { *v, err = strconv.Atoi(s); return }
+	}
+
+	str("LOG_FORMAT", &c.Format)
+	str("LOG_LEVEL", &c.Level)
+	str("LOG_FILE", &c.File)
+	parse("LOG_CALLER", boolean(&c.Caller))
+	parse("LOG_REDACT", boolean(&c.Redact))
+	parse("LOG_SAMPLE_SUCCESS", func(s string) error {
+		n, err := strconv.ParseUint(s, 10, 0)
+		c.SampleSuccess = uint(n)
+		return err
+	})
+	parse("LOG_FILE_MAX_SIZE", integer(&c.FileMaxSize))
+	parse("LOG_FILE_MAX_BACKUPS", integer(&c.FileMaxBackups))
+	return err
+}
+
+// RegisterFlags adds -log-* flags to fs whose defaults are the current configuration,
+// so flags override anything read with LoadEnv
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Format, "log-format", c.Format, "log format: console or json")
+	fs.StringVar(&c.Level, "log-level", c.Level, "minimum log level: trace, debug, info, warn or error")
+	fs.BoolVar(&c.Caller, "log-caller", c.Caller, "add the file and line of each log call")
+	fs.UintVar(&c.SampleSuccess, "log-sample-success", c.SampleSuccess, "log one in every N successful requests")
+	fs.BoolVar(&c.Redact, "log-redact", c.Redact, "keep todo descriptions and query strings out of the logs")
+	fs.StringVar(&c.File, "log-file", c.File, "write logs to this file instead of stdout")
+	fs.IntVar(&c.FileMaxSize, "log-file-max-size", c.FileMaxSize, "size in megabytes at which the log file is rotated")
+	fs.IntVar(&c.FileMaxBackups, "log-file-max-backups", c.FileMaxBackups, "number of rotated log files to keep")
+}
+
+// Validate reports the first invalid setting
+func (c Config) Validate() error {
+	switch c.Format {
+	case FormatConsole, FormatJSON:
+	default:
+		return fmt.Errorf("log format %q: must be %s or %s", c.Format, FormatConsole, FormatJSON)
+	}
+	if _, err := zerolog.ParseLevel(strings.ToLower(c.Level)); err != nil || c.Level == "" {
+		return fmt.Errorf("log level %q: unknown level", c.Level)
+	}
+	if c.File != "" && (c.FileMaxSize <= 0 || c.FileMaxBackups < 0) {
+		return fmt.Errorf("log file: max size must be positive and max backups must not be negative")
+	}
+	return nil
+}
+
+// New creates a logger writing to w; the configuration must be valid
+func New(cfg Config, w io.Writer) (zerolog.Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return zerolog.Nop(), err
+	}
+	level, _ := zerolog.ParseLevel(strings.ToLower(cfg.Level))
+
+	if cfg.Format == FormatConsole {
+		w = zerolog.NewConsoleWriter(func(cw *zerolog.ConsoleWriter) {
+			cw.Out = w
+			cw.TimeFormat = time.RFC822
+			cw.NoColor = cfg.File != ""
+		})
+	}
+	logger := zerolog.New(w).Level(level).With().Timestamp()
+	if cfg.Caller {
+		logger = logger.CallerWithSkipFrameCount(skipFrameCount)
+	}
+	return logger.Logger(), nil
+}
+
+// Configure replaces the DefaultLogger with one built from cfg and applies its
+// redaction setting; close the returned io.Closer on shutdown to close the log file
+func Configure(cfg Config) (io.Closer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var w io.WriteCloser = nopCloser{Writer: os.Stdout}
+	if cfg.File != "" {
+		file, err := NewRotatingFile(cfg.File, int64(cfg.FileMaxSize)<<20, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w = file
+	}
+	logger, err := New(cfg, w)
+	if err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	DefaultLogger = logger
+	redact.Store(cfg.Redact)
+	return w, nil
+}
+
+// WebLoggerOptions returns the WebLogger options that apply the sampling and redaction settings
+func (c Config) WebLoggerOptions() []WebLoggerOption {
+	var options []WebLoggerOption
+	if c.SampleSuccess > 1 {
+		options = append(options, WithSuccessSampling(uint32(c.SampleSuccess)))
+	}
+	if c.Redact {
+		options = append(options, WithRedaction())
+	}
+	return options
+}
+
+// Redact returns s, or a placeholder when redaction is enabled; use it for todo descriptions
+func Redact(s string) string {
+	if redact.Load() {
+		return redacted
+	}
+	return s
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,163 @@
+package log
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+)
+
+func TestConfig_LoadEnvAndFlags(t *testing.T) {
+	tests := map[string]struct {
+		env     map[string]string
+		args    []string
+		want    Config
+		wantErr bool
+	}{
+		"Defaults": {
+			want: DefaultConfig(),
+		},
+		"Env": {
+			env: map[string]string{"LOG_FORMAT": "json", "LOG_LEVEL": "warn", "LOG_CALLER": "false", "LOG_SAMPLE_SUCCESS": "10", "LOG_REDACT": "true"},
+			want: func() Config {
+				c := DefaultConfig()
+				c.Format, c.Level, c.Caller, c.SampleSuccess, c.Redact = FormatJSON, "warn", false, 10, true
+				return c
+			}(),
+		},
+		"FlagsOverrideEnv": {
+			env:  map[string]string{"LOG_FORMAT": "json", "LOG_LEVEL": "warn"},
+			args: []string{"-log-level", "error", "-log-file", "server.log"},
+			want: func() Config {
+				c := DefaultConfig()
+				c.Format, c.Level, c.File = FormatJSON, "error", "server.log"
+				return c
+			}(),
+		},
+		"InvalidEnv": {
+			env:     map[string]string{"LOG_CALLER": "sometimes"},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			err := cfg.LoadEnv(func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			fs := flag.NewFlagSet(name, flag.ContinueOnError)
+			cfg.RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if cfg != tt.want {
+				t.Errorf("config = %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		change  func(c *Config)
+		wantErr bool
+	}{
+		"Default":      {change: func(c *Config) {}},
+		"JSON":         {change: func(c *Config) { c.Format = FormatJSON }},
+		"UpperLevel":   {change: func(c *Config) { c.Level = "WARN" }},
+		"BadFormat":    {change: func(c *Config) { c.Format = "xml" }, wantErr: true},
+		"BadLevel":     {change: func(c *Config) { c.Level = "loud" }, wantErr: true},
+		"EmptyLevel":   {change: func(c *Config) { c.Level = "" }, wantErr: true},
+		"BadFileLimit": {change: func(c *Config) { c.File, c.FileMaxSize = "x.log", 0 }, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.change(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		cfg        Config
+		wantLines  int
+		wantCaller bool
+		wantJSON   bool
+	}{
+		"JSON": {
+			cfg:       Config{Format: FormatJSON, Level: "info"},
+			wantLines: 2,
+			wantJSON:  true,
+		},
+		"JSONWithCaller": {
+			cfg:        Config{Format: FormatJSON, Level: "info", Caller: true},
+			wantLines:  2,
+			wantCaller: true,
+			wantJSON:   true,
+		},
+		"WarnLevel": {
+			cfg:       Config{Format: FormatJSON, Level: "warn"},
+			wantLines: 1,
+			wantJSON:  true,
+		},
+		"Console": {
+			cfg:       Config{Format: FormatConsole, Level: "debug"},
+			wantLines: 3,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(tt.cfg, &buf)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			logger.Debug().Msg("debug")
+			logger.Info().Msg("info")
+			logger.Warn().Msg("warn")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != tt.wantLines {
+				t.Fatalf("wrote %d lines, want %d:\n%s", len(lines), tt.wantLines, buf.String())
+			}
+			if !tt.wantJSON {
+				return
+			}
+			var line map[string]any
+			if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+				t.Fatalf("line %q is not JSON: %v", lines[0], err)
+			}
+			if _, ok := line["caller"]; ok != tt.wantCaller {
+				t.Errorf("caller present = %v, want %v", ok, tt.wantCaller)
+			}
+			if _, ok := line["time"]; !ok {
+				t.Errorf("line %q has no timestamp", lines[0])
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	defer redact.Store(false)
+
+	if got := Redact("Bake a cake"); got != "Bake a cake" {
+		t.Errorf("Redact() = %q without redaction", got)
+	}
+	redact.Store(true)
+	if got := Redact("Bake a cake"); got != redacted {
+		t.Errorf("Redact() = %q, want %q", got, redacted)
+	}
+}
@@ -1,7 +1,7 @@
 package log
 
 import (
-	"time"
+	"os"
 
 	"github.com/rs/zerolog"
 )
@@ -9,19 +9,8 @@ import (
 // skip the first 2 callers when logging
 const skipFrameCount = 2
 
-// DefaultLogger is the default logger used by the package-level functions.
-var DefaultLogger = zerolog.New(
-	zerolog.NewConsoleWriter(
-		func(w *zerolog.ConsoleWriter) {
-			w.TimeFormat = time.RFC822
-		},
-	),
-).
-	Level(zerolog.DebugLevel).
-	With().
-	CallerWithSkipFrameCount(skipFrameCount).
-	Timestamp().
-	Logger()
+// DefaultLogger is the default logger used by the package-level functions; replace it with Configure.
+var DefaultLogger, _ = New(DefaultConfig(), os.Stdout)
 
 // Trace returns a new event with the given message and level set to zerolog.TraceLevel.
 func Trace() *zerolog.Event {
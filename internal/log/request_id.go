@@ -0,0 +1,71 @@
+package log
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
+)
+
+// RequestIDHeader names the header that carries the request ID between the proxy and the server
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps the length of request IDs accepted from clients
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID is a http.Handler middleware that accepts the X-Request-ID of a request, or
+// generates one, and attaches a child of logger that carries it to the request context.
+//
+// The ID is echoed in the response; use Ctx to log with the child logger.
+func RequestID(logger zerolog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			id := request.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			writer.Header().Set(RequestIDHeader, id)
+
+			ctx := context.WithValue(request.Context(), requestIDKey{}, id)
+			child := logger.With().Str("RequestID", id)
+			if sc, ok := trace.SpanContextFromContext(ctx); ok {
+				child = child.Str("TraceID", sc.TraceID.String())
+			}
+			l := child.Logger()
+
+			next.ServeHTTP(writer, request.WithContext(l.WithContext(ctx)))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID attached by RequestID, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Ctx returns the logger attached to ctx by RequestID, or the DefaultLogger.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return l
+	}
+	return &DefaultLogger
+}
+
+// validRequestID accepts short IDs made of printable ASCII so they are safe to log and echo
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
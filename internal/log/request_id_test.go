@@ -0,0 +1,68 @@
+package log
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/encoding/json"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := map[string]struct {
+		header   string
+		wantSame bool
+	}{
+		"Generated":    {},
+		"Accepted":     {header: "proxy-1234", wantSame: true},
+		"TooLong":      {header: strings.Repeat("a", maxRequestIDLength+1)},
+		"NotPrintable": {header: "bad id\n"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var gotID string
+			handler := RequestID(zerolog.New(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotID = RequestIDFromContext(r.Context())
+				Ctx(r.Context()).Info().Msg("handled")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantSame && gotID != tt.header {
+				t.Errorf("request ID = %q, want %q", gotID, tt.header)
+			}
+			if !tt.wantSame {
+				if _, err := uuid.Parse(gotID); err != nil {
+					t.Errorf("request ID = %q, want a generated uuid", gotID)
+				}
+			}
+			if got := rec.Header().Get(RequestIDHeader); got != gotID {
+				t.Errorf("response %s = %q, want %q", RequestIDHeader, got, gotID)
+			}
+
+			var line map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatalf("log line %q is not JSON: %v", buf.String(), err)
+			}
+			if line["RequestID"] != gotID {
+				t.Errorf("log line RequestID = %v, want %q", line["RequestID"], gotID)
+			}
+		})
+	}
+}
+
+func TestCtx_WithoutRequestID(t *testing.T) {
+	if got := Ctx(httptest.NewRequest(http.MethodGet, "/", nil).Context()); got != &DefaultLogger {
+		t.Errorf("Ctx() = %p, want the DefaultLogger", got)
+	}
+}
@@ -0,0 +1,143 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+type (
+	// RotatingFile is an io.WriteCloser that renames the file to name.1 once it reaches
+	// its size limit, shifting older files up to name.<maxBackups> and deleting the rest
+	RotatingFile struct {
+		name       string
+		maxSize    int64
+		maxBackups int
+		fs         fileSystem
+
+		mu   sync.Mutex
+		file io.WriteCloser
+		size int64
+	}
+
+	// fileSystem holds the file operations used by RotatingFile so tests can run in memory
+	fileSystem struct {
+		open   func(name string) (io.WriteCloser, int64, error)
+		rename func(oldName, newName string) error
+		remove func(name string) error
+	}
+)
+
+var _ io.WriteCloser = (*RotatingFile)(nil)
+
+var osFileSystem = fileSystem{
+	open: func(name string) (io.WriteCloser, int64, error) {
+		file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	},
+	rename: os.Rename,
+	remove: os.Remove,
+}
+
+// NewRotatingFile opens, or creates, the file and appends to it
+func NewRotatingFile(name string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	return newRotatingFile(name, maxSize, maxBackups, osFileSystem)
+}
+
+func newRotatingFile(name string, maxSize int64, maxBackups int, fs fileSystem) (*RotatingFile, error) {
+	r := &RotatingFile{
+		name:       name,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		fs:         fs,
+	}
+	var err error
+	if r.file, r.size, err = fs.open(name); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write rotates the file first when p would take it past its size limit; a single
+// write larger than the limit is never split. A rotation that fails is reported, but p
+// is still written to the file when it could be opened again.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		// an earlier rotation could not open the file again
+		if err := r.reopen(); err != nil {
+			return 0, err
+		}
+	}
+	var rotateErr error
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if rotateErr = r.rotate(); r.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate moves the file to the first backup and opens a new one; whatever fails, the
+// file is opened again, so that r.file is only nil when that fails too
+func (r *RotatingFile) rotate() error {
+	err := r.file.Close()
+	r.file = nil
+	if err == nil {
+		err = r.shift()
+	}
+	return errors.Join(err, r.reopen())
+}
+
+// shift moves the file and its backups up by one, dropping the oldest
+func (r *RotatingFile) shift() error {
+	if r.maxBackups == 0 {
+		_ = r.fs.remove(r.name)
+		return nil
+	}
+	_ = r.fs.remove(r.backup(r.maxBackups))
+	for i := r.maxBackups - 1; i > 0; i-- {
+		_ = r.fs.rename(r.backup(i), r.backup(i+1))
+	}
+	return r.fs.rename(r.name, r.backup(1))
+}
+
+func (r *RotatingFile) reopen() error {
+	file, size, err := r.fs.open(r.name)
+	if err != nil {
+		return err
+	}
+	r.file, r.size = file, size
+	return nil
+}
+
+func (r *RotatingFile) backup(i int) string {
+	return r.name + "." + strconv.Itoa(i)
+}
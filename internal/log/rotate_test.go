@@ -0,0 +1,202 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// memFiles is an in-memory fileSystem keyed by file name
+type memFiles map[string]*bytes.Buffer
+
+func (m memFiles) fileSystem() fileSystem {
+	return fileSystem{
+		open: func(name string) (io.WriteCloser, int64, error) {
+			if m[name] == nil {
+				m[name] = &bytes.Buffer{}
+			}
+			return nopCloser{Writer: m[name]}, int64(m[name].Len()), nil
+		},
+		rename: func(oldName, newName string) error {
+			if m[oldName] == nil {
+				return os.ErrNotExist
+			}
+			m[newName] = m[oldName]
+			delete(m, oldName)
+			return nil
+		},
+		remove: func(name string) error {
+			if m[name] == nil {
+				return os.ErrNotExist
+			}
+			delete(m, name)
+			return nil
+		},
+	}
+}
+
+func (m memFiles) contents() map[string]string {
+	out := make(map[string]string, len(m))
+	for name, buf := range m {
+		out[name] = buf.String()
+	}
+	return out
+}
+
+func TestRotatingFile(t *testing.T) {
+	tests := map[string]struct {
+		existing   string
+		maxBackups int
+		writes     []string
+		want       map[string]string
+	}{
+		"UnderLimit": {
+			maxBackups: 2,
+			writes:     []string{"aaa", "bbb"},
+			want:       map[string]string{"app.log": "aaabbb"},
+		},
+		"Rotates": {
+			maxBackups: 2,
+			writes:     []string{"aaaa", "bbbb", "cccc"},
+			want:       map[string]string{"app.log": "cccc", "app.log.1": "bbbb", "app.log.2": "aaaa"},
+		},
+		"DropsOldestBackup": {
+			maxBackups: 2,
+			writes:     []string{"aaaa", "bbbb", "cccc", "dddd"},
+			want:       map[string]string{"app.log": "dddd", "app.log.1": "cccc", "app.log.2": "bbbb"},
+		},
+		"NoBackups": {
+			maxBackups: 0,
+			writes:     []string{"aaaa", "bbbb"},
+			want:       map[string]string{"app.log": "bbbb"},
+		},
+		"AppendsToExisting": {
+			existing:   "zzzz",
+			maxBackups: 1,
+			writes:     []string{"aaaa"},
+			want:       map[string]string{"app.log": "aaaa", "app.log.1": "zzzz"},
+		},
+		"LargeWriteIsNotSplit": {
+			maxBackups: 1,
+			writes:     []string{"aaaaaaaaaaaa"},
+			want:       map[string]string{"app.log": "aaaaaaaaaaaa"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			files := memFiles{}
+			if tt.existing != "" {
+				files["app.log"] = bytes.NewBufferString(tt.existing)
+			}
+			file, err := newRotatingFile("app.log", 6, tt.maxBackups, files.fileSystem())
+			if err != nil {
+				t.Fatalf("newRotatingFile() error = %v", err)
+			}
+			for _, w := range tt.writes {
+				if _, err := file.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := file.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			got := files.contents()
+			if len(got) != len(tt.want) {
+				t.Fatalf("files = %v, want %v", names(got), names(tt.want))
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatingFile_OpenError(t *testing.T) {
+	fs := memFiles{}.fileSystem()
+	fs.open = func(string) (io.WriteCloser, int64, error) {
+		return nil, 0, errors.New("read-only")
+	}
+	if _, err := newRotatingFile("app.log", 6, 1, fs); err == nil {
+		t.Errorf("newRotatingFile() expected an error")
+	}
+}
+
+func TestRotatingFile_RotateError(t *testing.T) {
+	tests := map[string]struct {
+		failOpen   bool
+		failRename bool
+		// want is the content of each file once the second write has been retried
+		want map[string]string
+	}{
+		"OpenFails": {
+			failOpen: true,
+			want:     map[string]string{"app.log": "world\n", "app.log.1": "hello\n"},
+		},
+		"RenameFails": {
+			failRename: true,
+			want:       map[string]string{"app.log": "hello\nworld\n"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			files := memFiles{}
+			fs := files.fileSystem()
+			open, rename := fs.open, fs.rename
+			var failing bool
+			fs.open = func(name string) (io.WriteCloser, int64, error) {
+				if failing && tt.failOpen {
+					return nil, 0, errors.New("read-only")
+				}
+				return open(name)
+			}
+			fs.rename = func(oldName, newName string) error {
+				if failing && tt.failRename {
+					return errors.New("read-only")
+				}
+				return rename(oldName, newName)
+			}
+			file, err := newRotatingFile("app.log", 6, 1, fs)
+			if err != nil {
+				t.Fatalf("newRotatingFile() error = %v", err)
+			}
+			if _, err := file.Write([]byte("hello\n")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			failing = true
+			if _, err := file.Write([]byte("world\n")); err == nil {
+				t.Errorf("Write() during a failing rotation expected an error")
+			}
+			failing = false
+			if tt.failOpen {
+				// the line was lost with the file; it is written again once the file opens
+				if _, err := file.Write([]byte("world\n")); err != nil {
+					t.Fatalf("Write() after the failure error = %v", err)
+				}
+			}
+			if err := file.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if got := files.contents(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func names(files map[string]string) []string {
+	var out []string
+	for name := range files {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
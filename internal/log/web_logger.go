@@ -9,10 +9,48 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	"github.com/stackus/errors"
+
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
+)
+
+type (
+	// WebLoggerOption configures a WebLogger
+	WebLoggerOption func(*webLoggerConfig)
+
+	webLoggerConfig struct {
+		sampleSuccess uint32
+		redact        bool
+	}
 )
 
+// WithSuccessSampling logs only one in every n requests answered with a 2xx status
+func WithSuccessSampling(n uint32) WebLoggerOption {
+	return func(cfg *webLoggerConfig) {
+		cfg.sampleSuccess = n
+	}
+}
+
+// WithRedaction leaves query strings out of the logged request URIs
+func WithRedaction() WebLoggerOption {
+	return func(cfg *webLoggerConfig) {
+		cfg.redact = true
+	}
+}
+
 // WebLogger is a http.Handler middleware that logs HTTP requests using zerolog.Logger.
-func WebLogger(logger zerolog.Logger) func(next http.Handler) http.Handler {
+//
+// Requests are correlated with their trace and request ID when trace.Middleware and
+// RequestID run before it.
+func WebLogger(logger zerolog.Logger, options ...WebLoggerOption) func(next http.Handler) http.Handler {
+	cfg := webLoggerConfig{}
+	for _, option := range options {
+		option(&cfg)
+	}
+	success := logger
+	if cfg.sampleSuccess > 1 {
+		success = logger.Sample(&zerolog.BasicSampler{N: cfg.sampleSuccess})
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			ww := middleware.NewWrapResponseWriter(writer, request.ProtoMajor)
@@ -34,6 +72,8 @@ func WebLogger(logger zerolog.Logger) func(next http.Handler) http.Handler {
 						ww.WriteHeader(http.StatusInternalServerError)
 					}
 					err = errors.ErrInternalServerError.Msgf("%s", p)
+				case ww.Status() < 300:
+					logFn = success.Info
 				case ww.Status() < 400:
 					logFn = logger.Info
 				case ww.Status() < 500:
@@ -49,11 +89,26 @@ func WebLogger(logger zerolog.Logger) func(next http.Handler) http.Handler {
 					log = log.Strs("Stack", strings.Split(string(stack), "\n"))
 				}
 
+				if id := RequestIDFromContext(request.Context()); id != "" {
+					log = log.Str("RequestID", id)
+				}
+				if sc, ok := trace.SpanContextFromContext(request.Context()); ok {
+					log = log.Str("TraceID", sc.TraceID.String()).Str("SpanID", sc.SpanID.String())
+				}
+
 				log = log.Str("RemoteAddr", request.RemoteAddr).
 					Int("ContentLength", ww.BytesWritten()).
 					Dur("ResponseTime", time.Since(start))
 
-				log.Msgf("[%d] %s %s", ww.Status(), request.Method, request.RequestURI)
+				uri := request.RequestURI
+				if cfg.redact {
+					uri = request.URL.Path
+				} else if query := request.URL.Query(); query.Has(accessTokenParam) {
+					// the calendar feed takes API tokens in its URL, which are never logged
+					query.Set(accessTokenParam, redacted)
+					uri = request.URL.Path + "?" + query.Encode()
+				}
+				log.Msgf("[%d] %s %s", ww.Status(), request.Method, uri)
 			}()
 
 			next.ServeHTTP(ww, request)
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWebLogger(t *testing.T) {
+	tests := map[string]struct {
+		options   []WebLoggerOption
+		target    string
+		status    int
+		requests  int
+		wantLines int
+		wantURI   string
+	}{
+		"LogsEveryRequest": {
+			status:    http.StatusOK,
+			requests:  4,
+			wantLines: 4,
+			wantURI:   "/todos?search=cake",
+		},
+		"SamplesSuccess": {
+			options:   []WebLoggerOption{WithSuccessSampling(2)},
+			status:    http.StatusOK,
+			requests:  4,
+			wantLines: 2,
+		},
+		"NeverSamplesErrors": {
+			options:   []WebLoggerOption{WithSuccessSampling(2)},
+			status:    http.StatusInternalServerError,
+			requests:  4,
+			wantLines: 4,
+		},
+		"NeverSamplesRedirects": {
+			options:   []WebLoggerOption{WithSuccessSampling(2)},
+			status:    http.StatusFound,
+			requests:  2,
+			wantLines: 2,
+		},
+		"RedactsQueryStrings": {
+			options:   []WebLoggerOption{WithRedaction()},
+			status:    http.StatusOK,
+			requests:  1,
+			wantLines: 1,
+			wantURI:   "GET /todos\"",
+		},
+		"RedactsAccessTokens": {
+			target:    "/todos.ics?access_token=todo_secret",
+			status:    http.StatusOK,
+			requests:  1,
+			wantLines: 1,
+			wantURI:   "/todos.ics?access_token=%5BREDACTED%5D",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := WebLogger(zerolog.New(&buf), tt.options...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			target := tt.target
+			if target == "" {
+				target = "/todos?search=cake"
+			}
+			for i := 0; i < tt.requests; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != tt.wantLines {
+				t.Fatalf("wrote %d lines, want %d:\n%s", len(lines), tt.wantLines, buf.String())
+			}
+			if tt.wantURI != "" && !strings.Contains(lines[0], tt.wantURI) {
+				t.Errorf("line %q does not contain %q", lines[0], tt.wantURI)
+			}
+			if len(tt.options) > 0 && tt.wantURI != "" && strings.Contains(buf.String(), "cake") {
+				t.Errorf("query string was logged: %s", buf.String())
+			}
+			if strings.Contains(buf.String(), "todo_secret") {
+				t.Errorf("API token was logged: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels requests that did not match any route, which keeps the
+// number of series bounded
+const unmatchedRoute = "unmatched"
+
+// Middleware is a http.Handler middleware that records request counts, latencies,
+// response sizes and in-flight requests per chi route pattern
+func Middleware(reg *Registry) func(next http.Handler) http.Handler {
+	requests := reg.NewCounter("http_requests_total", "Count of HTTP requests.", "method", "route", "status")
+	durations := reg.NewHistogram("http_request_duration_seconds", "Latency of HTTP requests.", DefaultBuckets, "method", "route")
+	sizes := reg.NewHistogram("http_response_size_bytes", "Size of HTTP responses.", SizeBuckets, "method", "route")
+	inFlight := reg.NewGauge("http_requests_in_flight", "Number of HTTP requests being served.")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ww := middleware.NewWrapResponseWriter(writer, request.ProtoMajor)
+
+			inFlight.Add(1)
+			start := time.Now()
+
+			defer func() {
+				inFlight.Add(-1)
+
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				route := routePattern(request)
+
+				requests.With(request.Method, route, strconv.Itoa(status)).Inc()
+				durations.With(request.Method, route).Observe(time.Since(start).Seconds())
+				sizes.With(request.Method, route).Observe(float64(ww.BytesWritten()))
+			}()
+
+			next.ServeHTTP(ww, request)
+		})
+	}
+}
+
+// routePattern is read after the request was served, once chi has matched every sub-router
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return unmatchedRoute
+	}
+	if pattern := rctx.RoutePattern(); pattern != "" {
+		return pattern
+	}
+	return unmatchedRoute
+}
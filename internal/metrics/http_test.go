@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := map[string]struct {
+		method string
+		target string
+		want   []string
+	}{
+		"RoutePattern": {
+			method: http.MethodGet,
+			target: "/todos/123",
+			want: []string{
+				`http_requests_total{method="GET",route="/todos/{todoId}",status="200"} 1`,
+				`http_request_duration_seconds_count{method="GET",route="/todos/{todoId}"} 1`,
+				`http_response_size_bytes_bucket{method="GET",route="/todos/{todoId}",le="100"} 1`,
+				`http_response_size_bytes_sum{method="GET",route="/todos/{todoId}"} 3`,
+				`http_requests_in_flight 0`,
+			},
+		},
+		"Status": {
+			method: http.MethodPost,
+			target: "/todos",
+			want: []string{
+				`http_requests_total{method="POST",route="/todos",status="201"} 1`,
+			},
+		},
+		"Unmatched": {
+			method: http.MethodGet,
+			target: "/nowhere/at/all",
+			want: []string{
+				`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			reg := NewRegistry()
+			router := chi.NewRouter()
+			router.Use(Middleware(reg))
+			router.Route("/todos", func(r chi.Router) {
+				r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusCreated)
+				})
+				r.Get("/{todoId}", func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(chi.URLParam(r, "todoId")))
+				})
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.target, nil))
+
+			var sb strings.Builder
+			if _, err := reg.WriteTo(&sb); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sb.String(), want+"\n") {
+					t.Errorf("metrics missing %q in\n%s", want, sb.String())
+				}
+			}
+		})
+	}
+}
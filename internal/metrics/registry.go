@@ -0,0 +1,345 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	// DefaultBuckets are latency buckets in seconds
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// SizeBuckets are response size buckets in bytes
+	SizeBuckets = []float64{100, 1000, 10_000, 100_000, 1_000_000, 10_000_000}
+)
+
+type (
+	// Registry holds metrics and writes them in the Prometheus text exposition format
+	Registry struct {
+		mu       sync.Mutex
+		families map[string]*family
+		scrapes  []func()
+	}
+
+	// Counter is a monotonically increasing value per set of label values
+	Counter struct{ f *family }
+	// Gauge is a value per set of label values that may go up and down
+	Gauge struct{ f *family }
+	// Histogram counts observations per set of label values into buckets
+	Histogram struct{ f *family }
+
+	family struct {
+		name    string
+		help    string
+		kind    string
+		labels  []string
+		buckets []float64
+
+		mu     sync.Mutex
+		series map[string]*series
+	}
+
+	series struct {
+		values []string
+
+		mu     sync.Mutex
+		value  float64
+		counts []uint64
+		sum    float64
+		count  uint64
+	}
+)
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// NewCounter registers a counter; it panics if the name is already registered
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{f: r.register(name, help, "counter", labels, nil)}
+}
+
+// NewGauge registers a gauge; it panics if the name is already registered
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{f: r.register(name, help, "gauge", labels, nil)}
+}
+
+// NewHistogram registers a histogram with the given upper bounds; it panics if the
+// name is already registered
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{f: r.register(name, help, "histogram", labels, buckets)}
+}
+
+// OnScrape registers a function that is called before every scrape; use it to set
+// gauges whose values are read from somewhere else
+func (r *Registry) OnScrape(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scrapes = append(r.scrapes, fn)
+}
+
+func (r *Registry) register(name, help, kind string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.families[name]; exists {
+		panic(fmt.Sprintf("metrics: %s is already registered", name))
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	if len(labels) == 0 {
+		// metrics without labels are exposed from the start
+		f.get(nil)
+	}
+	r.families[name] = f
+	return f
+}
+
+// WriteTo writes every metric in the text exposition format, sorted by name
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	scrapes := append([]func(){}, r.scrapes...)
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+
+	for _, fn := range scrapes {
+		fn()
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, f := range families {
+		f.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler serves the metrics of the registry
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// With returns the counter for the label values, in the order the labels were registered
+func (c *Counter) With(values ...string) *Counter {
+	return &Counter{f: c.f.child(values)}
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds a non-negative value to the counter
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	s := c.f.get(nil)
+	s.mu.Lock()
+	s.value += v
+	s.mu.Unlock()
+}
+
+// set replaces the value of a counter that mirrors a total kept elsewhere
+func (c *Counter) set(v float64) {
+	s := c.f.get(nil)
+	s.mu.Lock()
+	s.value = v
+	s.mu.Unlock()
+}
+
+// With returns the gauge for the label values, in the order the labels were registered
+func (g *Gauge) With(values ...string) *Gauge {
+	return &Gauge{f: g.f.child(values)}
+}
+
+// Set replaces the value of the gauge
+func (g *Gauge) Set(v float64) {
+	s := g.f.get(nil)
+	s.mu.Lock()
+	s.value = v
+	s.mu.Unlock()
+}
+
+// Add adds a value, which may be negative, to the gauge
+func (g *Gauge) Add(v float64) {
+	s := g.f.get(nil)
+	s.mu.Lock()
+	s.value += v
+	s.mu.Unlock()
+}
+
+// With returns the histogram for the label values, in the order the labels were registered
+func (h *Histogram) With(values ...string) *Histogram {
+	return &Histogram{f: h.f.child(values)}
+}
+
+// Observe records a single observation
+func (h *Histogram) Observe(v float64) {
+	s := h.f.get(nil)
+	i := sort.SearchFloat64s(h.f.buckets, v)
+	s.mu.Lock()
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	s.mu.Unlock()
+}
+
+// child returns a view of the family that is bound to a single series
+func (f *family) child(values []string) *family {
+	s := f.get(values)
+	return &family{
+		name:    f.name,
+		kind:    f.kind,
+		buckets: f.buckets,
+		series:  map[string]*series{"": s},
+	}
+}
+
+// get returns the series for the label values, creating it on first use; a bound
+// child family holds exactly one series under the empty key
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, exists := f.series[key]
+	if !exists {
+		s = &series{
+			values: append([]string(nil), values...),
+			counts: make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	all := make([]*series, len(keys))
+	for i, key := range keys {
+		all[i] = f.series[key]
+	}
+	f.mu.Unlock()
+
+	if len(all) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+	for _, s := range all {
+		s.mu.Lock()
+		if f.kind != "histogram" {
+			writeSample(w, f.name, f.labels, s.values, "", "", s.value)
+			s.mu.Unlock()
+			continue
+		}
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, f.name+"_bucket", f.labels, s.values, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(w, f.name+"_bucket", f.labels, s.values, "le", "+Inf", float64(s.count))
+		writeSample(w, f.name+"_sum", f.labels, s.values, "", "", s.sum)
+		writeSample(w, f.name+"_count", f.labels, s.values, "", "", float64(s.count))
+		s.mu.Unlock()
+	}
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, v float64) {
+	_, _ = w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		_ = w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabel(values[i]))
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", extraLabel, extraValue)
+		}
+		_ = w.WriteByte('}')
+	}
+	_ = w.WriteByte(' ')
+	_, _ = w.WriteString(formatFloat(v))
+	_ = w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	tests := map[string]struct {
+		record func(reg *Registry)
+		want   string
+	}{
+		"Counter": {
+			record: func(reg *Registry) {
+				c := reg.NewCounter("requests_total", "Count of requests.", "method")
+				c.With("POST").Inc()
+				c.With("GET").Add(2)
+				c.With("GET").Add(-1)
+			},
+			want: `# HELP requests_total Count of requests.
+# TYPE requests_total counter
+requests_total{method="GET"} 2
+requests_total{method="POST"} 1
+`,
+		},
+		"Gauge": {
+			record: func(reg *Registry) {
+				g := reg.NewGauge("in_flight", "Requests in flight.")
+				g.Add(3)
+				g.Add(-1)
+			},
+			want: `# HELP in_flight Requests in flight.
+# TYPE in_flight gauge
+in_flight 2
+`,
+		},
+		"Histogram": {
+			record: func(reg *Registry) {
+				h := reg.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.5}, "route")
+				h.With("/").Observe(0.25)
+				h.With("/").Observe(0.5)
+				h.With("/").Observe(2)
+			},
+			want: `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/",le="0.5"} 2
+latency_seconds_bucket{route="/",le="1"} 2
+latency_seconds_bucket{route="/",le="+Inf"} 3
+latency_seconds_sum{route="/"} 2.75
+latency_seconds_count{route="/"} 3
+`,
+		},
+		"EscapedValues": {
+			record: func(reg *Registry) {
+				reg.NewGauge("escaped", "A \\ help\ntext.", "value").With("a \"quoted\"\\\nvalue").Set(1)
+			},
+			want: `# HELP escaped A \\ help\ntext.
+# TYPE escaped gauge
+escaped{value="a \"quoted\"\\\nvalue"} 1
+`,
+		},
+		"SortedByName": {
+			record: func(reg *Registry) {
+				reg.NewGauge("b", "B.").Set(2)
+				reg.NewGauge("a", "A.").Set(1)
+			},
+			want: `# HELP a A.
+# TYPE a gauge
+a 1
+# HELP b B.
+# TYPE b gauge
+b 2
+`,
+		},
+		"UnusedVectorsAreOmitted": {
+			record: func(reg *Registry) {
+				reg.NewCounter("unused_total", "Unused.", "label")
+			},
+			want: "",
+		},
+		"OnScrape": {
+			record: func(reg *Registry) {
+				g := reg.NewGauge("scraped", "Scraped.")
+				reg.OnScrape(func() { g.Set(42) })
+			},
+			want: `# HELP scraped Scraped.
+# TYPE scraped gauge
+scraped 42
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			reg := NewRegistry()
+			tt.record(reg)
+
+			var sb strings.Builder
+			n, err := reg.WriteTo(&sb)
+			if err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteTo() got\n%s\nwant\n%s", got, tt.want)
+			}
+			if n != int64(sb.Len()) {
+				t.Errorf("WriteTo() n = %d, want %d", n, sb.Len())
+			}
+		})
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := map[string]func(reg *Registry){
+		"DuplicateName": func(reg *Registry) {
+			reg.NewCounter("dup_total", "Dup.")
+			reg.NewGauge("dup_total", "Dup.")
+		},
+		"WrongLabelCount": func(reg *Registry) {
+			reg.NewCounter("labels_total", "Labels.", "a", "b").With("a")
+		},
+		"MissingLabels": func(reg *Registry) {
+			reg.NewCounter("labels_total", "Labels.", "a").Inc()
+		},
+	}
+	for name, record := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+			record(NewRegistry())
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewGauge("up", "Up.").Set(1)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "\nup 1\n") {
+		t.Errorf("body = %q, missing the gauge", rec.Body.String())
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+type todoRepository struct {
+	repo      domain.TodoRepository
+	durations *Histogram
+}
+
+// Verify that todoRepository implements the TodoRepository interface
+var _ domain.TodoRepository = (*todoRepository)(nil)
+
+// NewTodoRepository decorates a repository to record the latency of every operation;
+// the number of todos by status is read from the repository when metrics are scraped,
+// if it can list the todos of every owner, which a remote one cannot
+func NewTodoRepository(repo domain.TodoRepository, reg *Registry) domain.TodoRepository {
+	if scanner, ok := repo.(domain.Scanner); ok {
+		counts := reg.NewGauge("todos", "Number of todos by status.", "status")
+		reg.OnScrape(func() {
+			list, err := scanner.Scan(context.Background())
+			if err != nil {
+				return
+			}
+			var completed int
+			for _, todo := range list {
+				if todo.Completed {
+					completed++
+				}
+			}
+			counts.With("active").Set(float64(len(list) - completed))
+			counts.With("completed").Set(float64(completed))
+		})
+	}
+
+	return todoRepository{
+		repo:      repo,
+		durations: reg.NewHistogram("todo_repository_operation_duration_seconds", "Latency of todo repository operations.", DefaultBuckets, "operation", "outcome"),
+	}
+}
+
+func (r todoRepository) Add(ctx context.Context, description string) (todo *domain.Todo, err error) {
+	defer r.observe("add", time.Now(), &err)
+	return r.repo.Add(ctx, description)
+}
+
+func (r todoRepository) Remove(ctx context.Context, id uuid.UUID) (err error) {
+	defer r.observe("remove", time.Now(), &err)
+	return r.repo.Remove(ctx, id)
+}
+
+func (r todoRepository) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (todo *domain.Todo, err error) {
+	defer r.observe("update", time.Now(), &err)
+	return r.repo.Update(ctx, id, completed, description)
+}
+
+func (r todoRepository) Search(ctx context.Context, search string) (todos []*domain.Todo, err error) {
+	defer r.observe("search", time.Now(), &err)
+	return r.repo.Search(ctx, search)
+}
+
+func (r todoRepository) All(ctx context.Context) (todos []*domain.Todo, err error) {
+	defer r.observe("all", time.Now(), &err)
+	return r.repo.All(ctx)
+}
+
+func (r todoRepository) Get(ctx context.Context, id uuid.UUID) (todo *domain.Todo, err error) {
+	defer r.observe("get", time.Now(), &err)
+	return r.repo.Get(ctx, id)
+}
+
+func (r todoRepository) Reorder(ctx context.Context, ids []uuid.UUID) (todos []*domain.Todo, err error) {
+	defer r.observe("reorder", time.Now(), &err)
+	return r.repo.Reorder(ctx, ids)
+}
+
+func (r todoRepository) Export(ctx context.Context, format string) (data []byte, err error) {
+	defer r.observe("export", time.Now(), &err)
+	return r.repo.Export(ctx, format)
+}
+
+func (r todoRepository) Import(ctx context.Context, todos []*domain.Todo, mode domain.ImportMode, dryRun bool) (report *domain.ImportReport, err error) {
+	defer r.observe("import", time.Now(), &err)
+	return r.repo.Import(ctx, todos, mode, dryRun)
+}
+
+func (r todoRepository) observe(operation string, start time.Time, err *error) {
+	outcome := "success"
+	if *err != nil {
+		outcome = "error"
+	}
+	r.durations.With(operation, outcome).Observe(time.Since(start).Seconds())
+}
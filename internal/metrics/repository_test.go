@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// scanningRepository is a repository that can list the todos of every owner
+type scanningRepository struct {
+	*domain.MockTodoRepository
+}
+
+func (r scanningRepository) Scan(ctx context.Context) ([]*domain.Todo, error) {
+	return r.All(ctx)
+}
+
+func TestTodoRepository(t *testing.T) {
+	id := uuid.New()
+	tests := map[string]struct {
+		call   func(repo domain.TodoRepository)
+		mock   func(m *domain.MockTodoRepository)
+		remote bool
+		want   []string
+	}{
+		"Success": {
+			call: func(repo domain.TodoRepository) { _, _ = repo.Get(context.Background(), id) },
+			mock: func(m *domain.MockTodoRepository) {
+				m.On("Get", mock.Anything, id).Return(&domain.Todo{ID: id}, nil)
+			},
+			want: []string{
+				`todo_repository_operation_duration_seconds_count{operation="get",outcome="success"} 1`,
+			},
+		},
+		"Error": {
+			call: func(repo domain.TodoRepository) { _ = repo.Remove(context.Background(), id) },
+			mock: func(m *domain.MockTodoRepository) {
+				m.On("Remove", mock.Anything, id).Return(errors.New("boom"))
+			},
+			want: []string{
+				`todo_repository_operation_duration_seconds_count{operation="remove",outcome="error"} 1`,
+			},
+		},
+		"Remote": {
+			call: func(repo domain.TodoRepository) { _, _ = repo.Get(context.Background(), id) },
+			mock: func(m *domain.MockTodoRepository) {
+				m.On("Get", mock.Anything, id).Return(&domain.Todo{ID: id}, nil)
+			},
+			remote: true,
+			want: []string{
+				`todo_repository_operation_duration_seconds_count{operation="get",outcome="success"} 1`,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := domain.NewMockTodoRepository(t)
+			tt.mock(m)
+			var repo domain.TodoRepository = m
+			want := tt.want
+			if !tt.remote {
+				m.On("All", mock.Anything).Return([]*domain.Todo{
+					{ID: uuid.New()},
+					{ID: uuid.New(), Completed: true},
+					{ID: uuid.New()},
+				}, nil)
+				repo = scanningRepository{m}
+				want = append(want, `todos{status="active"} 2`, `todos{status="completed"} 1`)
+			}
+
+			reg := NewRegistry()
+			tt.call(NewTodoRepository(repo, reg))
+
+			var sb strings.Builder
+			if _, err := reg.WriteTo(&sb); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			if tt.remote && strings.Contains(sb.String(), "todos{") {
+				t.Errorf("metrics of a remote repository count todos in\n%s", sb.String())
+			}
+			for _, line := range want {
+				if !strings.Contains(sb.String(), line+"\n") {
+					t.Errorf("metrics missing %q in\n%s", line, sb.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"runtime"
+)
+
+// RegisterRuntime adds Go runtime statistics to the registry; they are read when
+// metrics are scraped
+func RegisterRuntime(reg *Registry) {
+	reg.NewGauge("go_info", "Information about the Go environment.", "version").With(runtime.Version()).Set(1)
+
+	goroutines := reg.NewGauge("go_goroutines", "Number of goroutines that currently exist.")
+	heapAlloc := reg.NewGauge("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.")
+	heapObjects := reg.NewGauge("go_memstats_heap_objects", "Number of allocated objects.")
+	sys := reg.NewGauge("go_memstats_sys_bytes", "Number of bytes obtained from the system.")
+	lastGC := reg.NewGauge("go_memstats_last_gc_time_seconds", "Number of seconds since 1970 of last garbage collection.")
+	allocTotal := reg.NewCounter("go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.")
+	gcCycles := reg.NewCounter("go_gc_cycles_total", "Number of completed GC cycles.")
+
+	reg.OnScrape(func() {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		goroutines.Set(float64(runtime.NumGoroutine()))
+		heapAlloc.Set(float64(ms.HeapAlloc))
+		heapObjects.Set(float64(ms.HeapObjects))
+		sys.Set(float64(ms.Sys))
+		lastGC.Set(float64(ms.LastGC) / 1e9)
+		allocTotal.set(float64(ms.TotalAlloc))
+		gcCycles.set(float64(ms.NumGC))
+	})
+}
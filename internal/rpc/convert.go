@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	todosv1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+)
+
+// TodoToProto converts a todo into its protobuf message; a nil todo stays nil
+func TodoToProto(todo *domain.Todo) *todosv1.Todo {
+	if todo == nil {
+		return nil
+	}
+	msg := &todosv1.Todo{
+		Id:          todo.ID.String(),
+		Description: todo.Description,
+		Completed:   todo.Completed,
+		CreatedAt:   timestamppb.New(todo.CreatedAt),
+	}
+	if todo.DueAt != nil {
+		msg.DueAt = timestamppb.New(*todo.DueAt)
+	}
+	return msg
+}
+
+// TodoFromProto converts a protobuf message into a todo; a nil message stays nil
+func TodoFromProto(msg *todosv1.Todo) (*domain.Todo, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(msg.GetId())
+	if err != nil {
+		return nil, domain.ErrUnmarshaling{Err: err}
+	}
+	todo := &domain.Todo{
+		ID:          id,
+		Description: msg.GetDescription(),
+		Completed:   msg.GetCompleted(),
+		CreatedAt:   msg.GetCreatedAt().AsTime(),
+	}
+	if msg.DueAt != nil {
+		dueAt := msg.GetDueAt().AsTime()
+		todo.DueAt = &dueAt
+	}
+	return todo, nil
+}
+
+// TodosToProto converts a list of todos into protobuf messages
+func TodosToProto(todos []*domain.Todo) []*todosv1.Todo {
+	msgs := make([]*todosv1.Todo, len(todos))
+	for i, todo := range todos {
+		msgs[i] = TodoToProto(todo)
+	}
+	return msgs
+}
+
+// TodosFromProto converts protobuf messages into a list of todos
+func TodosFromProto(msgs []*todosv1.Todo) ([]*domain.Todo, error) {
+	todos := make([]*domain.Todo, len(msgs))
+	for i, msg := range msgs {
+		todo, err := TodoFromProto(msg)
+		if err != nil {
+			return nil, err
+		}
+		todos[i] = todo
+	}
+	return todos, nil
+}
+
+// ImportReportToProto converts an import report into its protobuf message
+func ImportReportToProto(report *domain.ImportReport) *todosv1.ImportReport {
+	return &todosv1.ImportReport{
+		Mode:      string(report.Mode),
+		DryRun:    report.DryRun,
+		Added:     TodosToProto(report.Added),
+		Updated:   TodosToProto(report.Updated),
+		Removed:   TodosToProto(report.Removed),
+		Unchanged: int32(report.Unchanged),
+	}
+}
+
+// ImportReportFromProto converts a protobuf message into an import report
+func ImportReportFromProto(msg *todosv1.ImportReport) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{
+		Mode:      domain.ImportMode(msg.GetMode()),
+		DryRun:    msg.GetDryRun(),
+		Unchanged: int(msg.GetUnchanged()),
+	}
+	var err error
+	if report.Added, err = TodosFromProto(msg.GetAdded()); err != nil {
+		return nil, err
+	}
+	if report.Updated, err = TodosFromProto(msg.GetUpdated()); err != nil {
+		return nil, err
+	}
+	if report.Removed, err = TodosFromProto(msg.GetRemoved()); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
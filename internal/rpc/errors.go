@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/bufbuild/connect-go"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	todosv1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+)
+
+// permissionHeader carries the permission a caller was denied in the metadata of the error
+const permissionHeader = "Todo-Permission"
+
+// ToConnectError converts a service error into a connect error; validation
+// failures carry their fields as a ValidationError detail
+func ToConnectError(err error) *connect.Error {
+	var validationErr domain.ErrValidation
+	if errors.As(err, &validationErr) {
+		connectErr := connect.NewError(connect.CodeInvalidArgument, err)
+		if detail, detailErr := connect.NewErrorDetail(&todosv1.ValidationError{Fields: validationErr.Fields}); detailErr == nil {
+			connectErr.AddDetail(detail)
+		}
+		return connectErr
+	}
+	var formatErr domain.ErrUnsupportedFormat
+	if errors.As(err, &formatErr) {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	var unauthenticatedErr domain.ErrUnauthenticated
+	if errors.As(err, &unauthenticatedErr) {
+		return connect.NewError(connect.CodeUnauthenticated, err)
+	}
+	var permissionErr domain.ErrPermissionDenied
+	if errors.As(err, &permissionErr) {
+		connectErr := connect.NewError(connect.CodePermissionDenied, err)
+		connectErr.Meta().Set(permissionHeader, string(permissionErr.Permission))
+		return connectErr
+	}
+	var apiErr domain.ErrIncompatibleAPI
+	if errors.As(err, &apiErr) {
+		connectErr := connect.NewError(connect.CodeFailedPrecondition, err)
+		connectErr.Meta().Set(domain.APIVersionHeader, apiErr.API)
+		return connectErr
+	}
+	return connect.NewError(connect.CodeInternal, err)
+}
+
+// FromConnectError converts an error returned by a client call back into a domain error
+func FromConnectError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return domain.ErrMakeRequest{Err: err}
+	}
+	if connectErr.Code() == connect.CodeUnauthenticated {
+		return domain.ErrUnauthenticated{}
+	}
+	if connectErr.Code() == connect.CodePermissionDenied {
+		if connectErr.Meta().Get(domain.CSRFHeader) == "invalid" {
+			return domain.ErrInvalidCSRFToken{}
+		}
+		return domain.ErrPermissionDenied{Permission: domain.Permission(connectErr.Meta().Get(permissionHeader))}
+	}
+	if connectErr.Code() == connect.CodeFailedPrecondition && connectErr.Meta().Get(domain.APIVersionHeader) != "" {
+		return domain.ErrIncompatibleAPI{API: connectErr.Meta().Get(domain.APIVersionHeader)}
+	}
+	for _, detail := range connectErr.Details() {
+		value, detailErr := detail.Value()
+		if detailErr != nil {
+			continue
+		}
+		if validationErr, ok := value.(*todosv1.ValidationError); ok {
+			return domain.ErrValidation{Fields: validationErr.GetFields()}
+		}
+	}
+	return domain.ErrMakeRequest{Err: err}
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+func TestConnectErrors(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		wantCode connect.Code
+		want     error
+	}{
+		"Validation": {
+			err:      domain.ErrValidation{Fields: map[string]string{"description": "must not be empty"}},
+			wantCode: connect.CodeInvalidArgument,
+			want:     domain.ErrValidation{Fields: map[string]string{"description": "must not be empty"}},
+		},
+		"UnsupportedFormat": {
+			err:      domain.ErrUnsupportedFormat{Format: "xml"},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		"Unauthenticated": {
+			err:      domain.ErrUnauthenticated{},
+			wantCode: connect.CodeUnauthenticated,
+			want:     domain.ErrUnauthenticated{},
+		},
+		"PermissionDenied": {
+			err:      domain.ErrPermissionDenied{Permission: domain.PermissionEdit},
+			wantCode: connect.CodePermissionDenied,
+			want:     domain.ErrPermissionDenied{Permission: domain.PermissionEdit},
+		},
+		"IncompatibleAPI": {
+			err:      domain.ErrIncompatibleAPI{API: "2.0"},
+			wantCode: connect.CodeFailedPrecondition,
+			want:     domain.ErrIncompatibleAPI{API: "2.0"},
+		},
+		"Internal": {
+			err:      errors.New("boom"),
+			wantCode: connect.CodeInternal,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			connectErr := ToConnectError(tt.err)
+			if connectErr.Code() != tt.wantCode {
+				t.Errorf("ToConnectError() Code = %v, want %v", connectErr.Code(), tt.wantCode)
+			}
+
+			got := FromConnectError(connectErr)
+			if tt.want != nil {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("FromConnectError() = %#v, want %#v", got, tt.want)
+				}
+				return
+			}
+			var requestErr domain.ErrMakeRequest
+			if !errors.As(got, &requestErr) {
+				t.Errorf("FromConnectError() = %#v, want ErrMakeRequest", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	todosv1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+	"github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1/todosv1connect"
+)
+
+// TodoClient is a TodoRepository backed by the TodosService RPC API
+//
+// It speaks the Connect protocol by default, which works over HTTP/1 and so
+// from the WASM client; pass connect.WithGRPC() or connect.WithGRPCWeb() to
+// use those protocols instead.
+type TodoClient struct {
+	client todosv1connect.TodosServiceClient
+}
+
+var _ domain.TodoRepository = (*TodoClient)(nil)
+
+func NewTodoClient(httpClient connect.HTTPClient, host string, options ...connect.ClientOption) *TodoClient {
+	return &TodoClient{
+		client: todosv1connect.NewTodosServiceClient(httpClient, host,
+			append([]connect.ClientOption{connect.WithInterceptors(traceInterceptor(), listInterceptor(), csrfInterceptor(), versionInterceptor())}, options...)...,
+		),
+	}
+}
+
+// listInterceptor sends the list selected in the context of every call to the server
+func listInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if listID, ok := domain.ListFromContext(ctx); ok {
+				req.Header().Set(domain.ListHeader, listID.String())
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// csrfInterceptor passes the CSRF token the page sent on to the server
+func csrfInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if token := domain.CSRFTokenFromContext(ctx); token != "" {
+				req.Header().Set(domain.CSRFHeader, token)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// versionInterceptor tells the server the API version the client speaks
+func versionInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			req.Header().Set(domain.APIVersionHeader, domain.APIVersion)
+			return next(ctx, req)
+		}
+	}
+}
+
+func (c *TodoClient) Add(ctx context.Context, description string) (*domain.Todo, error) {
+	resp, err := c.client.Add(ctx, connect.NewRequest(&todosv1.AddRequest{
+		Description: description,
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return TodoFromProto(resp.Msg.GetTodo())
+}
+
+func (c *TodoClient) Remove(ctx context.Context, id uuid.UUID) error {
+	_, err := c.client.Remove(ctx, connect.NewRequest(&todosv1.RemoveRequest{
+		Id: id.String(),
+	}))
+	if err != nil {
+		return FromConnectError(err)
+	}
+	return nil
+}
+
+func (c *TodoClient) Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*domain.Todo, error) {
+	resp, err := c.client.Update(ctx, connect.NewRequest(&todosv1.UpdateRequest{
+		Id:          id.String(),
+		Completed:   completed,
+		Description: description,
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return TodoFromProto(resp.Msg.GetTodo())
+}
+
+func (c *TodoClient) Search(ctx context.Context, search string) ([]*domain.Todo, error) {
+	resp, err := c.client.Search(ctx, connect.NewRequest(&todosv1.SearchRequest{
+		Search: search,
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return TodosFromProto(resp.Msg.GetTodos())
+}
+
+func (c *TodoClient) All(ctx context.Context) ([]*domain.Todo, error) {
+	return c.Search(ctx, "")
+}
+
+func (c *TodoClient) Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error) {
+	resp, err := c.client.Get(ctx, connect.NewRequest(&todosv1.GetRequest{
+		Id: id.String(),
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return TodoFromProto(resp.Msg.GetTodo())
+}
+
+func (c *TodoClient) Reorder(ctx context.Context, ids []uuid.UUID) ([]*domain.Todo, error) {
+	req := &todosv1.SortRequest{Ids: make([]string, len(ids))}
+	for i, id := range ids {
+		req.Ids[i] = id.String()
+	}
+	resp, err := c.client.Sort(ctx, connect.NewRequest(req))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return TodosFromProto(resp.Msg.GetTodos())
+}
+
+func (c *TodoClient) Export(ctx context.Context, format string) ([]byte, error) {
+	resp, err := c.client.Export(ctx, connect.NewRequest(&todosv1.ExportRequest{
+		Format: format,
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return resp.Msg.GetData(), nil
+}
+
+func (c *TodoClient) Import(ctx context.Context, todos []*domain.Todo, mode domain.ImportMode, dryRun bool) (*domain.ImportReport, error) {
+	codec, err := domain.CodecFor(domain.FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+	var data bytes.Buffer
+	if err := codec.Encode(&data, todos); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Import(ctx, connect.NewRequest(&todosv1.ImportRequest{
+		Format: domain.FormatJSON,
+		Data:   data.Bytes(),
+		Mode:   string(mode),
+		DryRun: dryRun,
+	}))
+	if err != nil {
+		return nil, FromConnectError(err)
+	}
+	return ImportReportFromProto(resp.Msg.GetReport())
+}
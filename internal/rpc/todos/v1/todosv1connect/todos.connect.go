@@ -0,0 +1,282 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: todos/v1/todos.proto
+
+package todosv1connect
+
+import (
+	context "context"
+	errors "errors"
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/stackus/todos-htmx-wasm/internal/rpc/todos/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// TodosServiceName is the fully-qualified name of the TodosService service.
+	TodosServiceName = "todos.v1.TodosService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TodosServiceAddProcedure is the fully-qualified name of the TodosService's Add RPC.
+	TodosServiceAddProcedure = "/todos.v1.TodosService/Add"
+	// TodosServiceRemoveProcedure is the fully-qualified name of the TodosService's Remove RPC.
+	TodosServiceRemoveProcedure = "/todos.v1.TodosService/Remove"
+	// TodosServiceUpdateProcedure is the fully-qualified name of the TodosService's Update RPC.
+	TodosServiceUpdateProcedure = "/todos.v1.TodosService/Update"
+	// TodosServiceSearchProcedure is the fully-qualified name of the TodosService's Search RPC.
+	TodosServiceSearchProcedure = "/todos.v1.TodosService/Search"
+	// TodosServiceGetProcedure is the fully-qualified name of the TodosService's Get RPC.
+	TodosServiceGetProcedure = "/todos.v1.TodosService/Get"
+	// TodosServiceSortProcedure is the fully-qualified name of the TodosService's Sort RPC.
+	TodosServiceSortProcedure = "/todos.v1.TodosService/Sort"
+	// TodosServiceExportProcedure is the fully-qualified name of the TodosService's Export RPC.
+	TodosServiceExportProcedure = "/todos.v1.TodosService/Export"
+	// TodosServiceImportProcedure is the fully-qualified name of the TodosService's Import RPC.
+	TodosServiceImportProcedure = "/todos.v1.TodosService/Import"
+)
+
+// TodosServiceClient is a client for the todos.v1.TodosService service.
+type TodosServiceClient interface {
+	// Add adds a todo to the list
+	Add(context.Context, *connect_go.Request[v1.AddRequest]) (*connect_go.Response[v1.AddResponse], error)
+	// Remove removes a todo from the list
+	Remove(context.Context, *connect_go.Request[v1.RemoveRequest]) (*connect_go.Response[v1.RemoveResponse], error)
+	// Update updates a todo in the list
+	Update(context.Context, *connect_go.Request[v1.UpdateRequest]) (*connect_go.Response[v1.UpdateResponse], error)
+	// Search returns a list of todos that match the search string
+	Search(context.Context, *connect_go.Request[v1.SearchRequest]) (*connect_go.Response[v1.SearchResponse], error)
+	// Get returns a todo by id
+	Get(context.Context, *connect_go.Request[v1.GetRequest]) (*connect_go.Response[v1.GetResponse], error)
+	// Sort sorts the todos by the given ids
+	Sort(context.Context, *connect_go.Request[v1.SortRequest]) (*connect_go.Response[v1.SortResponse], error)
+	// Export encodes the todos list in the given format
+	Export(context.Context, *connect_go.Request[v1.ExportRequest]) (*connect_go.Response[v1.ExportResponse], error)
+	// Import decodes todos in the given format and merges them into, or replaces, the list
+	Import(context.Context, *connect_go.Request[v1.ImportRequest]) (*connect_go.Response[v1.ImportResponse], error)
+}
+
+// NewTodosServiceClient constructs a client for the todos.v1.TodosService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTodosServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) TodosServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &todosServiceClient{
+		add: connect_go.NewClient[v1.AddRequest, v1.AddResponse](
+			httpClient,
+			baseURL+TodosServiceAddProcedure,
+			opts...,
+		),
+		remove: connect_go.NewClient[v1.RemoveRequest, v1.RemoveResponse](
+			httpClient,
+			baseURL+TodosServiceRemoveProcedure,
+			opts...,
+		),
+		update: connect_go.NewClient[v1.UpdateRequest, v1.UpdateResponse](
+			httpClient,
+			baseURL+TodosServiceUpdateProcedure,
+			opts...,
+		),
+		search: connect_go.NewClient[v1.SearchRequest, v1.SearchResponse](
+			httpClient,
+			baseURL+TodosServiceSearchProcedure,
+			opts...,
+		),
+		get: connect_go.NewClient[v1.GetRequest, v1.GetResponse](
+			httpClient,
+			baseURL+TodosServiceGetProcedure,
+			opts...,
+		),
+		sort: connect_go.NewClient[v1.SortRequest, v1.SortResponse](
+			httpClient,
+			baseURL+TodosServiceSortProcedure,
+			opts...,
+		),
+		export: connect_go.NewClient[v1.ExportRequest, v1.ExportResponse](
+			httpClient,
+			baseURL+TodosServiceExportProcedure,
+			opts...,
+		),
+		_import: connect_go.NewClient[v1.ImportRequest, v1.ImportResponse](
+			httpClient,
+			baseURL+TodosServiceImportProcedure,
+			opts...,
+		),
+	}
+}
+
+// todosServiceClient implements TodosServiceClient.
+type todosServiceClient struct {
+	add     *connect_go.Client[v1.AddRequest, v1.AddResponse]
+	remove  *connect_go.Client[v1.RemoveRequest, v1.RemoveResponse]
+	update  *connect_go.Client[v1.UpdateRequest, v1.UpdateResponse]
+	search  *connect_go.Client[v1.SearchRequest, v1.SearchResponse]
+	get     *connect_go.Client[v1.GetRequest, v1.GetResponse]
+	sort    *connect_go.Client[v1.SortRequest, v1.SortResponse]
+	export  *connect_go.Client[v1.ExportRequest, v1.ExportResponse]
+	_import *connect_go.Client[v1.ImportRequest, v1.ImportResponse]
+}
+
+// Add calls todos.v1.TodosService.Add.
+func (c *todosServiceClient) Add(ctx context.Context, req *connect_go.Request[v1.AddRequest]) (*connect_go.Response[v1.AddResponse], error) {
+	return c.add.CallUnary(ctx, req)
+}
+
+// Remove calls todos.v1.TodosService.Remove.
+func (c *todosServiceClient) Remove(ctx context.Context, req *connect_go.Request[v1.RemoveRequest]) (*connect_go.Response[v1.RemoveResponse], error) {
+	return c.remove.CallUnary(ctx, req)
+}
+
+// Update calls todos.v1.TodosService.Update.
+func (c *todosServiceClient) Update(ctx context.Context, req *connect_go.Request[v1.UpdateRequest]) (*connect_go.Response[v1.UpdateResponse], error) {
+	return c.update.CallUnary(ctx, req)
+}
+
+// Search calls todos.v1.TodosService.Search.
+func (c *todosServiceClient) Search(ctx context.Context, req *connect_go.Request[v1.SearchRequest]) (*connect_go.Response[v1.SearchResponse], error) {
+	return c.search.CallUnary(ctx, req)
+}
+
+// Get calls todos.v1.TodosService.Get.
+func (c *todosServiceClient) Get(ctx context.Context, req *connect_go.Request[v1.GetRequest]) (*connect_go.Response[v1.GetResponse], error) {
+	return c.get.CallUnary(ctx, req)
+}
+
+// Sort calls todos.v1.TodosService.Sort.
+func (c *todosServiceClient) Sort(ctx context.Context, req *connect_go.Request[v1.SortRequest]) (*connect_go.Response[v1.SortResponse], error) {
+	return c.sort.CallUnary(ctx, req)
+}
+
+// Export calls todos.v1.TodosService.Export.
+func (c *todosServiceClient) Export(ctx context.Context, req *connect_go.Request[v1.ExportRequest]) (*connect_go.Response[v1.ExportResponse], error) {
+	return c.export.CallUnary(ctx, req)
+}
+
+// Import calls todos.v1.TodosService.Import.
+func (c *todosServiceClient) Import(ctx context.Context, req *connect_go.Request[v1.ImportRequest]) (*connect_go.Response[v1.ImportResponse], error) {
+	return c._import.CallUnary(ctx, req)
+}
+
+// TodosServiceHandler is an implementation of the todos.v1.TodosService service.
+type TodosServiceHandler interface {
+	// Add adds a todo to the list
+	Add(context.Context, *connect_go.Request[v1.AddRequest]) (*connect_go.Response[v1.AddResponse], error)
+	// Remove removes a todo from the list
+	Remove(context.Context, *connect_go.Request[v1.RemoveRequest]) (*connect_go.Response[v1.RemoveResponse], error)
+	// Update updates a todo in the list
+	Update(context.Context, *connect_go.Request[v1.UpdateRequest]) (*connect_go.Response[v1.UpdateResponse], error)
+	// Search returns a list of todos that match the search string
+	Search(context.Context, *connect_go.Request[v1.SearchRequest]) (*connect_go.Response[v1.SearchResponse], error)
+	// Get returns a todo by id
+	Get(context.Context, *connect_go.Request[v1.GetRequest]) (*connect_go.Response[v1.GetResponse], error)
+	// Sort sorts the todos by the given ids
+	Sort(context.Context, *connect_go.Request[v1.SortRequest]) (*connect_go.Response[v1.SortResponse], error)
+	// Export encodes the todos list in the given format
+	Export(context.Context, *connect_go.Request[v1.ExportRequest]) (*connect_go.Response[v1.ExportResponse], error)
+	// Import decodes todos in the given format and merges them into, or replaces, the list
+	Import(context.Context, *connect_go.Request[v1.ImportRequest]) (*connect_go.Response[v1.ImportResponse], error)
+}
+
+// NewTodosServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTodosServiceHandler(svc TodosServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(TodosServiceAddProcedure, connect_go.NewUnaryHandler(
+		TodosServiceAddProcedure,
+		svc.Add,
+		opts...,
+	))
+	mux.Handle(TodosServiceRemoveProcedure, connect_go.NewUnaryHandler(
+		TodosServiceRemoveProcedure,
+		svc.Remove,
+		opts...,
+	))
+	mux.Handle(TodosServiceUpdateProcedure, connect_go.NewUnaryHandler(
+		TodosServiceUpdateProcedure,
+		svc.Update,
+		opts...,
+	))
+	mux.Handle(TodosServiceSearchProcedure, connect_go.NewUnaryHandler(
+		TodosServiceSearchProcedure,
+		svc.Search,
+		opts...,
+	))
+	mux.Handle(TodosServiceGetProcedure, connect_go.NewUnaryHandler(
+		TodosServiceGetProcedure,
+		svc.Get,
+		opts...,
+	))
+	mux.Handle(TodosServiceSortProcedure, connect_go.NewUnaryHandler(
+		TodosServiceSortProcedure,
+		svc.Sort,
+		opts...,
+	))
+	mux.Handle(TodosServiceExportProcedure, connect_go.NewUnaryHandler(
+		TodosServiceExportProcedure,
+		svc.Export,
+		opts...,
+	))
+	mux.Handle(TodosServiceImportProcedure, connect_go.NewUnaryHandler(
+		TodosServiceImportProcedure,
+		svc.Import,
+		opts...,
+	))
+	return "/todos.v1.TodosService/", mux
+}
+
+// UnimplementedTodosServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTodosServiceHandler struct{}
+
+func (UnimplementedTodosServiceHandler) Add(context.Context, *connect_go.Request[v1.AddRequest]) (*connect_go.Response[v1.AddResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Add is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Remove(context.Context, *connect_go.Request[v1.RemoveRequest]) (*connect_go.Response[v1.RemoveResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Remove is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Update(context.Context, *connect_go.Request[v1.UpdateRequest]) (*connect_go.Response[v1.UpdateResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Update is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Search(context.Context, *connect_go.Request[v1.SearchRequest]) (*connect_go.Response[v1.SearchResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Search is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Get(context.Context, *connect_go.Request[v1.GetRequest]) (*connect_go.Response[v1.GetResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Get is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Sort(context.Context, *connect_go.Request[v1.SortRequest]) (*connect_go.Response[v1.SortResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Sort is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Export(context.Context, *connect_go.Request[v1.ExportRequest]) (*connect_go.Response[v1.ExportResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Export is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Import(context.Context, *connect_go.Request[v1.ImportRequest]) (*connect_go.Response[v1.ImportResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("todos.v1.TodosService.Import is not implemented"))
+}
@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+	"github.com/stackus/todos-htmx-wasm/internal/trace"
+)
+
+// traceInterceptor runs every call in a client span and passes its traceparent, along
+// with the request ID, on to the server
+func traceInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
+			ctx, span := trace.Start(ctx, req.Spec().Procedure, trace.WithKind(trace.KindClient))
+			defer func() {
+				span.RecordError(err)
+				span.End()
+			}()
+			trace.Inject(ctx, req.Header())
+			if id := log.RequestIDFromContext(ctx); id != "" {
+				req.Header().Set(log.RequestIDHeader, id)
+			}
+			return next(ctx, req)
+		}
+	}
+}
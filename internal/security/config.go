@@ -0,0 +1,131 @@
+package security
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoncePlaceholder is replaced in the Content-Security-Policy with the nonce of each response
+const NoncePlaceholder = "{nonce}"
+
+// DefaultCSP allows the scripts of the site, and inline scripts that carry the nonce
+// of the response; the WebAssembly proxy needs 'wasm-unsafe-eval'
+const DefaultCSP = "default-src 'self'; " +
+	"script-src 'self' 'nonce-" + NoncePlaceholder + "' 'wasm-unsafe-eval'; " +
+	"style-src 'self'; img-src 'self' data:; connect-src 'self'; " +
+	"object-src 'none'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'"
+
+// DefaultReportURI is the path Mount serves the violation report collector on
+const DefaultReportURI = "/csp-report"
+
+// Config describes the security headers of every response
+type Config struct {
+	// CSP is the Content-Security-Policy, in which NoncePlaceholder is replaced with the
+	// nonce of each response; empty sends none
+	CSP string `yaml:"csp" toml:"csp"`
+	// ReportOnly sends the CSP, COOP and COEP as their -Report-Only headers, which
+	// report violations without blocking anything
+	ReportOnly bool `yaml:"report_only" toml:"report_only"`
+	// ReportURI receives the violation reports of the CSP; empty sends none
+	ReportURI string `yaml:"report_uri" toml:"report_uri"`
+	// HSTSMaxAge is the max-age of Strict-Transport-Security, which is only sent over
+	// HTTPS; 0 sends none
+	HSTSMaxAge time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age"`
+	// FrameOptions is X-Frame-Options: "DENY", "SAMEORIGIN" or empty
+	FrameOptions string `yaml:"frame_options" toml:"frame_options"`
+	// ReferrerPolicy is Referrer-Policy, e.g. "same-origin"
+	ReferrerPolicy string `yaml:"referrer_policy" toml:"referrer_policy"`
+	// OpenerPolicy is Cross-Origin-Opener-Policy, e.g. "same-origin"
+	OpenerPolicy string `yaml:"opener_policy" toml:"opener_policy"`
+	// EmbedderPolicy is Cross-Origin-Embedder-Policy, e.g. "require-corp"
+	EmbedderPolicy string `yaml:"embedder_policy" toml:"embedder_policy"`
+}
+
+// DefaultConfig returns the strictest configuration the pages work with
+func DefaultConfig() Config {
+	return Config{
+		CSP:            DefaultCSP,
+		ReportURI:      DefaultReportURI,
+		HSTSMaxAge:     365 * 24 * time.Hour,
+		FrameOptions:   "DENY",
+		ReferrerPolicy: "same-origin",
+		OpenerPolicy:   "same-origin",
+		EmbedderPolicy: "require-corp",
+	}
+}
+
+// LoadEnv reads SECURITY_* variables over the configuration using lookup, usually os.LookupEnv
+func (c *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	var err error
+	str := func(key string, v *string) {
+		if value, ok := lookup(key); ok {
+			*v = value
+		}
+	}
+	parse := func(key string, set func(string) error) {
+		if value, ok := lookup(key); ok && err == nil {
+			if parseErr := set(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		}
+	}
+
+	str("SECURITY_CSP", &c.CSP)
+	parse("SECURITY_REPORT_ONLY", func(s string) (err error) { c.ReportOnly, err = strconv.ParseBool(s); return })
+	str("SECURITY_REPORT_URI", &c.ReportURI)
+	parse("SECURITY_HSTS_MAX_AGE", func(s string) (err error) { c.HSTSMaxAge, err = time.ParseDuration(s); return })
+	str("SECURITY_FRAME_OPTIONS", &c.FrameOptions)
+	str("SECURITY_REFERRER_POLICY", &c.ReferrerPolicy)
+	str("SECURITY_OPENER_POLICY", &c.OpenerPolicy)
+	str("SECURITY_EMBEDDER_POLICY", &c.EmbedderPolicy)
+	return err
+}
+
+// RegisterFlags adds the security flags to fs whose defaults are the current
+// configuration, so flags override anything read with LoadEnv
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.CSP, "csp", c.CSP, "Content-Security-Policy, in which "+NoncePlaceholder+" is the nonce of the response; env SECURITY_CSP")
+	fs.BoolVar(&c.ReportOnly, "csp-report-only", c.ReportOnly, "only report violations of the CSP, COOP and COEP; env SECURITY_REPORT_ONLY")
+	fs.StringVar(&c.ReportURI, "csp-report-uri", c.ReportURI, "where browsers send CSP violation reports; env SECURITY_REPORT_URI")
+	fs.DurationVar(&c.HSTSMaxAge, "hsts-max-age", c.HSTSMaxAge, "max-age of Strict-Transport-Security, 0 for none; env SECURITY_HSTS_MAX_AGE")
+	fs.StringVar(&c.FrameOptions, "frame-options", c.FrameOptions, "X-Frame-Options: DENY or SAMEORIGIN; env SECURITY_FRAME_OPTIONS")
+	fs.StringVar(&c.ReferrerPolicy, "referrer-policy", c.ReferrerPolicy, "Referrer-Policy; env SECURITY_REFERRER_POLICY")
+	fs.StringVar(&c.OpenerPolicy, "opener-policy", c.OpenerPolicy, "Cross-Origin-Opener-Policy; env SECURITY_OPENER_POLICY")
+	fs.StringVar(&c.EmbedderPolicy, "embedder-policy", c.EmbedderPolicy, "Cross-Origin-Embedder-Policy; env SECURITY_EMBEDDER_POLICY")
+}
+
+// Validate reports the first invalid setting
+func (c Config) Validate() error {
+	if strings.ContainsAny(c.CSP, "\r\n") {
+		return fmt.Errorf("csp: must be a single line")
+	}
+	if c.ReportURI != "" && !strings.HasPrefix(c.ReportURI, "/") && !strings.HasPrefix(c.ReportURI, "https://") {
+		return fmt.Errorf("csp report uri %q: must be a path or an https URL", c.ReportURI)
+	}
+	if c.HSTSMaxAge < 0 {
+		return fmt.Errorf("hsts max age %s: must not be negative", c.HSTSMaxAge)
+	}
+	if err := oneOf("frame options", c.FrameOptions, "DENY", "SAMEORIGIN"); err != nil {
+		return err
+	}
+	if err := oneOf("opener policy", c.OpenerPolicy, "unsafe-none", "same-origin-allow-popups", "same-origin"); err != nil {
+		return err
+	}
+	return oneOf("embedder policy", c.EmbedderPolicy, "unsafe-none", "require-corp", "credentialless")
+}
+
+// oneOf reports value when it is neither empty nor one of values
+func oneOf(setting, value string, values ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range values {
+		if value == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q: must be one of %s", setting, value, strings.Join(values, ", "))
+}
@@ -0,0 +1,32 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		change  func(c *Config)
+		wantErr bool
+	}{
+		"Default":           {change: func(c *Config) {}},
+		"Empty":             {change: func(c *Config) { *c = Config{} }},
+		"ReportURL":         {change: func(c *Config) { c.ReportURI = "https://reports.test/csp" }},
+		"MultilineCSP":      {change: func(c *Config) { c.CSP = "default-src 'self';\nscript-src *" }, wantErr: true},
+		"PlainReportURL":    {change: func(c *Config) { c.ReportURI = "http://reports.test/csp" }, wantErr: true},
+		"NegativeHSTS":      {change: func(c *Config) { c.HSTSMaxAge = -time.Second }, wantErr: true},
+		"BadFrameOptions":   {change: func(c *Config) { c.FrameOptions = "ALLOW-FROM https://a.test" }, wantErr: true},
+		"BadOpenerPolicy":   {change: func(c *Config) { c.OpenerPolicy = "same-site" }, wantErr: true},
+		"BadEmbedderPolicy": {change: func(c *Config) { c.EmbedderPolicy = "require-cors" }, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.change(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
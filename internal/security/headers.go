@@ -0,0 +1,76 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type nonceKey struct{}
+
+// Headers sets the security headers of cfg on every response, and gives each request
+// a new nonce, which the CSP allows and templates read with Nonce
+func Headers(cfg Config) func(http.Handler) http.Handler {
+	suffix := ""
+	if cfg.ReportOnly {
+		suffix = "-Report-Only"
+	}
+	csp := cfg.CSP
+	if csp != "" && cfg.ReportURI != "" {
+		csp += "; report-uri " + cfg.ReportURI + "; report-to csp"
+	}
+	hsts := ""
+	if seconds := int64(cfg.HSTSMaxAge.Seconds()); seconds > 0 {
+		hsts = "max-age=" + strconv.FormatInt(seconds, 10) + "; includeSubDomains"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			set := func(name, value string) {
+				if value != "" {
+					header.Set(name, value)
+				}
+			}
+			set("X-Frame-Options", cfg.FrameOptions)
+			set("Referrer-Policy", cfg.ReferrerPolicy)
+			set("Cross-Origin-Opener-Policy"+suffix, cfg.OpenerPolicy)
+			set("Cross-Origin-Embedder-Policy"+suffix, cfg.EmbedderPolicy)
+			if r.TLS != nil {
+				set("Strict-Transport-Security", hsts)
+			}
+			if csp != "" {
+				nonce := newNonce()
+				set("Content-Security-Policy"+suffix, strings.ReplaceAll(csp, NoncePlaceholder, nonce))
+				if cfg.ReportURI != "" {
+					set("Reporting-Endpoints", `csp="`+cfg.ReportURI+`"`)
+				}
+				r = r.WithContext(WithNonce(r.Context(), nonce))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithNonce returns a copy of ctx that carries the CSP nonce of the response
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, nonceKey{}, nonce)
+}
+
+// Nonce returns the CSP nonce of the response for the nonce attribute of script tags,
+// or an empty string outside of Headers
+func Nonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(nonceKey{}).(string)
+	return nonce
+}
+
+// newNonce returns 128 random bits, as the CSP recommends
+func newNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
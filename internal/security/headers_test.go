@@ -0,0 +1,109 @@
+package security
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeaders(t *testing.T) {
+	tests := map[string]struct {
+		change     func(c *Config)
+		tls        bool
+		wantHeader map[string]string
+		wantNonce  bool
+	}{
+		"Default": {
+			change: func(c *Config) {},
+			wantHeader: map[string]string{
+				"Content-Security-Policy":      "script-src 'self' 'nonce-{nonce}'",
+				"Cross-Origin-Opener-Policy":   "same-origin",
+				"Cross-Origin-Embedder-Policy": "require-corp",
+				"X-Frame-Options":              "DENY",
+				"X-Content-Type-Options":       "nosniff",
+				"Referrer-Policy":              "same-origin",
+				"Reporting-Endpoints":          `csp="/csp-report"`,
+				"Strict-Transport-Security":    "",
+			},
+			wantNonce: true,
+		},
+		"HTTPS": {
+			change: func(c *Config) { c.HSTSMaxAge = time.Hour },
+			tls:    true,
+			wantHeader: map[string]string{
+				"Strict-Transport-Security": "max-age=3600; includeSubDomains",
+			},
+			wantNonce: true,
+		},
+		"ReportOnly": {
+			change: func(c *Config) { c.ReportOnly = true },
+			wantHeader: map[string]string{
+				"Content-Security-Policy":                  "",
+				"Content-Security-Policy-Report-Only":      "report-uri /csp-report; report-to csp",
+				"Cross-Origin-Opener-Policy":               "",
+				"Cross-Origin-Opener-Policy-Report-Only":   "same-origin",
+				"Cross-Origin-Embedder-Policy-Report-Only": "require-corp",
+			},
+			wantNonce: true,
+		},
+		"NoReports": {
+			change: func(c *Config) { c.ReportURI = "" },
+			wantHeader: map[string]string{
+				"Content-Security-Policy": "frame-ancestors 'none'",
+				"Reporting-Endpoints":     "",
+			},
+			wantNonce: true,
+		},
+		"NoCSP": {
+			change: func(c *Config) { c.CSP, c.FrameOptions = "", "" },
+			wantHeader: map[string]string{
+				"Content-Security-Policy": "",
+				"X-Frame-Options":         "",
+				"Reporting-Endpoints":     "",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.change(&cfg)
+			var nonce string
+			handler := Headers(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = Nonce(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if (nonce != "") != tt.wantNonce {
+				t.Fatalf("Nonce() = %q, want a nonce %v", nonce, tt.wantNonce)
+			}
+			for name, want := range tt.wantHeader {
+				got := rec.Header().Get(name)
+				want = strings.ReplaceAll(want, NoncePlaceholder, nonce)
+				if want == "" && got != "" || !strings.Contains(got, want) {
+					t.Errorf("%s = %q, want it to contain %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeaders_NewNonce(t *testing.T) {
+	var nonces []string
+	handler := Headers(DefaultConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, Nonce(r.Context()))
+	}))
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if len(nonces[0]) != 24 || nonces[0] == nonces[1] {
+		t.Errorf("nonces = %q, want a new 128-bit nonce for each response", nonces)
+	}
+}
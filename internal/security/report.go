@@ -0,0 +1,115 @@
+package security
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/log"
+)
+
+// maxReportSize limits the body of a violation report request
+const maxReportSize = 64 << 10
+
+type (
+	// Violation is a CSP violation, as reported by a browser
+	Violation struct {
+		DocumentURL        string `json:"documentURL"`
+		BlockedURL         string `json:"blockedURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"sourceFile"`
+		LineNumber         int    `json:"lineNumber"`
+		Sample             string `json:"sample"`
+	}
+
+	// legacyViolation is the body of a report-uri report, sent as application/csp-report
+	legacyViolation struct {
+		DocumentURI        string `json:"document-uri"`
+		BlockedURI         string `json:"blocked-uri"`
+		EffectiveDirective string `json:"effective-directive"`
+		ViolatedDirective  string `json:"violated-directive"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		ScriptSample       string `json:"script-sample"`
+	}
+)
+
+// Mount mounts the violation report collector at DefaultReportURI
+func Mount(r chi.Router) {
+	r.Post(DefaultReportURI, ReportHandler)
+}
+
+// ReportHandler logs the CSP violations of a report-uri or Reporting API request
+func ReportHandler(w http.ResponseWriter, r *http.Request) {
+	violations, err := decodeReport(r.Header.Get("Content-Type"), http.MaxBytesReader(w, r.Body, maxReportSize))
+	if err != nil {
+		log.Ctx(r.Context()).Debug().Err(err).Msg("rejected violation report")
+		http.Error(w, "invalid violation report", http.StatusBadRequest)
+		return
+	}
+	for _, v := range violations {
+		log.Ctx(r.Context()).Warn().
+			Str("Document", v.DocumentURL).
+			Str("Blocked", v.BlockedURL).
+			Str("Directive", v.EffectiveDirective).
+			Str("Disposition", v.Disposition).
+			Str("Source", v.SourceFile).
+			Int("Line", v.LineNumber).
+			Str("Sample", v.Sample).
+			Msg("content security policy violation")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeReport decodes the violations of an application/csp-report body, or the
+// csp-violation reports of an application/reports+json body
+func decodeReport(contentType string, body io.Reader) ([]Violation, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	decoder := json.NewDecoder(body)
+	switch mediaType {
+	case "application/csp-report":
+		var report struct {
+			Body legacyViolation `json:"csp-report"`
+		}
+		if err := decoder.Decode(&report); err != nil {
+			return nil, err
+		}
+		v := report.Body
+		directive := v.EffectiveDirective
+		if directive == "" {
+			directive = v.ViolatedDirective
+		}
+		return []Violation{{
+			DocumentURL:        v.DocumentURI,
+			BlockedURL:         v.BlockedURI,
+			EffectiveDirective: directive,
+			Disposition:        v.Disposition,
+			SourceFile:         v.SourceFile,
+			LineNumber:         v.LineNumber,
+			Sample:             v.ScriptSample,
+		}}, nil
+	case "application/reports+json":
+		var reports []struct {
+			Type string    `json:"type"`
+			Body Violation `json:"body"`
+		}
+		if err := decoder.Decode(&reports); err != nil {
+			return nil, err
+		}
+		var violations []Violation
+		for _, report := range reports {
+			if report.Type == "csp-violation" {
+				violations = append(violations, report.Body)
+			}
+		}
+		return violations, nil
+	default:
+		return nil, fmt.Errorf("unsupported report media type %q", mediaType)
+	}
+}
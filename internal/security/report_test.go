@@ -0,0 +1,72 @@
+package security
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestReportHandler(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		wantStatus  int
+		wantLogs    []string
+	}{
+		"ReportURI": {
+			contentType: "application/csp-report",
+			body:        `{"csp-report":{"document-uri":"https://todos.test/","blocked-uri":"inline","violated-directive":"script-src-elem","disposition":"enforce","line-number":12}}`,
+			wantStatus:  http.StatusNoContent,
+			wantLogs:    []string{`"Document":"https://todos.test/"`, `"Blocked":"inline"`, `"Directive":"script-src-elem"`, `"Line":12`},
+		},
+		"ReportingAPI": {
+			contentType: "application/reports+json",
+			body: `[{"type":"csp-violation","body":{"documentURL":"https://todos.test/","blockedURL":"https://evil.test/x.js","effectiveDirective":"script-src-elem","disposition":"report"}},` +
+				`{"type":"deprecation","body":{"id":"x"}}]`,
+			wantStatus: http.StatusNoContent,
+			wantLogs:   []string{`"Blocked":"https://evil.test/x.js"`, `"Disposition":"report"`},
+		},
+		"Malformed": {
+			contentType: "application/csp-report",
+			body:        `{"csp-report":`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"UnsupportedType": {
+			contentType: "text/plain",
+			body:        `hello`,
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := zerolog.New(&logs).Level(zerolog.WarnLevel)
+			req := httptest.NewRequest(http.MethodPost, DefaultReportURI, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = req.WithContext(logger.WithContext(req.Context()))
+			rec := httptest.NewRecorder()
+
+			ReportHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("StatusCode = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			wantLines := 0
+			if tt.wantLogs != nil {
+				wantLines = 1
+			}
+			if lines := strings.Count(logs.String(), "\n"); lines != wantLines {
+				t.Errorf("logged %d violations, want %d: %s", lines, wantLines, logs.String())
+			}
+			for _, want := range tt.wantLogs {
+				if !strings.Contains(logs.String(), want) {
+					t.Errorf("logs = %s, want them to contain %s", logs.String(), want)
+				}
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
-func HomePage(todos []*domain.Todo) templ.Component {
+func HomePage(todos []*domain.Todo, lists []*domain.Membership) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -37,6 +37,16 @@ func HomePage(todos []*domain.Todo) templ.Component {
 				defer templ.ReleaseBuffer(templBuffer)
 			}
 			// TemplElement
+			err = partials.Lists(lists).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
 			err = partials.Search("").Render(ctx, templBuffer)
 			if err != nil {
 				return err
@@ -56,8 +66,31 @@ func HomePage(todos []*domain.Todo) templ.Component {
 			if err != nil {
 				return err
 			}
+			// If
+			if !shared.ReadOnly(ctx) {
+				// TemplElement
+				err = partials.AddTodoForm("", "").Render(ctx, templBuffer)
+				if err != nil {
+					return err
+				}
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.ImportExport().Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
 			// TemplElement
-			err = partials.AddTodoForm().Render(ctx, templBuffer)
+			err = partials.Logout().Render(ctx, templBuffer)
 			if err != nil {
 				return err
 			}
@@ -9,6 +9,13 @@ import "context"
 import "io"
 import "bytes"
 
+// GoExpression
+import (
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+	"github.com/stackus/todos-htmx-wasm/internal/security"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
+)
+
 func LoadingPage() templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
@@ -80,7 +87,19 @@ func LoadingPage() templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" href=\"/dist/favicon.svg\"")
+		_, err = templBuffer.WriteString(" href=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.Asset("favicon.svg")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -161,28 +180,67 @@ func LoadingPage() templ.Component {
 			return err
 		}
 		// RawElement
-		_, err = templBuffer.WriteString("<script>")
+		_, err = templBuffer.WriteString("<script")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" nonce=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(security.Nonce(ctx)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" data-worker=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.ServiceWorker(manifest.Hash("client.wasm"))))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
 		if err != nil {
 			return err
 		}
 		// Text
 		var_3 := `
-    navigator.serviceWorker.register('/dist/sw.js', { scope: '/' })
+    navigator.serviceWorker.register(document.currentScript.dataset.worker, { scope: '/' })
       .then(registration => {
-        const serviceWorker = registration.installing ?? registration.waiting ?? registration.active
-	      if (serviceWorker.state === 'activated') {
+        // a worker that already serves other pages keeps serving them; pages offer to
+        // reload into the new one
+        if (registration.active) {
           document.location.reload()
-        } else {
-          serviceWorker.addEventListener('statechange', e => {
-            if (e.target.state === 'activated') {
-              document.location.reload()
-            }
-          })
+          return
         }
+        const serviceWorker = registration.installing ?? registration.waiting
+        serviceWorker.addEventListener('statechange', e => {
+          if (e.target.state === 'activated') {
+            document.location.reload()
+          }
+        })
+      })
+      .catch(error => {
+        console.error("Service Worker error: ", error)
       })
-    	.catch(error => {
-				console.error("Service Worker error: ", error)
-			})
 	`
 		_, err = templBuffer.WriteString(var_3)
 		if err != nil {
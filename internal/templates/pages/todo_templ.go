@@ -16,7 +16,7 @@ import (
 	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
-func TodoPage(todo *domain.Todo) templ.Component {
+func TodoPage(todo *domain.Todo, message string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -37,7 +37,7 @@ func TodoPage(todo *domain.Todo) templ.Component {
 				defer templ.ReleaseBuffer(templBuffer)
 			}
 			// TemplElement
-			err = partials.EditTodoForm(todo).Render(ctx, templBuffer)
+			err = partials.EditTodoForm(todo, message).Render(ctx, templBuffer)
 			if err != nil {
 				return err
 			}
@@ -56,8 +56,21 @@ func TodosPage(todos []*domain.Todo, term string) templ.Component {
 			if err != nil {
 				return err
 			}
+			// If
+			if !shared.ReadOnly(ctx) {
+				// TemplElement
+				err = partials.AddTodoForm("", "").Render(ctx, templBuffer)
+				if err != nil {
+					return err
+				}
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
 			// TemplElement
-			err = partials.AddTodoForm().Render(ctx, templBuffer)
+			err = partials.ImportExport().Render(ctx, templBuffer)
 			if err != nil {
 				return err
 			}
@@ -9,7 +9,12 @@ import "context"
 import "io"
 import "bytes"
 
-func AddTodoForm() templ.Component {
+// GoExpression
+import (
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
+)
+
+func AddTodoForm(description string, message string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -32,7 +37,19 @@ func AddTodoForm() templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" action=\"/todos\"")
+		_, err = templBuffer.WriteString(" action=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.InList(ctx, "/todos")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -56,6 +73,11 @@ func AddTodoForm() templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.CSRFInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<label")
 		if err != nil {
@@ -108,11 +130,27 @@ func AddTodoForm() templ.Component {
 		if err != nil {
 			return err
 		}
+		_, err = templBuffer.WriteString(" value=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(description))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString(" class=\"ml-2 grow\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" data-script=\"on keyup if the event&#39;s key is &#39;Enter&#39; set my value to &#39;&#39; trigger keyup\"")
+		_, err = templBuffer.WriteString(" data-script=\"on htmx:afterRequest from closest &lt;form/&gt; if event.detail.successful set my value to &#39;&#39; then put &#39;&#39; into #add-todo-error then add .hidden to #add-todo-error end\"")
 		if err != nil {
 			return err
 		}
@@ -124,6 +162,11 @@ func AddTodoForm() templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = FieldError("add-todo-error", message).Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString("</form>")
 		if err != nil {
 			return err
@@ -12,9 +12,10 @@ import "bytes"
 // GoExpression
 import (
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
-func EditTodoForm(todo *domain.Todo) templ.Component {
+func EditTodoForm(todo *domain.Todo, message string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -149,7 +150,7 @@ func EditTodoForm(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(templ.EscapeString("/todos/" + todo.ID.String() + "/edit"))
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.InList(ctx, "/todos/"+todo.ID.String()+"/edit")))
 		if err != nil {
 			return err
 		}
@@ -189,6 +190,11 @@ func EditTodoForm(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.CSRFInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (void)
 		_, err = templBuffer.WriteString("<input")
 		if err != nil {
@@ -272,6 +278,11 @@ func EditTodoForm(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = FieldError("description-error-"+todo.ID.String(), message).Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString("</form>")
 		if err != nil {
 			return err
@@ -12,6 +12,7 @@ import "bytes"
 // GoExpression
 import (
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
 func RenderTodo(todo *domain.Todo) templ.Component {
@@ -28,12 +29,115 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		}
 		ctx = templ.ClearChildren(ctx)
 		// Element (standard)
+		// Element CSS
+		var var_2 = []any{"block py-2 border-b-4 border-dotted border-red-900", templ.KV("draggable", !shared.ReadOnly(ctx))}
+		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString("<div")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" class=\"block py-2 border-b-4 border-dotted border-red-900 draggable\"")
+		_, err = templBuffer.WriteString(" class=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// If
+		if shared.ReadOnly(ctx) {
+			// Element (standard)
+			// Element CSS
+			var var_3 = []any{templ.KV("line-through", todo.Completed)}
+			err = templ.RenderCSSItems(ctx, templBuffer, var_3...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<span")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" class=")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_3).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// StringExpression
+			var var_4 string = todo.Description
+			_, err = templBuffer.WriteString(templ.EscapeString(var_4))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span>")
+			if err != nil {
+				return err
+			}
+		} else {
+			// TemplElement
+			err = editableTodo(todo).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<input")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" type=\"hidden\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" name=\"id\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" value=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(todo.ID.String()))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -41,6 +145,30 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		_, err = templBuffer.WriteString("</div>")
+		if err != nil {
+			return err
+		}
+		if !templIsBuffer {
+			_, err = io.Copy(w, templBuffer)
+		}
+		return err
+	})
+}
+
+func editableTodo(todo *domain.Todo) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		var_5 := templ.GetChildren(ctx)
+		if var_5 == nil {
+			var_5 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
 		// Element (standard)
 		_, err = templBuffer.WriteString("<form")
 		if err != nil {
@@ -59,7 +187,7 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(templ.EscapeString("/todos/" + todo.ID.String() + "/delete"))
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.InList(ctx, "/todos/"+todo.ID.String()+"/delete")))
 		if err != nil {
 			return err
 		}
@@ -75,6 +203,11 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.CSRFInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<button")
 		if err != nil {
@@ -118,8 +251,8 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 			return err
 		}
 		// Text
-		var_2 := `❌`
-		_, err = templBuffer.WriteString(var_2)
+		var_6 := `❌`
+		_, err = templBuffer.WriteString(var_6)
 		if err != nil {
 			return err
 		}
@@ -165,6 +298,11 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.ListInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<button")
 		if err != nil {
@@ -208,8 +346,8 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 			return err
 		}
 		// Text
-		var_3 := `📝`
-		_, err = templBuffer.WriteString(var_3)
+		var_7 := `📝`
+		_, err = templBuffer.WriteString(var_7)
 		if err != nil {
 			return err
 		}
@@ -223,8 +361,8 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		}
 		// Element (standard)
 		// Element CSS
-		var var_4 = []any{"inline", templ.KV("line-through", todo.Completed)}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_4...)
+		var var_8 = []any{"inline", templ.KV("line-through", todo.Completed)}
+		err = templ.RenderCSSItems(ctx, templBuffer, var_8...)
 		if err != nil {
 			return err
 		}
@@ -245,7 +383,7 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(templ.EscapeString("/todos/" + todo.ID.String() + "/edit"))
+		_, err = templBuffer.WriteString(templ.EscapeString(shared.InList(ctx, "/todos/"+todo.ID.String()+"/edit")))
 		if err != nil {
 			return err
 		}
@@ -269,7 +407,7 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_4).String()))
+		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_8).String()))
 		if err != nil {
 			return err
 		}
@@ -281,6 +419,11 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.CSRFInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (void)
 		_, err = templBuffer.WriteString("<input")
 		if err != nil {
@@ -413,8 +556,8 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 			return err
 		}
 		// StringExpression
-		var var_5 string = todo.Description
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
+		var var_9 string = todo.Description
+		_, err = templBuffer.WriteString(templ.EscapeString(var_9))
 		if err != nil {
 			return err
 		}
@@ -426,44 +569,6 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		// Element (void)
-		_, err = templBuffer.WriteString("<input")
-		if err != nil {
-			return err
-		}
-		// Element Attributes
-		_, err = templBuffer.WriteString(" type=\"hidden\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(" name=\"id\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(" value=")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(todo.ID.String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(">")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
 		if !templIsBuffer {
 			_, err = io.Copy(w, templBuffer)
 		}
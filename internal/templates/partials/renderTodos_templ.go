@@ -12,6 +12,7 @@ import "bytes"
 // GoExpression
 import (
 	"github.com/stackus/todos-htmx-wasm/internal/domain"
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
 )
 
 func RenderTodos(todos []*domain.Todo) templ.Component {
@@ -33,13 +34,16 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" hx-post=\"/todos/sort\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(" hx-trigger=\"end\"")
-		if err != nil {
-			return err
+		if !shared.ReadOnly(ctx) {
+			// Element Attributes
+			_, err = templBuffer.WriteString(" hx-post=\"/todos/sort\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" hx-trigger=\"end\"")
+			if err != nil {
+				return err
+			}
 		}
 		_, err = templBuffer.WriteString(" class=\"block p-0 mb-2 text-lg\"")
 		if err != nil {
@@ -50,6 +54,12 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 			return err
 		}
 		// Element (standard)
+		// Element CSS
+		var var_2 = []any{templ.KV("sortable", !shared.ReadOnly(ctx))}
+		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString("<div")
 		if err != nil {
 			return err
@@ -59,7 +69,19 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" class=\" sortable\"")
+		_, err = templBuffer.WriteString(" class=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -75,6 +97,39 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 				return err
 			}
 		}
+		// TemplElement
+		err = noTodos().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</div>")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</form>")
+		if err != nil {
+			return err
+		}
+		if !templIsBuffer {
+			_, err = io.Copy(w, templBuffer)
+		}
+		return err
+	})
+}
+
+func noTodos() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		var_3 := templ.GetChildren(ctx)
+		if var_3 == nil {
+			var_3 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
 		// Element (standard)
 		_, err = templBuffer.WriteString("<div")
 		if err != nil {
@@ -99,8 +154,8 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 			return err
 		}
 		// Text
-		var_2 := `Congrats, you have no todos! Or... do you? 😰`
-		_, err = templBuffer.WriteString(var_2)
+		var_4 := `Congrats, you have no todos! Or... do you? 😰`
+		_, err = templBuffer.WriteString(var_4)
 		if err != nil {
 			return err
 		}
@@ -112,14 +167,6 @@ func RenderTodos(todos []*domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</form>")
-		if err != nil {
-			return err
-		}
 		if !templIsBuffer {
 			_, err = io.Copy(w, templBuffer)
 		}
@@ -9,6 +9,11 @@ import "context"
 import "io"
 import "bytes"
 
+// GoExpression
+import (
+	"github.com/stackus/todos-htmx-wasm/internal/templates/shared"
+)
+
 func Search(term string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
@@ -44,6 +49,11 @@ func Search(term string) templ.Component {
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = shared.ListInput().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<label")
 		if err != nil {
@@ -0,0 +1,11 @@
+package shared
+
+import (
+	"github.com/stackus/todos-htmx-wasm/internal/assets/manifest"
+)
+
+// Asset returns the content-hashed URL of the named asset, e.g. "app.js", which
+// browsers cache until a build changes it
+func Asset(name string) string {
+	return manifest.URL(name)
+}
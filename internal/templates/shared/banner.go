@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"context"
+)
+
+type (
+	// Banner is shown at the top of every page when the proxy and the server disagree
+	Banner struct {
+		// Message tells the user what is wrong, e.g. that the APIs are incompatible
+		Message string
+		// Build is the content hash of the proxy the server offers; reloading installs it
+		Build string
+		// Incompatible is set when this proxy can no longer talk to the server
+		Incompatible bool
+	}
+
+	bannerKey struct{}
+)
+
+// WithBanner returns a context in which Page shows the banner
+func WithBanner(ctx context.Context, banner Banner) context.Context {
+	return context.WithValue(ctx, bannerKey{}, banner)
+}
+
+func banner(ctx context.Context) Banner {
+	b, _ := ctx.Value(bannerKey{}).(Banner)
+	return b
+}
+
+// ServiceWorker returns the URL the worker that serves the proxy of build is registered
+// with; a new URL makes the browser install a new worker alongside the one in use
+func ServiceWorker(build string) string {
+	if build == "" {
+		return "/dist/sw.js"
+	}
+	return "/dist/sw.js?build=" + build
+}
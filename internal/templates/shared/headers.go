@@ -0,0 +1,29 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+// htmxConfig keeps htmx from adding its indicator styles in a style tag, which the
+// Content-Security-Policy does not allow
+const htmxConfig = `{"includeIndicatorStyles":false}`
+
+// hxHeaders returns the hx-headers that send the CSRF token of the session, and the
+// list of the page, with every htmx request
+func hxHeaders(ctx context.Context) string {
+	headers := map[string]string{}
+	if token := domain.CSRFTokenFromContext(ctx); token != "" {
+		headers[domain.CSRFHeader] = token
+	}
+	if membership := List(ctx); membership != nil {
+		headers[domain.ListHeader] = membership.ListID.String()
+	}
+	if len(headers) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(headers)
+	return string(data)
+}
@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/stackus/todos-htmx-wasm/internal/domain"
+)
+
+type listKey struct{}
+
+// WithList returns a context in which pages act on the list of the membership, and
+// only offer what its role allows
+func WithList(ctx context.Context, membership *domain.Membership) context.Context {
+	return context.WithValue(ctx, listKey{}, membership)
+}
+
+// List returns the membership of the list selected for the page, or nil when the page
+// shows the user's own list
+func List(ctx context.Context) *domain.Membership {
+	membership, _ := ctx.Value(listKey{}).(*domain.Membership)
+	return membership
+}
+
+// ReadOnly reports whether the user may only view the list of the page
+func ReadOnly(ctx context.Context) bool {
+	membership := List(ctx)
+	return membership != nil && !membership.Role.Allows(domain.PermissionEdit)
+}
+
+// CanShare reports whether the user may share the list of the page
+func CanShare(ctx context.Context) bool {
+	membership := List(ctx)
+	return membership == nil || membership.Role.Allows(domain.PermissionShare)
+}
+
+// InList adds the list of the page to the query of the path, so links and forms that
+// are not sent by htmx stay on it
+func InList(ctx context.Context, path string) string {
+	membership := List(ctx)
+	if membership == nil {
+		return path
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "list=" + url.QueryEscape(membership.ListID.String())
+}
@@ -9,6 +9,12 @@ import "context"
 import "io"
 import "bytes"
 
+// GoExpression
+import (
+	"github.com/stackus/todos-htmx-wasm/internal/assets/thirdparty"
+	"github.com/stackus/todos-htmx-wasm/internal/security"
+)
+
 func Page(title string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
@@ -93,7 +99,19 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" href=\"/dist/favicon.svg\"")
+		_, err = templBuffer.WriteString(" href=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(Asset("favicon.svg")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -173,93 +191,86 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// Element (void)
+		_, err = templBuffer.WriteString("<meta")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/htmx.org@1.9.2\"")
+		_, err = templBuffer.WriteString(" name=\"htmx-config\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" integrity=\"sha384-L6OqL9pRWyyFU3+/bjdSri+iIphTN/bvYyM37tICVyOJkWZLpP2vGn6VUEXgzg6h\"")
+		_, err = templBuffer.WriteString(" content=")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" crossorigin=\"anonymous\"")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(">")
+		_, err = templBuffer.WriteString(templ.EscapeString(htmxConfig))
 		if err != nil {
 			return err
 		}
-		// Text
-		var_3 := ``
-		_, err = templBuffer.WriteString(var_3)
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString(">")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// TemplElement
+		err = vendorScript("htmx").Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/hyperscript.org@0.9.8\"")
+		// TemplElement
+		err = vendorScript("hyperscript").Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(">")
+		// TemplElement
+		err = vendorScript("sortablejs").Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		// Text
-		var_4 := ``
-		_, err = templBuffer.WriteString(var_4)
+		// RawElement
+		_, err = templBuffer.WriteString("<script")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		// Element Attributes
+		_, err = templBuffer.WriteString(" src=")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/sortablejs@1.15.0\"")
+		_, err = templBuffer.WriteString(templ.EscapeString(Asset("app.js")))
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(">")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		// Text
-		var_5 := ``
-		_, err = templBuffer.WriteString(var_5)
+		_, err = templBuffer.WriteString(" nonce=")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		_, err = templBuffer.WriteString(templ.EscapeString(security.Nonce(ctx)))
 		if err != nil {
 			return err
 		}
-		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"/dist/app.js\"")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -268,8 +279,8 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_6 := ``
-		_, err = templBuffer.WriteString(var_6)
+		var_3 := ``
+		_, err = templBuffer.WriteString(var_3)
 		if err != nil {
 			return err
 		}
@@ -287,7 +298,19 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" href=\"/dist/styles.css\"")
+		_, err = templBuffer.WriteString(" href=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(Asset("styles.css")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -309,10 +332,34 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
+		if hxHeaders(ctx) != "" {
+			// Element Attributes
+			_, err = templBuffer.WriteString(" hx-headers=")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(hxHeaders(ctx)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+		}
 		_, err = templBuffer.WriteString(">")
 		if err != nil {
 			return err
 		}
+		// TemplElement
+		err = VersionBanner().Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<section")
 		if err != nil {
@@ -342,8 +389,8 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_7 := `Todos`
-		_, err = templBuffer.WriteString(var_7)
+		var_4 := `Todos`
+		_, err = templBuffer.WriteString(var_4)
 		if err != nil {
 			return err
 		}
@@ -374,3 +421,91 @@ func Page(title string) templ.Component {
 		return err
 	})
 }
+
+func vendorScript(name string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		var_5 := templ.GetChildren(ctx)
+		if var_5 == nil {
+			var_5 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		// RawElement
+		_, err = templBuffer.WriteString("<script")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" src=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(Asset(thirdparty.Asset(name))))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" integrity=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(thirdparty.Integrity(name)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" nonce=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(security.Nonce(ctx)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Text
+		var_6 := ``
+		_, err = templBuffer.WriteString(var_6)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</script>")
+		if err != nil {
+			return err
+		}
+		if !templIsBuffer {
+			_, err = io.Copy(w, templBuffer)
+		}
+		return err
+	})
+}
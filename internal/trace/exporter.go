@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"io"
+	"sync"
+
+	"github.com/segmentio/encoding/json"
+)
+
+type (
+	// StdoutExporter writes every span as a line of JSON
+	StdoutExporter struct {
+		mu sync.Mutex
+		w  io.Writer
+	}
+
+	// MemoryExporter keeps spans in memory; use it in tests
+	MemoryExporter struct {
+		mu    sync.Mutex
+		spans []SpanData
+	}
+)
+
+var (
+	_ Exporter = (*StdoutExporter)(nil)
+	_ Exporter = (*MemoryExporter)(nil)
+)
+
+// NewStdoutExporter creates an exporter that writes to w, usually os.Stdout
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+func (e *StdoutExporter) ExportSpan(span SpanData) {
+	data, err := json.Marshal(span)
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, _ = e.w.Write(append(data, '\n'))
+}
+
+// NewMemoryExporter creates an empty in-memory exporter
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+func (e *MemoryExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the exported spans in the order they ended
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset forgets every exported span
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
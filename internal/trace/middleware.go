@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware is a http.Handler middleware that starts a server span for every request,
+// continuing the trace of an incoming traceparent header; the span is named after the
+// chi route pattern once the request has been served
+func Middleware(tracer *Tracer) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := Extract(request.Context(), request.Header)
+			ctx, span := tracer.Start(ctx, request.Method, WithKind(KindServer), WithAttributes(
+				"http.method", request.Method,
+				"http.target", request.URL.Path,
+			))
+			ww := middleware.NewWrapResponseWriter(writer, request.ProtoMajor)
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				span.SetName(request.Method + " " + routePattern(request))
+				span.SetAttributes("http.status_code", strconv.Itoa(status))
+				if status >= http.StatusInternalServerError {
+					span.RecordError(errStatus(status))
+				}
+				span.End()
+			}()
+
+			next.ServeHTTP(ww, request.WithContext(ctx))
+		})
+	}
+}
+
+type errStatus int
+
+func (e errStatus) Error() string {
+	return http.StatusText(int(e))
+}
+
+// routePattern falls back to the path for handlers that are not routed by chi
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
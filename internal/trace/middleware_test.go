@@ -0,0 +1,79 @@
+package trace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMiddleware(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := map[string]struct {
+		target      string
+		traceparent string
+		wantName    string
+		wantStatus  string
+		wantErr     bool
+		wantParent  string
+	}{
+		"NewTrace": {
+			target:     "/todos/123",
+			wantName:   "GET /todos/{todoId}",
+			wantStatus: "200",
+		},
+		"ContinuesTrace": {
+			target:      "/todos/123",
+			traceparent: traceparent,
+			wantName:    "GET /todos/{todoId}",
+			wantStatus:  "200",
+			wantParent:  "00f067aa0ba902b7",
+		},
+		"ServerError": {
+			target:     "/fail",
+			wantName:   "GET /fail",
+			wantStatus: "500",
+			wantErr:    true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			exporter := NewMemoryExporter()
+			router := chi.NewRouter()
+			router.Use(Middleware(NewTracer(exporter)))
+			router.Get("/todos/{todoId}", func(w http.ResponseWriter, r *http.Request) {
+				if SpanFromContext(r.Context()) == nil {
+					t.Errorf("handler context has no span")
+				}
+			})
+			router.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.traceparent != "" {
+				req.Header.Set(TraceparentHeader, tt.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := exporter.Spans()
+			if len(spans) != 1 {
+				t.Fatalf("exported %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != tt.wantName || span.Kind != KindServer {
+				t.Errorf("span = %s (%s), want %s (%s)", span.Name, span.Kind, tt.wantName, KindServer)
+			}
+			if got := span.Attributes["http.status_code"]; got != tt.wantStatus {
+				t.Errorf("http.status_code = %q, want %q", got, tt.wantStatus)
+			}
+			if (span.Error != "") != tt.wantErr {
+				t.Errorf("span error = %q, wantErr %v", span.Error, tt.wantErr)
+			}
+			if tt.wantParent != "" && span.ParentID.String() != tt.wantParent {
+				t.Errorf("span parent = %v, want %s", span.ParentID, tt.wantParent)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader names the W3C Trace Context header
+const TraceparentHeader = "traceparent"
+
+const (
+	traceparentVersion = "00"
+	flagSampled        = 0x01
+)
+
+// String formats the span context as a traceparent header value
+func (sc SpanContext) String() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return traceparentVersion + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C traceparent header value
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("traceparent %q: expected 4 fields", value)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" {
+		return sc, fmt.Errorf("traceparent %q: invalid version", value)
+	}
+	// future versions may append fields, but version 00 has exactly four
+	if version == traceparentVersion && len(parts) != 4 {
+		return sc, fmt.Errorf("traceparent %q: expected 4 fields", value)
+	}
+	if len(traceID) != 32 || !isLowerHex(traceID) {
+		return sc, fmt.Errorf("traceparent %q: invalid trace id", value)
+	}
+	if len(spanID) != 16 || !isLowerHex(spanID) {
+		return sc, fmt.Errorf("traceparent %q: invalid parent id", value)
+	}
+	if len(flags) != 2 || !isLowerHex(flags) {
+		return sc, fmt.Errorf("traceparent %q: invalid flags", value)
+	}
+
+	_, _ = hex.Decode(sc.TraceID[:], []byte(traceID))
+	_, _ = hex.Decode(sc.SpanID[:], []byte(spanID))
+	if !sc.TraceID.IsValid() || !sc.SpanID.IsValid() {
+		return SpanContext{}, fmt.Errorf("traceparent %q: ids must not be zero", value)
+	}
+	var flagBits [1]byte
+	_, _ = hex.Decode(flagBits[:], []byte(flags))
+	sc.Sampled = flagBits[0]&flagSampled != 0
+	return sc, nil
+}
+
+// Inject writes the span context in ctx to the headers of an outgoing request
+func Inject(ctx context.Context, header http.Header) {
+	if sc, ok := SpanContextFromContext(ctx); ok {
+		header.Set(TraceparentHeader, sc.String())
+	}
+}
+
+// Extract returns a context that continues the trace of an incoming request; invalid
+// headers are ignored and a new trace is started instead
+func Extract(ctx context.Context, header http.Header) context.Context {
+	value := header.Get(TraceparentHeader)
+	if value == "" {
+		return ctx
+	}
+	sc, err := ParseTraceparent(value)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := map[string]struct {
+		value       string
+		wantSampled bool
+		wantErr     bool
+	}{
+		"Sampled":          {value: "00-" + traceID + "-" + spanID + "-01", wantSampled: true},
+		"NotSampled":       {value: "00-" + traceID + "-" + spanID + "-00"},
+		"OtherFlags":       {value: "00-" + traceID + "-" + spanID + "-09", wantSampled: true},
+		"FutureVersion":    {value: "01-" + traceID + "-" + spanID + "-01-extra", wantSampled: true},
+		"ExtraFieldsInV00": {value: "00-" + traceID + "-" + spanID + "-01-extra", wantErr: true},
+		"InvalidVersion":   {value: "ff-" + traceID + "-" + spanID + "-01", wantErr: true},
+		"UpperCase":        {value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", wantErr: true},
+		"ShortTraceID":     {value: "00-4bf92f35-" + spanID + "-01", wantErr: true},
+		"ZeroTraceID":      {value: "00-00000000000000000000000000000000-" + spanID + "-01", wantErr: true},
+		"ZeroSpanID":       {value: "00-" + traceID + "-0000000000000000-01", wantErr: true},
+		"MissingFields":    {value: "00-" + traceID, wantErr: true},
+		"Empty":            {value: "", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseTraceparent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.TraceID.String() != traceID || got.SpanID.String() != spanID {
+				t.Errorf("ParseTraceparent() = %v, want ids %s and %s", got, traceID, spanID)
+			}
+			if got.Sampled != tt.wantSampled {
+				t.Errorf("ParseTraceparent() Sampled = %v, want %v", got.Sampled, tt.wantSampled)
+			}
+		})
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	ctx, span := NewTracer(nil).Start(context.Background(), "client")
+	header := http.Header{}
+	Inject(ctx, header)
+
+	if got, want := header.Get(TraceparentHeader), span.SpanContext().String(); got != want {
+		t.Fatalf("Inject() traceparent = %q, want %q", got, want)
+	}
+
+	got, ok := SpanContextFromContext(Extract(context.Background(), header))
+	if !ok || got != span.SpanContext() {
+		t.Errorf("Extract() = %v, %v; want %v", got, ok, span.SpanContext())
+	}
+}
+
+func TestExtract_Invalid(t *testing.T) {
+	header := http.Header{}
+	header.Set(TraceparentHeader, "not a traceparent")
+
+	if _, ok := SpanContextFromContext(Extract(context.Background(), header)); ok {
+		t.Errorf("Extract() accepted an invalid traceparent")
+	}
+}
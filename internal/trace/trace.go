@@ -0,0 +1,263 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// Span kinds
+const (
+	KindInternal = "internal"
+	KindServer   = "server"
+	KindClient   = "client"
+)
+
+type (
+	// TraceID identifies a trace across every process it passes through
+	TraceID [16]byte
+	// SpanID identifies a single span within a trace
+	SpanID [8]byte
+
+	// SpanContext is the part of a span that is propagated to other processes
+	SpanContext struct {
+		TraceID TraceID
+		SpanID  SpanID
+		Sampled bool
+	}
+
+	// SpanData is a finished span as it is handed to an Exporter
+	SpanData struct {
+		Name       string            `json:"name"`
+		Kind       string            `json:"kind"`
+		TraceID    TraceID           `json:"traceId"`
+		SpanID     SpanID            `json:"spanId"`
+		ParentID   SpanID            `json:"parentId"`
+		Start      time.Time         `json:"start"`
+		End        time.Time         `json:"end"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+		Error      string            `json:"error,omitempty"`
+	}
+
+	// Exporter receives spans as they end
+	Exporter interface {
+		ExportSpan(span SpanData)
+	}
+
+	// Tracer starts spans and hands them to its exporter once they end
+	Tracer struct {
+		exporter Exporter
+	}
+
+	// Span is an operation being traced; it is safe for concurrent use
+	Span struct {
+		tracer  *Tracer
+		sampled bool
+
+		mu    sync.Mutex
+		data  SpanData
+		ended bool
+	}
+
+	// SpanOption configures a span as it is started
+	SpanOption func(*SpanData)
+
+	spanKey   struct{}
+	remoteKey struct{}
+)
+
+// DefaultTracer is used by the package-level Start; spans are discarded until it is
+// replaced with a tracer that has an exporter
+var DefaultTracer = NewTracer(nil)
+
+// NewTracer creates a tracer; a nil exporter discards spans while still creating and
+// propagating ids so that logs can be correlated
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// WithKind sets the kind of the span
+func WithKind(kind string) SpanOption {
+	return func(data *SpanData) {
+		data.Kind = kind
+	}
+}
+
+// WithAttributes adds attributes, given as key and value pairs, to the span
+func WithAttributes(keyValues ...string) SpanOption {
+	return func(data *SpanData) {
+		setAttributes(data, keyValues)
+	}
+}
+
+// Start starts a span using the tracer of the span in ctx, or the DefaultTracer
+func Start(ctx context.Context, name string, options ...SpanOption) (context.Context, *Span) {
+	tracer := DefaultTracer
+	if parent := SpanFromContext(ctx); parent != nil {
+		tracer = parent.tracer
+	}
+	return tracer.Start(ctx, name, options...)
+}
+
+// Start starts a span that is a child of the span, or the remote span context, in ctx
+func (t *Tracer) Start(ctx context.Context, name string, options ...SpanOption) (context.Context, *Span) {
+	span := &Span{
+		tracer:  t,
+		sampled: true,
+		data: SpanData{
+			Name:   name,
+			Kind:   KindInternal,
+			SpanID: newSpanID(),
+			Start:  time.Now(),
+		},
+	}
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		span.data.TraceID = parent.TraceID
+		span.data.ParentID = parent.SpanID
+		span.sampled = parent.Sampled
+	} else {
+		span.data.TraceID = newTraceID()
+	}
+	for _, option := range options {
+		option(&span.data)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the span started in ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the span context of the span in ctx, or the remote
+// span context extracted from an incoming request
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext(), true
+	}
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, ok
+}
+
+// ContextWithRemoteSpanContext returns a context whose next span continues a trace
+// started in another process
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanContext returns the identity of the span
+func (s *Span) SpanContext() SpanContext {
+	return SpanContext{
+		TraceID: s.data.TraceID,
+		SpanID:  s.data.SpanID,
+		Sampled: s.sampled,
+	}
+}
+
+// SetName replaces the name of the span, for names that are only known once the work is done
+func (s *Span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Name = name
+}
+
+// SetAttributes adds attributes, given as key and value pairs, to the span
+func (s *Span) SetAttributes(keyValues ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setAttributes(&s.data, keyValues)
+}
+
+// RecordError marks the span as failed; nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End finishes the span and exports it; only the first call has any effect
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(data)
+	}
+}
+
+// MarshalJSON leaves out the parent id of root spans
+func (d SpanData) MarshalJSON() ([]byte, error) {
+	type spanData SpanData
+	var parentID string
+	if d.ParentID.IsValid() {
+		parentID = d.ParentID.String()
+	}
+	return json.Marshal(struct {
+		spanData
+		ParentID string `json:"parentId,omitempty"`
+	}{spanData: spanData(d), ParentID: parentID})
+}
+
+func setAttributes(data *SpanData, keyValues []string) {
+	if data.Attributes == nil {
+		data.Attributes = make(map[string]string, len(keyValues)/2)
+	}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		data.Attributes[keyValues[i]] = keyValues[i+1]
+	}
+}
+
+// IsValid reports whether the id is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// IsValid reports whether the id is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func newTraceID() (id TraceID) {
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
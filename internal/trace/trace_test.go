@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+)
+
+func TestTracer_Start(t *testing.T) {
+	exporter := NewMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	ctx, parent := tracer.Start(context.Background(), "parent", WithKind(KindServer))
+	_, child := Start(ctx, "child", WithAttributes("key", "value"))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	child.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	got, root := spans[0], spans[1]
+	if got.Name != "child" || got.Kind != KindInternal {
+		t.Errorf("child = %s (%s), want child (%s)", got.Name, got.Kind, KindInternal)
+	}
+	if got.TraceID != root.TraceID || got.ParentID != root.SpanID {
+		t.Errorf("child is not part of the parent trace: %v, %v", got, root)
+	}
+	if root.ParentID.IsValid() || root.Kind != KindServer {
+		t.Errorf("root = %v, want a server span without a parent", root)
+	}
+	if got.Attributes["key"] != "value" || got.Error != "boom" {
+		t.Errorf("child attributes = %v, error = %q", got.Attributes, got.Error)
+	}
+}
+
+func TestTracer_NotSampled(t *testing.T) {
+	exporter := NewMemoryExporter()
+	remote, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, span := NewTracer(exporter).Start(ContextWithRemoteSpanContext(context.Background(), remote), "ignored")
+	span.End()
+
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("exported %d spans of a trace that is not sampled", len(spans))
+	}
+	if sc, _ := SpanContextFromContext(ctx); sc.TraceID != remote.TraceID || sc.Sampled {
+		t.Errorf("span context = %v, want trace %v without sampling", sc, remote.TraceID)
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	_, span := NewTracer(NewStdoutExporter(&buf)).Start(context.Background(), "root")
+	span.End()
+
+	line := strings.TrimSpace(buf.String())
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("exported line %q is not JSON: %v", line, err)
+	}
+	if got["name"] != "root" || got["traceId"] != span.SpanContext().TraceID.String() {
+		t.Errorf("exported %v", got)
+	}
+	if _, ok := got["parentId"]; ok {
+		t.Errorf("root span exported a parentId: %v", got)
+	}
+}